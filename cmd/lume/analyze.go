@@ -0,0 +1,94 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/Tyooughtul/lume/pkg/scanner"
+	"github.com/Tyooughtul/lume/pkg/ui"
+)
+
+// analyzeItem is the JSON form of a scanner.DiskItem (DiskItem itself has a
+// Parent pointer and cannot be marshalled)
+type analyzeItem struct {
+	Path  string `json:"path"`
+	Size  int64  `json:"size"`
+	IsDir bool   `json:"is_dir"`
+}
+
+type analyzeReport struct {
+	Path  string        `json:"path"`
+	Size  int64         `json:"size"`
+	Items []analyzeItem `json:"items"`
+}
+
+// analyze runs DiskAnalyzer on path and prints the largest items, either as a
+// sorted list or as JSON
+func analyze(path string, top int, asJSON bool) int {
+	if top < 1 {
+		fmt.Fprintln(os.Stderr, "lume: -top must be at least 1")
+		return 2
+	}
+
+	root, err := filepath.Abs(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "lume: %v\n", err)
+		return 1
+	}
+
+	analyzer := scanner.NewDiskAnalyzer()
+	analyzer.SetMinSize(1024 * 1024) // 1MB, anything smaller is noise here
+
+	tree, err := analyzer.AnalyzePath(root, nil)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "lume: %v\n", err)
+		return 1
+	}
+
+	report := analyzeReport{Path: root, Size: tree.Size, Items: []analyzeItem{}}
+	// GetTopItems includes the root itself, ask for one extra and skip it
+	for _, item := range scanner.GetTopItems(tree, top+1) {
+		if item.Path == root {
+			continue
+		}
+		report.Items = append(report.Items, analyzeItem{Path: item.Path, Size: item.Size, IsDir: item.IsDir})
+	}
+	if len(report.Items) > top {
+		report.Items = report.Items[:top]
+	}
+
+	if asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(report); err != nil {
+			fmt.Fprintf(os.Stderr, "lume: %v\n", err)
+			return 1
+		}
+		return 0
+	}
+
+	fmt.Printf("%s%s%s  %s\n\n", colorBold, root, colorReset, ui.FormatBytes(uint64(report.Size)))
+	for _, item := range report.Items {
+		percent := 0.0
+		if report.Size > 0 {
+			percent = float64(item.Size) / float64(report.Size) * 100
+		}
+
+		name, err := filepath.Rel(root, item.Path)
+		if err != nil {
+			name = item.Path
+		}
+		if item.IsDir {
+			name += "/"
+		}
+
+		fmt.Printf("  %10s  %5.1f%%  %s\n", ui.FormatBytes(uint64(item.Size)), percent, name)
+	}
+	if len(report.Items) == 0 {
+		fmt.Println("  Nothing larger than 1 MB.")
+	}
+
+	return 0
+}
@@ -0,0 +1,12 @@
+package main
+
+import "testing"
+
+func TestAnalyzeRejectsTopBelowOne(t *testing.T) {
+	dir := t.TempDir()
+	for _, top := range []int{0, -1, -5} {
+		if code := analyze(dir, top, false); code != 2 {
+			t.Errorf("analyze(-top %d) = %d, want exit code 2", top, code)
+		}
+	}
+}
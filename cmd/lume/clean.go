@@ -0,0 +1,130 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Tyooughtul/lume/pkg/cleaner"
+	"github.com/Tyooughtul/lume/pkg/scanner"
+	"github.com/Tyooughtul/lume/pkg/ui"
+)
+
+// cleanSafe is the headless System Junk clean for cron and launchd: it moves
+// every low-risk target that is selected by default to the Trash, records a
+// history snapshot and prints a summary. The autoSelect setting is ignored so
+// a config change can never widen what an unattended job deletes; only an
+// explicit policy (-auto-select) can, and high-risk targets are never cleaned.
+// minAgeDays >= 0 replaces the config's age filter.
+func cleanSafe(policy string, minAgeDays int) int {
+	s := scanner.NewEnhancedJunkScanner()
+	s.SetAutoSelect(policy)
+	if minAgeDays >= 0 {
+		s.SetMinAge(minAgeDays)
+	}
+
+	maxRisk := scanner.RiskLow
+	if policy == scanner.AutoSelectAllSafe {
+		maxRisk = scanner.RiskMedium
+	}
+
+	targets, err := s.Scan(nil)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "lume: %v\n", err)
+		return 1
+	}
+
+	var selected []scanner.ScanTarget
+	for _, t := range targets {
+		if t.Selected && t.RiskLevel <= maxRisk {
+			selected = append(selected, t)
+		}
+	}
+	return trashTargets(selected)
+}
+
+// cleanTargets is the headless clean for exactly the System Junk targets
+// named in list (comma-separated, as shown by -diagnose). Naming a target is
+// taken as the decision to clean it, so risk level and autoSelect do not
+// apply; unknown names fail the run before anything is scanned.
+func cleanTargets(list string, minAgeDays int) int {
+	names := strings.Split(list, ",")
+	s := scanner.NewEnhancedJunkScanner()
+	if _, unknown := scanner.MatchTargets(s.BuildTargets(), names); len(unknown) > 0 {
+		fmt.Fprintf(os.Stderr, "lume: unknown targets: %s (lume -diagnose lists them)\n", strings.Join(unknown, ", "))
+		return 2
+	}
+	if minAgeDays >= 0 {
+		s.SetMinAge(minAgeDays)
+	}
+	s.SetOnly(names)
+
+	targets, err := s.Scan(nil)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "lume: %v\n", err)
+		return 1
+	}
+	return trashTargets(targets)
+}
+
+// trashTargets moves targets to the Trash, skipping empty ones, anything in
+// synced locations and anything modified within the last few minutes, then
+// records a history snapshot and prints a summary
+func trashTargets(targets []scanner.ScanTarget) int {
+	var selected []scanner.ScanTarget
+	var names []string
+	for _, t := range targets {
+		if t.SizeUnknown {
+			fmt.Fprintf(os.Stderr, "lume: skipping %s: it could not be read (grant Full Disk Access)\n", t.Name)
+			continue
+		}
+		if t.Reclaimable() == 0 {
+			continue
+		}
+		if t.Locked {
+			fmt.Fprintf(os.Stderr, "lume: skipping %s: it is locked (L in System Junk unlocks it)\n", t.Name)
+			continue
+		}
+		// Nobody is there to read the warning the TUI would show
+		if label := scanner.SyncedDataLabel(t.Path); label != "" {
+			fmt.Fprintf(os.Stderr, "lume: skipping %s: it is in %s\n", t.Name, label)
+			continue
+		}
+		// Something is writing to it, most likely an app that is running
+		if len(scanner.RecentlyModified([]string{t.Path}, scanner.RecentActivityWindow)) > 0 {
+			fmt.Fprintf(os.Stderr, "lume: skipping %s: it was modified in the last %d minutes\n", t.Name, int(scanner.RecentActivityWindow/time.Minute))
+			continue
+		}
+		t.Selected = true
+		selected = append(selected, t)
+		names = append(names, t.Name)
+	}
+
+	if len(selected) == 0 {
+		fmt.Println("Nothing to clean.")
+		return 0
+	}
+
+	for _, t := range selected {
+		fmt.Printf("  %-10s %s\n", ui.FormatBytes(uint64(t.Reclaimable())), t.Name)
+	}
+
+	size, cleanErr := cleaner.NewCleaner().CleanScanTargets(selected, nil)
+
+	if size > 0 {
+		details := strings.Join(names, ", ")
+		if len(names) > 3 {
+			details = fmt.Sprintf("%s, %s and %d more", names[0], names[1], len(names)-2)
+		}
+		// RecordSnapshot returns a tea.Cmd; run it inline to write the snapshot
+		ui.RecordSnapshot(0, 0, size, "system_junk", details)()
+	}
+
+	fmt.Printf("Moved %s from %d targets to Trash\n", ui.FormatBytes(uint64(size)), len(selected))
+	if cleanErr != nil {
+		fmt.Fprintf(os.Stderr, "lume: %v\n", cleanErr)
+		return 1
+	}
+	return 0
+}
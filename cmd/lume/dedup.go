@@ -0,0 +1,86 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Tyooughtul/lume/pkg/scanner"
+)
+
+// dedup scans for duplicate files without the TUI and prints one line per file:
+//
+//	<group>\t<size bytes>\t<sha256>\t<path>
+//
+// When root is "-" the directories to scan are read from stdin, one per line,
+// so lume can be composed with find/fd in shell pipelines.
+func dedup(root string) int {
+	var roots []string
+	if root == "-" {
+		var err error
+		roots, err = readRoots(os.Stdin)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "lume: reading stdin: %v\n", err)
+			return 1
+		}
+	} else {
+		roots = []string{filepath.Clean(root)}
+	}
+
+	roots = validRoots(roots)
+	if len(roots) == 0 {
+		fmt.Fprintln(os.Stderr, "lume: no directories to scan")
+		return 1
+	}
+
+	groups, err := scanner.NewDuplicateScannerWithRoots(roots).Scan(nil)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "lume: %v\n", err)
+		return 1
+	}
+
+	w := bufio.NewWriter(os.Stdout)
+	defer w.Flush()
+	for i, group := range groups {
+		for _, file := range group.Files {
+			fmt.Fprintf(w, "%d\t%d\t%s\t%s\n", i+1, file.Size, group.Hash, file.Path)
+		}
+	}
+
+	return 0
+}
+
+// readRoots reads newline-separated paths, ignoring blank lines
+func readRoots(r io.Reader) ([]string, error) {
+	var roots []string
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" {
+			continue
+		}
+		roots = append(roots, filepath.Clean(line))
+	}
+	return roots, sc.Err()
+}
+
+// validRoots drops (with a warning on stderr) anything that is not a directory
+func validRoots(roots []string) []string {
+	var valid []string
+	for _, root := range roots {
+		info, err := os.Stat(root)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "lume: skipping %s: %v\n", root, err)
+			continue
+		}
+		if !info.IsDir() {
+			fmt.Fprintf(os.Stderr, "lume: skipping %s: not a directory\n", root)
+			continue
+		}
+		valid = append(valid, root)
+	}
+	return valid
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"os/exec"
@@ -9,12 +10,12 @@ import (
 	"strconv"
 	"strings"
 
-	"github.com/dustin/go-humanize"
 	"github.com/Tyooughtul/lume/pkg/scanner"
+	"github.com/Tyooughtul/lume/pkg/ui"
 )
 
-// ANSI color helpers for diagnose output
-const (
+// ANSI color helpers for diagnose output, emptied by plainOutput
+var (
 	colorReset  = "\033[0m"
 	colorRed    = "\033[31m"
 	colorGreen  = "\033[32m"
@@ -24,6 +25,18 @@ const (
 	colorBold   = "\033[1m"
 )
 
+// plainOutput turns off the ANSI escapes in the text reports
+func plainOutput() {
+	colorReset, colorRed, colorGreen, colorYellow = "", "", "", ""
+	colorCyan, colorDim, colorBold = "", "", ""
+}
+
+// isTerminal reports whether f is a terminal rather than a file or pipe
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
 // sizeTag returns a colored severity indicator for a given size
 func sizeTag(size int64, canClean bool) string {
 	if !canClean {
@@ -60,7 +73,7 @@ func diagnose() {
 		{"Developer", filepath.Join(homeDir, "Library", "Developer")},
 		{"Logs", filepath.Join(homeDir, "Library", "Logs")},
 		{"Downloads", filepath.Join(homeDir, "Downloads")},
-		{"Trash", filepath.Join(homeDir, ".Trash")},
+		{"Trash", scanner.CurrentPlatform.TrashDir(homeDir)},
 	}
 
 	fmt.Println("┌─────────────────────────────────────────────────────────────┐")
@@ -97,7 +110,7 @@ func diagnose() {
 
 	for _, r := range results {
 		tag := sizeTag(r.size, true)
-		sizeStr := humanize.Bytes(uint64(r.size))
+		sizeStr := ui.FormatBytes(uint64(r.size))
 		// pad manually: tag has ANSI codes, so use fixed field for the visible part
 		visible := fmt.Sprintf("%s %s", tag, sizeStr)
 		// ANSI codes don't take visual space; right-align within 17 char column
@@ -161,7 +174,7 @@ func diagnose() {
 		}
 
 		tag := sizeTag(target.Size, true)
-		sizeStr := humanize.Bytes(uint64(target.Size))
+		sizeStr := ui.FormatBytes(uint64(target.Size))
 		pad := 17 - (3 + 1 + len(sizeStr))
 		if pad < 0 {
 			pad = 0
@@ -180,7 +193,7 @@ func diagnose() {
 	}
 
 	fmt.Println("═════════════════════════════════════════════════════════════")
-	fmt.Printf("[Total] Reclaimable space: %s\n", humanize.Bytes(uint64(totalJunk)))
+	fmt.Printf("[Total] Reclaimable space: %s\n", ui.FormatBytes(uint64(totalJunk)))
 	fmt.Println("═════════════════════════════════════════════════════════════")
 	fmt.Println()
 
@@ -209,7 +222,10 @@ func diagnose() {
 			}
 
 			tag := sizeTag(item.Size, item.CanClean)
-			sizeStr := humanize.Bytes(uint64(item.Size))
+			sizeStr := ui.FormatBytes(uint64(item.Size))
+			if item.SizeUnknown {
+				sizeStr = "unavailable"
+			}
 			pad := 17 - (3 + 1 + len(sizeStr))
 			if pad < 0 {
 				pad = 0
@@ -223,8 +239,20 @@ func diagnose() {
 
 		totalSystem := systemScanner.GetTotalSize()
 		cleanableSystem := systemScanner.GetCleanableSize()
-		fmt.Printf("[Total] System Data: %s\n", humanize.Bytes(uint64(totalSystem)))
-		fmt.Printf("[OK] Cleanable System Data: %s\n", humanize.Bytes(uint64(cleanableSystem)))
+		fmt.Printf("[Total] System Data: %s\n", ui.FormatBytes(uint64(totalSystem)))
+		fmt.Printf("[OK] Cleanable System Data: %s\n", ui.FormatBytes(uint64(cleanableSystem)))
+		fmt.Println()
+	}
+
+	if errs := systemScanner.GetErrors(); len(errs) > 0 {
+		fmt.Printf("[!] %d System Data locations could not be measured:\n", len(errs))
+		for i, err := range errs {
+			if i >= 5 {
+				fmt.Printf("  ... and %d more\n", len(errs)-5)
+				break
+			}
+			fmt.Printf("  - %s\n", err)
+		}
 		fmt.Println()
 	}
 
@@ -241,25 +269,218 @@ func diagnose() {
 		fmt.Println()
 	}
 
-	// 6. Tips
+	// 6. Lume's own footprint
+	usage := scanner.GetLumeDataUsage()
+	if usage.TotalSize > 0 {
+		fmt.Printf("[Lume] %s uses %s (caches: %s, clear with 'lume -clear-cache')\n",
+			usage.Path, ui.FormatBytes(uint64(usage.TotalSize)), ui.FormatBytes(uint64(usage.CacheSize)))
+		fmt.Println()
+	}
+
+	// 7. Tips
 	fmt.Println("[Tips]:")
 	fmt.Println("  1. If some directories show 'No access', try running with sudo")
 	fmt.Printf("  2. For %s%s[!]%s large directories, use TUI mode to view details\n", colorRed, colorBold, colorReset)
 	fmt.Println("  3. Docker data is usually in ~/Library/Containers/com.docker.docker")
 	fmt.Println("  4. Xcode cache can be very large, DerivedData is safe to clean")
 	fmt.Printf("  5. %s[L]%s items are system data that cannot be safely cleaned\n", colorDim, colorReset)
-	fmt.Println("  6. Time Machine snapshots are automatically managed by macOS")
+	fmt.Println("  6. Time Machine snapshots are managed by macOS; delete them with 'lume -delete-snapshots'")
 	fmt.Println("  7. System swap files are automatically managed by the OS")
 	fmt.Println()
 }
 
+// diagnosePathLargeDirs is how many of the largest directories below the
+// path diagnosePathMode lists
+const diagnosePathLargeDirs = 10
+
+// diagnosePathMode is -diagnose -path: the directory analysis and a scan
+// for the largest directories, run on path instead of the home folder. The
+// junk and System Data sections are left out, as they are about this Mac
+// rather than the path.
+func diagnosePathMode(path string) int {
+	root, err := filepath.Abs(path)
+	if err == nil {
+		var info os.FileInfo
+		if info, err = os.Stat(root); err == nil && !info.IsDir() {
+			err = fmt.Errorf("%s is not a directory", root)
+		}
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "lume: %v\n", err)
+		return 1
+	}
+
+	fmt.Println("╔════════════════════════════════════════════════════════════╗")
+	fmt.Println("║             Lume - Disk Space Diagnostic Tool               ║")
+	fmt.Println("╚════════════════════════════════════════════════════════════╝")
+	fmt.Println()
+
+	if d, err := readVolume(root); err == nil && d.total > 0 {
+		fmt.Printf("[Volume] %s used of %s, %s free\n",
+			ui.FormatBytes(d.used), ui.FormatBytes(d.total), ui.FormatBytes(d.total-d.used))
+		fmt.Println()
+	}
+
+	// 1. Top-level entries of path
+	fmt.Printf("[*] Analyzing %s...\n", root)
+	fmt.Println()
+
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "lume: %v\n", err)
+		return 1
+	}
+
+	type entrySize struct {
+		name string
+		size int64
+	}
+	var results []entrySize
+	var denied []string
+	for _, entry := range entries {
+		full := filepath.Join(root, entry.Name())
+		var size int64
+		if entry.IsDir() {
+			size = getDirSizeDU(full)
+		} else if info, err := entry.Info(); err == nil {
+			size = info.Size()
+		}
+		if size < 0 {
+			denied = append(denied, entry.Name())
+			continue
+		}
+		if size > 0 {
+			results = append(results, entrySize{entry.Name(), size})
+		}
+	}
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].size > results[j].size
+	})
+	var total int64
+	for _, r := range results {
+		total += r.size
+	}
+
+	fmt.Println("┌─────────────────────────────────────────────────────────────┐")
+	fmt.Println("│ Directory Analysis Results                                  │")
+	fmt.Println("├─────────────────────────────────────────┬───────────────────┤")
+	fmt.Println("│ Entry                                   │ Size              │")
+	fmt.Println("├─────────────────────────────────────────┼───────────────────┤")
+	for i, r := range results {
+		if i >= 15 {
+			fmt.Printf("│ ... %d more items                       │                   │\n", len(results)-15)
+			break
+		}
+		printSizeRow(r.name, r.size)
+	}
+	for _, name := range denied {
+		fmt.Printf("│ %-39s │ %17s │\n", truncateName(name), "No access")
+	}
+	fmt.Println("└─────────────────────────────────────────┴───────────────────┘")
+	fmt.Println()
+
+	// 2. Largest directories anywhere below path
+	fmt.Println("[*] Looking for the largest directories...")
+	fmt.Println()
+
+	analyzer := scanner.NewDiskAnalyzer()
+	analyzer.SetMinSize(100 * 1024 * 1024)
+	tree, err := analyzer.AnalyzePath(root, nil)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "lume: %v\n", err)
+		return 1
+	}
+
+	fmt.Println("┌─────────────────────────────────────────────────────────────┐")
+	fmt.Println("│ Largest Directories                                         │")
+	fmt.Println("├─────────────────────────────────────────┬───────────────────┤")
+	fmt.Println("│ Directory                               │ Size              │")
+	fmt.Println("├─────────────────────────────────────────┼───────────────────┤")
+	shown := 0
+	for _, item := range scanner.GetTopItems(tree, 100) {
+		if !item.IsDir || item.Path == root {
+			continue
+		}
+		name, err := filepath.Rel(root, item.Path)
+		if err != nil {
+			name = item.Path
+		}
+		printSizeRow(name+"/", item.Size)
+		if shown++; shown == diagnosePathLargeDirs {
+			break
+		}
+	}
+	if shown == 0 {
+		fmt.Printf("│ %-39s │ %17s │\n", "Nothing over 100 MB", "")
+	}
+	fmt.Println("└─────────────────────────────────────────┴───────────────────┘")
+	fmt.Println()
+
+	fmt.Println("═════════════════════════════════════════════════════════════")
+	fmt.Printf("[Total] %s: %s\n", root, ui.FormatBytes(uint64(total)))
+	fmt.Println("═════════════════════════════════════════════════════════════")
+	fmt.Println()
+
+	fmt.Println("[Tips]:")
+	fmt.Printf("  1. Explore further with 'lume -analyze %s'\n", root)
+	fmt.Println("  2. Entries showing 'No access' usually need Full Disk Access or sudo")
+	fmt.Println()
+	return 0
+}
+
+// printSizeRow prints one row of a two-column diagnose table
+func printSizeRow(name string, size int64) {
+	tag := sizeTag(size, true)
+	sizeStr := ui.FormatBytes(uint64(size))
+	pad := 17 - (3 + 1 + len(sizeStr))
+	if pad < 0 {
+		pad = 0
+	}
+	visible := fmt.Sprintf("%s %s", tag, sizeStr)
+	fmt.Printf("│ %-39s │ %s%s │\n", truncateName(name), strings.Repeat(" ", pad), visible)
+}
+
+// truncateName shortens name to the 39 columns of a diagnose table
+func truncateName(name string) string {
+	if len(name) > 39 {
+		return name[:36] + "..."
+	}
+	return name
+}
+
+// volumeUsage is the size and usage of a volume, in bytes
+type volumeUsage struct {
+	total, used uint64
+}
+
+// readVolume reads the size and usage of the volume holding path
+func readVolume(path string) (volumeUsage, error) {
+	var d volumeUsage
+	out, err := exec.Command("df", "-k", "--", path).Output()
+	if err != nil {
+		return d, err
+	}
+	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+	fields := strings.Fields(lines[len(lines)-1])
+	if len(fields) < 3 {
+		return d, fmt.Errorf("unexpected df output")
+	}
+	total, err1 := strconv.ParseUint(fields[1], 10, 64)
+	used, err2 := strconv.ParseUint(fields[2], 10, 64)
+	if err1 != nil || err2 != nil {
+		return d, fmt.Errorf("unexpected df output")
+	}
+	d.total, d.used = total*1024, used*1024
+	return d, nil
+}
+
 // getDirSizeDU uses du command for fast size calculation
 func getDirSizeDU(path string) int64 {
 	if strings.Contains(path, "com.docker.docker") {
 		return getDockerSize(path)
 	}
 
-	cmd := exec.Command("du", "-sk", path)
+	cmd := scanner.ScanCommand(context.Background(), "du", "-sk", "--", path)
 	output, err := cmd.Output()
 	if err != nil {
 		return -1
@@ -284,7 +505,7 @@ func getDockerSize(path string) int64 {
 
 	info, err := os.Stat(dataPath)
 	if err == nil {
-		cmd := exec.Command("du", "-k", dataPath)
+		cmd := scanner.ScanCommand(context.Background(), "du", "-k", "--", dataPath)
 		output, err := cmd.Output()
 		if err == nil {
 			fields := strings.Fields(string(output))
@@ -296,7 +517,7 @@ func getDockerSize(path string) int64 {
 		return info.Size()
 	}
 
-	cmd := exec.Command("du", "-sk", path)
+	cmd := scanner.ScanCommand(context.Background(), "du", "-sk", "--", path)
 	output, err := cmd.Output()
 	if err != nil {
 		return -1
@@ -308,3 +529,14 @@ func getDockerSize(path string) int64 {
 	sizeKB, _ := strconv.ParseInt(fields[0], 10, 64)
 	return sizeKB * 1024
 }
+
+// clearLumeCaches removes lume's own caches and reports what was freed
+func clearLumeCaches() int {
+	freed, err := scanner.ClearLumeCaches()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "lume: %v\n", err)
+		return 1
+	}
+	fmt.Printf("Cleared %s of lume caches\n", ui.FormatBytes(uint64(freed)))
+	return 0
+}
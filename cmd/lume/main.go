@@ -4,10 +4,12 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/charmbracelet/bubbletea"
 	"github.com/mattn/go-runewidth"
 
+	"github.com/Tyooughtul/lume/pkg/scanner"
 	"github.com/Tyooughtul/lume/pkg/ui"
 )
 
@@ -23,14 +25,42 @@ func init() {
 func main() {
 	// Initialize theme manager
 	ui.InitThemeManager()
+	ui.SetBinaryUnits(scanner.LoadConfig().BinaryUnits)
+	ui.LoadKeymap()
+	for _, w := range ui.GlobalThemeManager.Warnings {
+		fmt.Fprintf(os.Stderr, "lume: %s\n", w)
+	}
 
 	diagnoseMode := flag.Bool("diagnose", false, "Run diagnostic mode (no TUI)")
-	versionMode := flag.Bool("version", false, "Show version information")
+	diagnosePath := flag.String("path", "", "Directory or volume for -diagnose to analyze instead of the home folder")
+	versionMode := flag.Bool("version", false, "Show version and build information")
 	helpMode := flag.Bool("help", false, "Show help information")
+	dedupRoot := flag.String("dedup", "", "Find duplicate files under a directory ('-' reads directories from stdin)")
+	analyzePath := flag.String("analyze", "", "Show the largest items under a directory (no TUI)")
+	analyzeTop := flag.Int("top", 20, "Number of items to show with -analyze")
+	jsonOutput := flag.Bool("json", false, "Print -analyze results as JSON")
+	reportMode := flag.Bool("report", false, "Scan everything and write a Markdown report to ~/lume-report-<date>.md")
+	clearCache := flag.Bool("clear-cache", false, "Delete lume's own caches in ~/.config/lume")
+	cleanSafeMode := flag.Bool("clean-safe", false, "Move default-selected low-risk System Junk to Trash (no TUI)")
+	cleanMode := flag.Bool("clean", false, "Move the System Junk targets named by -targets to Trash (no TUI)")
+	targetList := flag.String("targets", "", "Comma-separated System Junk target names for -clean")
+	deleteSnapshotsMode := flag.Bool("delete-snapshots", false, "Delete Time Machine local snapshots after a typed confirmation")
+	autoSelect := flag.String("auto-select", "", "Pre-select System Junk by risk: none, low-risk or all-safe (overrides config)")
+	noColor := flag.Bool("no-color", false, "Disable colored output (also set by the NO_COLOR environment variable)")
+	olderThan := flag.Int("older-than", -1, "Only count and clean System Junk files not modified for N days (overrides config)")
+	lowPriority := flag.Bool("low-priority", false, "Scan under nice with fewer workers so the machine stays responsive (also set by lowPriority in config)")
 	flag.Parse()
 
+	colorOff := *noColor || os.Getenv("NO_COLOR") != ""
+	if colorOff || !isTerminal(os.Stdout) {
+		plainOutput()
+	}
+	if colorOff {
+		ui.DisableColor()
+	}
+
 	if *versionMode {
-		fmt.Printf("Lume %s\n", ui.AppVersion)
+		printVersion()
 		os.Exit(0)
 	}
 
@@ -40,6 +70,19 @@ func main() {
 		fmt.Println("Usage:")
 		fmt.Println("  lume              Start TUI interface")
 		fmt.Println("  lume -diagnose    Run diagnostic mode")
+		fmt.Println("  lume -diagnose -path DIR  Diagnose DIR (e.g. an external drive) instead of your home folder")
+		fmt.Println("  lume -dedup DIR   Print duplicate files under DIR (tab-separated)")
+		fmt.Println("  lume -dedup -     Read directories to scan from stdin")
+		fmt.Println("  lume -analyze DIR Show the largest items under DIR (-top N, -json)")
+		fmt.Println("  lume -report      Scan everything and write ~/lume-report-<date>.md (cleans nothing)")
+		fmt.Println("  lume -clear-cache Delete lume's own caches (history and themes are kept)")
+		fmt.Println("  lume -clean-safe  Move default-selected low-risk junk to Trash (for cron/launchd)")
+		fmt.Println("  lume -clean -targets \"A,B\"  Move exactly the named System Junk targets to Trash")
+		fmt.Println("  lume -delete-snapshots  Delete Time Machine local snapshots (asks first)")
+		fmt.Println("  -auto-select P    Pre-select System Junk by risk: none, low-risk, all-safe")
+		fmt.Println("  -older-than N     Only clean System Junk files not modified for N days")
+		fmt.Println("  -low-priority     Scan in the background: nice, fewer workers, slower")
+		fmt.Println("  -no-color         Disable colors (or set NO_COLOR); reports are plain when piped")
 		fmt.Println("  lume -version     Show version")
 		fmt.Println("  lume -help        Show help")
 		fmt.Println()
@@ -56,7 +99,62 @@ func main() {
 		os.Exit(0)
 	}
 
+	if *clearCache {
+		os.Exit(clearLumeCaches())
+	}
+
+	if *autoSelect != "" && !scanner.IsAutoSelectPolicy(*autoSelect) {
+		fmt.Fprintf(os.Stderr, "lume: -auto-select must be %s, %s or %s\n",
+			scanner.AutoSelectNone, scanner.AutoSelectLowRisk, scanner.AutoSelectAllSafe)
+		os.Exit(2)
+	}
+	ui.SetAutoSelect(*autoSelect)
+	if *olderThan < -1 {
+		fmt.Fprintln(os.Stderr, "lume: -older-than must be a number of days")
+		os.Exit(2)
+	}
+	ui.SetMinAge(*olderThan)
+	if *lowPriority {
+		scanner.SetLowPriority(true)
+	}
+
+	if *deleteSnapshotsMode {
+		os.Exit(deleteSnapshots())
+	}
+
+	if *cleanSafeMode {
+		os.Exit(cleanSafe(*autoSelect, *olderThan))
+	}
+
+	if *cleanMode || *targetList != "" {
+		if !*cleanMode || strings.TrimSpace(strings.ReplaceAll(*targetList, ",", "")) == "" {
+			fmt.Fprintln(os.Stderr, "lume: -clean and -targets go together, e.g. lume -clean -targets \"npm Cache,Xcode DerivedData\"")
+			os.Exit(2)
+		}
+		os.Exit(cleanTargets(*targetList, *olderThan))
+	}
+
+	if *reportMode {
+		os.Exit(report())
+	}
+
+	if *analyzePath != "" {
+		os.Exit(analyze(*analyzePath, *analyzeTop, *jsonOutput))
+	}
+
+	if *dedupRoot != "" {
+		os.Exit(dedup(*dedupRoot))
+	}
+
+	if *diagnosePath != "" && !*diagnoseMode {
+		fmt.Fprintln(os.Stderr, "lume: -path goes with -diagnose, e.g. lume -diagnose -path /Volumes/External")
+		os.Exit(2)
+	}
+
 	if *diagnoseMode {
+		if *diagnosePath != "" {
+			os.Exit(diagnosePathMode(*diagnosePath))
+		}
 		diagnose()
 		os.Exit(0)
 	}
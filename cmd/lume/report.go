@@ -0,0 +1,228 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/Tyooughtul/lume/pkg/scanner"
+	"github.com/Tyooughtul/lume/pkg/ui"
+)
+
+// reportTop is how many rows each table of the report lists
+const reportTop = 20
+
+// reportLargeFileSize is the Large Files threshold, the same as the TUI's
+const reportLargeFileSize = 50 * 1024 * 1024
+
+// report runs the System Junk, System Data, Large Files and Duplicates scans
+// and writes a Markdown summary to ~/lume-report-<date>.md, for a record of
+// the machine before a big cleanup. Nothing is cleaned.
+func report() int {
+	home := scanner.GetRealHomeDir()
+	now := time.Now()
+
+	var b strings.Builder
+	host, _ := os.Hostname()
+	fmt.Fprintf(&b, "# Lume report: %s\n\n", host)
+	fmt.Fprintf(&b, "Generated %s by %s %s.\n\n", now.Format("2006-01-02 15:04"), ui.AppName, ui.AppVersion)
+	reportDisk(&b, home)
+
+	fmt.Fprintln(os.Stderr, "Scanning System Junk...")
+	reportJunk(&b)
+
+	fmt.Fprintln(os.Stderr, "Scanning System Data...")
+	reportSystemData(&b)
+
+	fmt.Fprintln(os.Stderr, "Scanning for large files...")
+	reportLargeFiles(&b, home)
+
+	fmt.Fprintln(os.Stderr, "Scanning for duplicates (this can take a while)...")
+	reportDuplicates(&b, home)
+
+	path := filepath.Join(home, fmt.Sprintf("lume-report-%s.md", now.Format("2006-01-02")))
+	if err := os.WriteFile(path, []byte(b.String()), 0644); err != nil {
+		fmt.Fprintf(os.Stderr, "lume: %v\n", err)
+		return 1
+	}
+	fmt.Printf("Report written to %s\n", path)
+	return 0
+}
+
+// reportDisk writes the size and free space of the volume holding home
+func reportDisk(b *strings.Builder, home string) {
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(home, &st); err != nil {
+		return
+	}
+	total := st.Blocks * uint64(st.Bsize)
+	free := st.Bavail * uint64(st.Bsize)
+	if total == 0 {
+		return
+	}
+	fmt.Fprintf(b, "**Disk:** %s used of %s, %s free (%.0f%% used)\n\n",
+		ui.FormatBytes(total-free), ui.FormatBytes(total), ui.FormatBytes(free),
+		float64(total-free)/float64(total)*100)
+}
+
+func reportJunk(b *strings.Builder) {
+	s := scanner.NewEnhancedJunkScanner()
+	targets, err := s.Scan(nil)
+
+	b.WriteString("## System Junk\n\n")
+	if err != nil {
+		fmt.Fprintf(b, "Scan failed: %v\n\n", err)
+		return
+	}
+
+	var found []scanner.ScanTarget
+	var total int64
+	for _, t := range targets {
+		if t.Reclaimable() > 0 {
+			found = append(found, t)
+			total += t.Reclaimable()
+		}
+	}
+	sort.Slice(found, func(i, j int) bool {
+		return found[i].Reclaimable() > found[j].Reclaimable()
+	})
+
+	fmt.Fprintf(b, "**Total:** %s in %d targets\n\n", ui.FormatBytes(uint64(total)), len(found))
+	if len(found) > 0 {
+		b.WriteString("| Target | Size | Risk | Path |\n| :--- | ---: | :--- | :--- |\n")
+		for i, t := range found {
+			if i == reportTop {
+				fmt.Fprintf(b, "| ... %d more | | | |\n", len(found)-reportTop)
+				break
+			}
+			fmt.Fprintf(b, "| %s | %s | %s | %s |\n", mdCell(t.Name), ui.FormatBytes(uint64(t.Reclaimable())), t.RiskLevel, mdCell(t.Path))
+		}
+		b.WriteString("\n")
+	}
+	reportWarnings(b, s.GetErrors())
+}
+
+func reportSystemData(b *strings.Builder) {
+	s := scanner.NewSystemDataScanner()
+	items, err := s.Scan()
+
+	b.WriteString("## System Data\n\n")
+	if err != nil {
+		fmt.Fprintf(b, "Scan failed: %v\n\n", err)
+		return
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].Size > items[j].Size
+	})
+
+	fmt.Fprintf(b, "**Total:** %s, of which %s can be cleaned\n\n",
+		ui.FormatBytes(uint64(s.GetTotalSize())), ui.FormatBytes(uint64(s.GetCleanableSize())))
+	if len(items) > 0 {
+		b.WriteString("| Item | Size | Cleanable |\n| :--- | ---: | :--- |\n")
+		for i, item := range items {
+			if i == reportTop {
+				fmt.Fprintf(b, "| ... %d more | | |\n", len(items)-reportTop)
+				break
+			}
+			size := ui.FormatBytes(uint64(item.Size))
+			if item.SizeUnknown {
+				size = "unavailable"
+			}
+			cleanable := "no"
+			if item.CanClean {
+				cleanable = "yes"
+			}
+			fmt.Fprintf(b, "| %s | %s | %s |\n", mdCell(item.Name), size, cleanable)
+		}
+		b.WriteString("\n")
+	}
+	reportWarnings(b, s.GetErrors())
+}
+
+func reportLargeFiles(b *strings.Builder, home string) {
+	s := scanner.NewLargeFileScanner(home)
+	s.SetMinSize(reportLargeFileSize)
+	files, err := s.Scan(nil)
+
+	fmt.Fprintf(b, "## Large Files (over %s)\n\n", ui.FormatBytes(reportLargeFileSize))
+	if err != nil {
+		fmt.Fprintf(b, "Scan failed: %v\n\n", err)
+		return
+	}
+
+	var total int64
+	for _, f := range files {
+		total += f.Size
+	}
+	scanner.SortBySize(files)
+
+	fmt.Fprintf(b, "**Total:** %s in %d files\n\n", ui.FormatBytes(uint64(total)), len(files))
+	if len(files) > 0 {
+		b.WriteString("| File | Size | Modified |\n| :--- | ---: | :--- |\n")
+		for i, f := range files {
+			if i == reportTop {
+				fmt.Fprintf(b, "| ... %d more | | |\n", len(files)-reportTop)
+				break
+			}
+			fmt.Fprintf(b, "| %s | %s | %s |\n", mdCell(f.Path), ui.FormatBytes(uint64(f.Size)), f.Modified.Format("2006-01-02"))
+		}
+		b.WriteString("\n")
+	}
+}
+
+func reportDuplicates(b *strings.Builder, home string) {
+	s := scanner.NewDuplicateScanner(home)
+	s.SetMaxDepth(10) // as in the TUI, so node_modules-style trees don't dominate
+	groups, err := s.Scan(nil)
+
+	b.WriteString("## Duplicates\n\n")
+	if err != nil {
+		fmt.Fprintf(b, "Scan failed: %v\n\n", err)
+		return
+	}
+
+	fmt.Fprintf(b, "**Reclaimable:** %s in %d groups\n\n", ui.FormatBytes(uint64(scanner.GetDuplicateTotalSize(groups))), len(groups))
+	if len(groups) > 0 {
+		b.WriteString("| File | Copies | Reclaimable | Locations |\n| :--- | ---: | ---: | :--- |\n")
+		for i, g := range groups {
+			if i == reportTop {
+				fmt.Fprintf(b, "| ... %d more | | | |\n", len(groups)-reportTop)
+				break
+			}
+			var paths []string
+			for _, f := range g.Files {
+				paths = append(paths, mdCell(f.Path))
+			}
+			fmt.Fprintf(b, "| %s | %d | %s | %s |\n", mdCell(g.Files[0].Name), len(g.Files),
+				ui.FormatBytes(uint64(g.Reclaimable())), strings.Join(paths, "<br>"))
+		}
+		b.WriteString("\n")
+	}
+}
+
+// reportWarnings lists locations a scan could not read, if any
+func reportWarnings(b *strings.Builder, errs []string) {
+	if len(errs) == 0 {
+		return
+	}
+	fmt.Fprintf(b, "%d locations could not be read (usually missing Full Disk Access):\n\n", len(errs))
+	for i, e := range errs {
+		if i == 5 {
+			fmt.Fprintf(b, "- ... and %d more\n", len(errs)-5)
+			break
+		}
+		fmt.Fprintf(b, "- %s\n", e)
+	}
+	b.WriteString("\n")
+}
+
+// mdCell makes s safe inside a Markdown table cell
+func mdCell(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.ReplaceAll(s, "\n", " ")
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/Tyooughtul/lume/pkg/cleaner"
+	"github.com/Tyooughtul/lume/pkg/scanner"
+	"github.com/Tyooughtul/lume/pkg/ui"
+)
+
+// deleteSnapshots lists the local APFS snapshots and, after the user types
+// "delete", removes the Time Machine ones with tmutil. Snapshots do not go to
+// the Trash, hence the typed confirmation instead of y/n.
+func deleteSnapshots() int {
+	names, size, purgeable := scanner.LocalSnapshots()
+	if len(names) == 0 {
+		fmt.Println("No local snapshots found.")
+		return 0
+	}
+
+	fmt.Printf("%d local snapshots:\n", len(names))
+	for _, name := range names {
+		fmt.Printf("  %s\n", name)
+	}
+	if size > 0 {
+		fmt.Printf("Reported size: %s\n", ui.FormatBytes(uint64(size)))
+	} else {
+		fmt.Println("Size unavailable: snapshots share blocks with your files, deleting them may free little.")
+	}
+	if purgeable {
+		fmt.Println("All snapshots are purgeable: macOS deletes them by itself when space runs low.")
+	}
+	fmt.Println()
+	fmt.Println("Deleting snapshots is permanent. They do not go to the Trash and")
+	fmt.Println("Time Machine can no longer restore from them.")
+	fmt.Print("Type 'delete' to continue: ")
+
+	line, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	if strings.TrimSpace(line) != "delete" {
+		fmt.Println("Aborted.")
+		return 1
+	}
+
+	deleted, err := cleaner.NewCleaner().DeleteLocalSnapshots(names, nil)
+	fmt.Printf("Deleted %d of %d snapshots\n", deleted, len(names))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "lume: %v\n", err)
+		return 1
+	}
+	return 0
+}
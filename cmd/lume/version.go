@@ -0,0 +1,53 @@
+package main
+
+import (
+	"fmt"
+	"runtime"
+	"runtime/debug"
+
+	"github.com/Tyooughtul/lume/pkg/ui"
+)
+
+// printVersion prints the version plus the build metadata that helps triage
+// bug reports. VCS details are only present in binaries built from a git
+// checkout with module support (go build / go install, not go run).
+func printVersion() {
+	fmt.Printf("%s %s\n", ui.AppName, ui.AppVersion)
+	fmt.Printf("  Go:       %s\n", runtime.Version())
+	fmt.Printf("  Platform: %s/%s\n", runtime.GOOS, runtime.GOARCH)
+
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return
+	}
+
+	var revision, commitTime string
+	modified := false
+	for _, s := range info.Settings {
+		switch s.Key {
+		case "vcs.revision":
+			revision = s.Value
+		case "vcs.time":
+			commitTime = s.Value
+		case "vcs.modified":
+			modified = s.Value == "true"
+		}
+	}
+	if revision == "" {
+		// go install module@version records the module version instead
+		if v := info.Main.Version; v != "" && v != "(devel)" && v != ui.AppVersion {
+			fmt.Printf("  Module:   %s\n", v)
+		}
+		return
+	}
+	if len(revision) > 12 {
+		revision = revision[:12]
+	}
+	if commitTime != "" {
+		revision += " " + commitTime
+	}
+	if modified {
+		revision += " (modified)"
+	}
+	fmt.Printf("  Commit:   %s\n", revision)
+}
@@ -1,38 +1,153 @@
 package cleaner
 
 import (
+	"context"
 	"fmt"
 	"io"
+	"io/fs"
+	"net/url"
 	"os"
 	"os/exec"
 	"path/filepath"
-	"sort"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/Tyooughtul/lume/pkg/scanner"
 )
 
+// SystemRoots are the locations the cleaner refuses to touch unless the
+// config sets allowSystemPaths. Several System Data items live here, and
+// trashing one of them by mistake can leave macOS unbootable.
+var SystemRoots = []string{
+	"/System",
+	"/Library",
+	"/private",
+	"/var",
+	"/etc",
+	"/tmp",
+	"/usr",
+	"/bin",
+	"/sbin",
+	"/cores",
+}
+
 // Cleaner handles file cleanup operations
 type Cleaner struct {
-	trashPath string
+	trashPath   string
+	denyPaths   []string // extra roots from the config's denyPaths
+	allowSystem bool
+	launchItems bool // allow the plists of isSystemLaunchItem, see withLaunchItems
 }
 
 // NewCleaner creates a new Cleaner instance
 func NewCleaner() *Cleaner {
 	homeDir := scanner.GetRealHomeDir()
+	cfg := scanner.LoadConfig()
+
+	var denyPaths []string
+	for _, p := range cfg.DenyPaths {
+		if p == "~" || strings.HasPrefix(p, "~/") {
+			p = filepath.Join(homeDir, strings.TrimPrefix(p, "~"))
+		}
+		if filepath.IsAbs(p) {
+			denyPaths = append(denyPaths, filepath.Clean(p))
+		}
+	}
+
 	return &Cleaner{
-		trashPath: filepath.Join(homeDir, ".Trash"),
+		trashPath:   scanner.CurrentPlatform.TrashDir(homeDir),
+		denyPaths:   denyPaths,
+		allowSystem: cfg.AllowSystemPaths,
+	}
+}
+
+// checkAllowed returns an error if path lies in one of the deny roots. The
+// user's own temporary directory ($TMPDIR) is always allowed. Paths added
+// through denyPaths are refused even with allowSystemPaths set.
+func (c *Cleaner) checkAllowed(path string) error {
+	path = filepath.Clean(path)
+	// Resolve symlinked parents so /tmp/x is seen as /private/tmp/x
+	if dir, err := filepath.EvalSymlinks(filepath.Dir(path)); err == nil {
+		path = filepath.Join(dir, filepath.Base(path))
+	}
+
+	if tmp, err := filepath.EvalSymlinks(os.TempDir()); err == nil && strings.HasPrefix(path, tmp+"/") {
+		return nil
+	}
+
+	under := func(root string) bool {
+		return path == root || strings.HasPrefix(path, root+"/")
 	}
+	// Bazel's output root lives under /private but belongs to the user, and
+	// an uninstalled app's launch agents are single plists under /Library
+	bazel := scanner.BazelOutputRoot()
+	exempt := (bazel != "" && under(bazel)) || (c.launchItems && isSystemLaunchItem(path))
+	if !c.allowSystem && !exempt {
+		for _, root := range SystemRoots {
+			if under(root) {
+				return fmt.Errorf("refusing to clean %s: %s is a protected system location (set allowSystemPaths in ~/.config/lume/config.json to override)", path, root)
+			}
+		}
+	}
+	for _, root := range c.denyPaths {
+		if under(root) {
+			return fmt.Errorf("refusing to clean %s: %s is listed in denyPaths", path, root)
+		}
+	}
+	return nil
+}
+
+// withLaunchItems returns a copy of c that may also trash single launch
+// agent and daemon plists under /Library. Only CleanApp uses it, for the
+// residuals of the app being uninstalled; every other clean keeps /Library
+// off limits.
+func (c *Cleaner) withLaunchItems() *Cleaner {
+	lc := *c
+	lc.launchItems = true
+	return &lc
+}
+
+// isSystemLaunchItem reports whether path is a plist directly inside one of
+// scanner.SystemLaunchDirs
+func isSystemLaunchItem(path string) bool {
+	if filepath.Ext(path) != ".plist" {
+		return false
+	}
+	for _, dir := range scanner.SystemLaunchDirs {
+		if filepath.Dir(path) == dir {
+			return true
+		}
+	}
+	return false
+}
+
+// unloadLaunchItem stops a launch agent or daemon before its plist goes to
+// the Trash, so launchd does not keep trying to run a removed app. Daemons
+// need root to unload; without it launchd drops them at the next restart.
+func unloadLaunchItem(path string) {
+	if scanner.CurrentPlatform.Name() != "macOS" {
+		return
+	}
+	exec.Command("launchctl", "unload", path).Run()
 }
 
 // MoveToTrash moves a file to Trash using AppleScript (supports cross-filesystem)
 func (c *Cleaner) MoveToTrash(path string) error {
+	if err := c.checkAllowed(path); err != nil {
+		return err
+	}
+
 	// Check if file exists
 	if _, err := os.Stat(path); os.IsNotExist(err) {
 		return fmt.Errorf("file not found: %s", path)
 	}
 
+	// Finder is only there on macOS
+	if scanner.CurrentPlatform.Name() != "macOS" {
+		return c.directMoveToTrash(path)
+	}
+
 	// Use osascript to invoke Finder to move to Trash
 	// This handles cross-filesystem scenarios
 	script := fmt.Sprintf(`tell application "Finder" to delete POSIX file "%s"`, escapeAppleScript(path))
@@ -61,19 +176,15 @@ func escapeAppleScript(s string) string {
 
 // directMoveToTrash moves to Trash directory directly (fallback)
 func (c *Cleaner) directMoveToTrash(path string) error {
-	filename := filepath.Base(path)
-	destPath := filepath.Join(c.trashPath, filename)
-
-	// If destination exists, append timestamp
-	if _, err := os.Stat(destPath); err == nil {
-		timestamp := time.Now().Format("20060102150405")
-		ext := filepath.Ext(filename)
-		base := strings.TrimSuffix(filename, ext)
-		destPath = filepath.Join(c.trashPath, fmt.Sprintf("%s_%s%s", base, timestamp, ext))
+	if err := os.MkdirAll(c.trashPath, 0700); err != nil {
+		return err
 	}
 
+	destPath := c.trashDest(filepath.Base(path))
+
 	// Try rename (same filesystem)
 	if err := os.Rename(path, destPath); err == nil {
+		c.writeTrashInfo(path, destPath)
 		return nil
 	}
 
@@ -84,10 +195,51 @@ func (c *Cleaner) directMoveToTrash(path string) error {
 	}
 
 	if info.IsDir() {
-		return c.moveDirToTrash(path, destPath)
+		err = c.moveDirToTrash(path, destPath)
+	} else {
+		err = c.moveFileToTrash(path, destPath)
+	}
+	if err == nil {
+		c.writeTrashInfo(path, destPath)
+	}
+	return err
+}
+
+// trashDest returns a path in the Trash for filename that nothing is using
+// yet: the name itself, or the name with a timestamp and, if two moves land
+// in the same second, a counter
+func (c *Cleaner) trashDest(filename string) string {
+	destPath := filepath.Join(c.trashPath, filename)
+	if _, err := os.Lstat(destPath); err != nil {
+		return destPath
 	}
 
-	return c.moveFileToTrash(path, destPath)
+	timestamp := time.Now().Format("20060102150405")
+	ext := filepath.Ext(filename)
+	base := strings.TrimSuffix(filename, ext)
+	destPath = filepath.Join(c.trashPath, fmt.Sprintf("%s_%s%s", base, timestamp, ext))
+	for n := 2; ; n++ {
+		if _, err := os.Lstat(destPath); err != nil {
+			return destPath
+		}
+		destPath = filepath.Join(c.trashPath, fmt.Sprintf("%s_%s_%d%s", base, timestamp, n, ext))
+	}
+}
+
+// writeTrashInfo records where a file moved to a freedesktop.org Trash
+// (.../Trash/files, as on Linux) came from, so file managers can list and
+// restore it. The macOS Trash keeps no such record.
+func (c *Cleaner) writeTrashInfo(orig, dest string) {
+	if filepath.Base(c.trashPath) != "files" {
+		return
+	}
+	infoDir := filepath.Join(filepath.Dir(c.trashPath), "info")
+	if err := os.MkdirAll(infoDir, 0700); err != nil {
+		return
+	}
+	info := fmt.Sprintf("[Trash Info]\nPath=%s\nDeletionDate=%s\n",
+		(&url.URL{Path: orig}).EscapedPath(), time.Now().Format("2006-01-02T15:04:05"))
+	os.WriteFile(filepath.Join(infoDir, filepath.Base(dest)+".trashinfo"), []byte(info), 0600)
 }
 
 // moveFileToTrash moves a file to Trash (cross-filesystem)
@@ -142,6 +294,10 @@ func copyDir(src, dst string) error {
 
 // DeleteFile permanently deletes a file (use with caution)
 func (c *Cleaner) DeleteFile(path string) error {
+	if err := c.checkAllowed(path); err != nil {
+		return err
+	}
+
 	info, err := os.Stat(path)
 	if err != nil {
 		return err
@@ -157,6 +313,10 @@ func (c *Cleaner) DeleteFile(path string) error {
 func (c *Cleaner) CleanScanTargets(targets []scanner.ScanTarget, progressCh chan<- string) (int64, error) {
 	var totalSize int64
 	var failed []string
+	var cleaned []string
+
+	// Even a partial clean changes the sizes scans have cached
+	defer func() { scanner.ForgetSizes(cleaned) }()
 
 	for _, target := range targets {
 		if !target.Selected {
@@ -166,8 +326,26 @@ func (c *Cleaner) CleanScanTargets(targets []scanner.ScanTarget, progressCh chan
 		if progressCh != nil {
 			progressCh <- fmt.Sprintf("Cleaning: %s", target.Name)
 		}
+		cleaned = append(cleaned, target.Path)
+
+		if !target.OlderThan.IsZero() {
+			// Age filter: only the stale files go, so count what actually moved
+			freed, err := c.trashOlderThan(target.Path, target.OlderThan, target.Exclude)
+			totalSize += freed
+			if err != nil {
+				failed = append(failed, fmt.Sprintf("%s: %v", target.Name, err))
+			}
+			continue
+		}
 
-		if err := c.MoveToTrash(target.Path); err != nil {
+		var err error
+		if len(target.Exclude) > 0 {
+			// Nested targets are cleaned (or kept) on their own
+			err = c.clearDirectoryExcept(target.Path, target.Exclude)
+		} else {
+			err = c.MoveToTrash(target.Path)
+		}
+		if err != nil {
 			// Record failure but don't abort
 			failed = append(failed, fmt.Sprintf("%s: %v", target.Name, err))
 		} else {
@@ -225,12 +403,19 @@ func (c *Cleaner) CleanApp(app scanner.AppInfo, removeResiduals bool, progressCh
 
 	// Delete residual files
 	if removeResiduals {
+		rc := c.withLaunchItems()
 		for _, residual := range app.Residuals {
 			if progressCh != nil {
 				progressCh <- fmt.Sprintf("Cleaning residual: %s", filepath.Base(residual.Path))
 			}
 
-			if err := c.MoveToTrash(residual.Path); err != nil {
+			if residual.IsLaunchItem() {
+				if err := rc.checkAllowed(residual.Path); err != nil {
+					continue
+				}
+				unloadLaunchItem(residual.Path)
+			}
+			if err := rc.MoveToTrash(residual.Path); err != nil {
 				// Ignore residual cleanup errors
 				continue
 			}
@@ -241,8 +426,9 @@ func (c *Cleaner) CleanApp(app scanner.AppInfo, removeResiduals bool, progressCh
 	return totalSize, nil
 }
 
-// CleanDuplicateFiles cleans duplicate files
-func (c *Cleaner) CleanDuplicateFiles(groups []scanner.DuplicateGroup, keepNewest bool, progressCh chan<- string) (int64, error) {
+// CleanDuplicateFiles cleans duplicate files, keeping one copy per group:
+// the one the group's Keep names, otherwise the one policy prefers
+func (c *Cleaner) CleanDuplicateFiles(groups []scanner.DuplicateGroup, policy scanner.KeepPolicy, progressCh chan<- string) (int64, error) {
 	var totalSize int64
 
 	for _, group := range groups {
@@ -251,39 +437,34 @@ func (c *Cleaner) CleanDuplicateFiles(groups []scanner.DuplicateGroup, keepNewes
 			continue
 		}
 
-		// Sort by modified time using efficient sort
-		sort.Slice(files, func(i, j int) bool {
-			if keepNewest {
-				return files[i].Modified.After(files[j].Modified)
+		keep := group.KeepIndex(policy)
+		for i, file := range files {
+			if i == keep {
+				continue
 			}
-			return files[i].Modified.Before(files[j].Modified)
-		})
-
-		// Delete all files except the first
-		for i := 1; i < len(files); i++ {
 			if progressCh != nil {
-				progressCh <- fmt.Sprintf("Deleting: %s", files[i].Name)
+				progressCh <- fmt.Sprintf("Deleting: %s", file.Name)
 			}
 
-			if err := c.MoveToTrash(files[i].Path); err != nil {
+			if err := c.MoveToTrash(file.Path); err != nil {
 				continue
 			}
-			totalSize += files[i].Size
+			// A copy with other hard links frees nothing
+			if file.Links <= 1 {
+				totalSize += file.Size
+			}
 		}
 	}
 
 	return totalSize, nil
 }
 
-// CleanBrowserData cleans browser data
+// CleanBrowserData cleans the selected items of each browser. Items are
+// picked individually, so a browser's own Selected flag is not consulted.
 func (c *Cleaner) CleanBrowserData(browsers []scanner.BrowserDataInfo, progressCh chan<- string) (int64, error) {
 	var totalSize int64
 
 	for _, browser := range browsers {
-		if !browser.Selected {
-			continue
-		}
-
 		for _, item := range browser.Data {
 			if !item.Selected {
 				continue
@@ -306,6 +487,112 @@ func (c *Cleaner) CleanBrowserData(browsers []scanner.BrowserDataInfo, progressC
 	return totalSize, nil
 }
 
+// DeleteLocalSnapshots deletes Time Machine local snapshots with
+// `tmutil deletelocalsnapshots`. Unlike every other clean this is permanent:
+// snapshots cannot go to the Trash. Snapshots not made by Time Machine are
+// skipped and reported in the error. It returns how many were deleted.
+func (c *Cleaner) DeleteLocalSnapshots(names []string, progressCh chan<- string) (int, error) {
+	deleted := 0
+	var failed []string
+
+	for _, name := range names {
+		date, ok := snapshotDate(name)
+		if !ok {
+			failed = append(failed, fmt.Sprintf("%s: not a Time Machine snapshot", name))
+			continue
+		}
+
+		if progressCh != nil {
+			progressCh <- fmt.Sprintf("Deleting snapshot %s", date)
+		}
+
+		out, err := exec.Command("tmutil", "deletelocalsnapshots", date).CombinedOutput()
+		if err != nil {
+			msg := strings.TrimSpace(string(out))
+			if msg == "" {
+				msg = err.Error()
+			}
+			failed = append(failed, fmt.Sprintf("%s: %s", date, msg))
+			continue
+		}
+		deleted++
+	}
+
+	if len(failed) > 0 {
+		return deleted, fmt.Errorf("failed to delete %d snapshots: %s", len(failed), strings.Join(failed, "; "))
+	}
+	return deleted, nil
+}
+
+// TrashSize returns how much the user's Trash (~/.Trash) holds and how many
+// items are in it. Reading it needs Full Disk Access on recent macOS; without
+// it the error is returned and the size is 0.
+func (c *Cleaner) TrashSize() (int64, int, error) {
+	entries, err := os.ReadDir(c.trashPath)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	var size int64
+	err = filepath.WalkDir(c.trashPath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			// Unreadable entries are skipped, not fatal
+			return nil
+		}
+		if d.Type().IsRegular() {
+			if info, err := d.Info(); err == nil {
+				size += info.Size()
+			}
+		}
+		return nil
+	})
+	return size, len(entries), err
+}
+
+// TrashDiskUsage returns the disk space the Trash takes up according to
+// du -sk, which is what emptying it would free. du still prints a total when
+// parts of the Trash are unreadable, so that total is used; -1 means du
+// printed nothing usable.
+func (c *Cleaner) TrashDiskUsage() int64 {
+	out, _ := scanner.ScanCommand(context.Background(), "du", "-sk", "--", c.trashPath).Output()
+	fields := strings.Fields(string(out))
+	if len(fields) == 0 {
+		return -1
+	}
+	kb, err := strconv.ParseInt(fields[0], 10, 64)
+	if err != nil {
+		return -1
+	}
+	return kb * 1024
+}
+
+// EmptyTrash empties the Trash the way the platform's own Empty Trash does:
+// through Finder on macOS, where every volume's Trash is emptied, and by
+// deleting the freedesktop.org Trash's files on Linux. Everything in it is
+// deleted permanently. This is the only way lume releases the space its
+// cleanups moved to the Trash.
+func (c *Cleaner) EmptyTrash() error {
+	if err := scanner.CurrentPlatform.EmptyTrash(c.trashPath); err != nil {
+		return fmt.Errorf("failed to empty Trash: %w", err)
+	}
+	return nil
+}
+
+// snapshotDate extracts the date tmutil identifies a snapshot by from its
+// name, e.g. "2024-01-15-101010" from
+// "com.apple.TimeMachine.2024-01-15-101010.local"
+func snapshotDate(name string) (string, bool) {
+	const prefix = "com.apple.TimeMachine."
+	if !strings.HasPrefix(name, prefix) {
+		return "", false
+	}
+	date := strings.TrimSuffix(strings.TrimPrefix(name, prefix), ".local")
+	if _, err := time.Parse("2006-01-02-150405", date); err != nil {
+		return "", false
+	}
+	return date, true
+}
+
 // clearDirectory clears directory contents (always via Trash - never permanently deletes)
 func (c *Cleaner) clearDirectory(path string) error {
 	entries, err := os.ReadDir(path)
@@ -329,6 +616,149 @@ func (c *Cleaner) clearDirectory(path string) error {
 	return nil
 }
 
+// clearDirectoryExcept moves the contents of path to the Trash, leaving the
+// paths in exclude and the folders that lead to them in place
+func (c *Cleaner) clearDirectoryExcept(path string, exclude []string) error {
+	if err := c.checkAllowed(path); err != nil {
+		return err
+	}
+
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return err
+	}
+
+	var errors []string
+	for _, entry := range entries {
+		fullPath := filepath.Join(path, entry.Name())
+
+		keep, descend := false, false
+		for _, ex := range exclude {
+			if fullPath == ex {
+				keep = true
+				break
+			}
+			if strings.HasPrefix(ex, fullPath+"/") {
+				descend = true
+			}
+		}
+
+		switch {
+		case keep:
+			continue
+		case descend && entry.IsDir():
+			err = c.clearDirectoryExcept(fullPath, exclude)
+		default:
+			err = c.MoveToTrash(fullPath)
+		}
+		if err != nil {
+			errors = append(errors, fmt.Sprintf("%s: %v", entry.Name(), err))
+		}
+	}
+
+	if len(errors) > 0 {
+		return fmt.Errorf("failed to move %d items to Trash", len(errors))
+	}
+
+	return nil
+}
+
+// trashOlderThan moves the regular files under path last modified before
+// cutoff to the Trash, skipping the subtrees in exclude, and returns the
+// bytes moved. Folders are left in place, even if they end up empty. The
+// files all go into one new folder in the Trash, named after path, under
+// their paths relative to it: same-named files in different subfolders
+// cannot collide, and there is no Finder round trip per file. A path that
+// is a file itself is moved on its own if it is old enough.
+func (c *Cleaner) trashOlderThan(path string, cutoff time.Time, exclude []string) (int64, error) {
+	if err := c.checkAllowed(path); err != nil {
+		return 0, err
+	}
+
+	if info, err := os.Lstat(path); err == nil && info.Mode().IsRegular() {
+		if !info.ModTime().Before(cutoff) {
+			return 0, nil
+		}
+		if err := c.MoveToTrash(path); err != nil {
+			return 0, err
+		}
+		return info.Size(), nil
+	}
+
+	skip := make(map[string]bool, len(exclude))
+	for _, ex := range exclude {
+		skip[ex] = true
+	}
+
+	var stale []string
+	var sizes []int64
+	err := filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() {
+			if skip[p] {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		if info, err := d.Info(); err == nil && info.ModTime().Before(cutoff) {
+			stale = append(stale, p)
+			sizes = append(sizes, info.Size())
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	if len(stale) == 0 {
+		return 0, nil
+	}
+	if err := os.MkdirAll(c.trashPath, 0700); err != nil {
+		return 0, err
+	}
+	dest := c.trashDest(filepath.Base(path))
+	if err := os.Mkdir(dest, 0700); err != nil {
+		return 0, err
+	}
+	c.writeTrashInfo(path, dest)
+
+	var freed int64
+	var errors []string
+	for i, p := range stale {
+		rel, err := filepath.Rel(path, p)
+		if err == nil {
+			err = c.moveInto(p, filepath.Join(dest, rel))
+		}
+		if err != nil {
+			errors = append(errors, fmt.Sprintf("%s: %v", filepath.Base(p), err))
+			continue
+		}
+		freed += sizes[i]
+	}
+
+	if len(errors) > 0 {
+		return freed, fmt.Errorf("failed to move %d of %d old files to Trash", len(errors), len(stale))
+	}
+	return freed, nil
+}
+
+// moveInto moves the file src to dst, creating dst's folders, and copies it
+// across when the two are on different filesystems
+func (c *Cleaner) moveInto(src, dst string) error {
+	if err := os.MkdirAll(filepath.Dir(dst), 0700); err != nil {
+		return err
+	}
+	if err := os.Rename(src, dst); err == nil {
+		return nil
+	}
+	return c.moveFileToTrash(src, dst)
+}
+
 // CopyFile copies a file
 func CopyFile(src, dst string) error {
 	sourceFile, err := os.Open(src)
@@ -3,7 +3,9 @@ package cleaner
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/Tyooughtul/lume/pkg/scanner"
 )
@@ -178,3 +180,287 @@ func TestCleaner_CleanFiles(t *testing.T) {
 	_ = totalSize
 	_ = err
 }
+
+func TestSnapshotDate(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+		ok   bool
+	}{
+		{"com.apple.TimeMachine.2024-01-15-101010.local", "2024-01-15-101010", true},
+		{"com.apple.TimeMachine.2024-01-15-101010", "2024-01-15-101010", true},
+		{"com.apple.os.update-ABC123", "", false},
+		{"com.apple.TimeMachine.not-a-date.local", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := snapshotDate(tt.name)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("snapshotDate(%q) = %q, %v, want %q, %v", tt.name, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestCleaner_DeleteLocalSnapshots_SkipsForeignSnapshots(t *testing.T) {
+	c := NewCleaner()
+	deleted, err := c.DeleteLocalSnapshots([]string{"com.apple.os.update-ABC123"}, nil)
+	if deleted != 0 || err == nil {
+		t.Errorf("Expected non-Time Machine snapshot to be refused, got %d, %v", deleted, err)
+	}
+}
+
+func TestCleaner_CheckAllowed(t *testing.T) {
+	c := &Cleaner{denyPaths: []string{"/Volumes/Work/keep"}}
+
+	for _, p := range []string{"/Library/Caches", "/usr", "/System/Library/Caches", "/Volumes/Work/keep/a"} {
+		if err := c.checkAllowed(p); err == nil {
+			t.Errorf("Expected %s to be refused", p)
+		}
+	}
+	for _, p := range []string{"/usrlocal/file", "/Volumes/Work/keeper", "/Users/alice/Library/Caches"} {
+		if err := c.checkAllowed(p); err != nil {
+			t.Errorf("Expected %s to be allowed, got %v", p, err)
+		}
+	}
+
+	c.allowSystem = true
+	if err := c.checkAllowed("/Library/Caches"); err != nil {
+		t.Errorf("allowSystemPaths should permit /Library/Caches: %v", err)
+	}
+	if err := c.checkAllowed("/Volumes/Work/keep/a"); err == nil {
+		t.Error("denyPaths should apply even with allowSystemPaths")
+	}
+}
+
+func TestCleaner_CheckAllowed_BazelOutputRoot(t *testing.T) {
+	c := &Cleaner{}
+	root := scanner.BazelOutputRoot()
+	if root == "" {
+		t.Skip("no user name to build the Bazel output root from")
+	}
+
+	if err := c.checkAllowed(filepath.Join(root, "abc123", "execroot")); err != nil {
+		t.Errorf("Expected Bazel's output root to be allowed, got %v", err)
+	}
+	if err := c.checkAllowed("/private/var/tmp/_bazel_someone-else/x"); err == nil {
+		t.Error("Expected another user's Bazel root to be refused")
+	}
+}
+
+func TestCleaner_CheckAllowed_SystemLaunchItems(t *testing.T) {
+	if err := (&Cleaner{}).checkAllowed("/Library/LaunchDaemons/com.example.helper.plist"); err == nil {
+		t.Error("Launch items should only be allowed when uninstalling an app")
+	}
+
+	c := (&Cleaner{}).withLaunchItems()
+	tests := []struct {
+		path    string
+		allowed bool
+	}{
+		{"/Library/LaunchDaemons/com.example.helper.plist", true},
+		{"/Library/LaunchAgents/com.example.updater.plist", true},
+		{"/Library/LaunchDaemons", false},
+		{"/Library/LaunchDaemons/com.example.helper", false},
+		{"/Library/LaunchDaemons/sub/com.example.helper.plist", false},
+		{"/Library/Preferences/com.example.plist", false},
+	}
+	for _, tt := range tests {
+		err := c.checkAllowed(tt.path)
+		if tt.allowed && err != nil {
+			t.Errorf("checkAllowed(%q) = %v, want allowed", tt.path, err)
+		} else if !tt.allowed && err == nil {
+			t.Errorf("checkAllowed(%q) allowed, want refused", tt.path)
+		}
+	}
+}
+
+func TestCleaner_MoveToTrash_RefusesSystemPath(t *testing.T) {
+	c := NewCleaner()
+	c.allowSystem = false
+	err := c.MoveToTrash("/System/Library/lume-test-nonexistent")
+	if err == nil || !strings.Contains(err.Error(), "protected system location") {
+		t.Errorf("Expected protected-location error, got %v", err)
+	}
+}
+
+func TestCleaner_ClearDirectoryExcept(t *testing.T) {
+	root := t.TempDir()
+	trash := filepath.Join(root, ".Trash")
+	caches := filepath.Join(root, "Caches")
+	for _, dir := range []string{trash, filepath.Join(caches, "Homebrew", "Cask"), filepath.Join(caches, "Homebrew", "downloads"), filepath.Join(caches, "com.example.app")} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	c := &Cleaner{trashPath: trash}
+	exclude := []string{filepath.Join(caches, "Homebrew", "Cask")}
+	if err := c.clearDirectoryExcept(caches, exclude); err != nil {
+		t.Fatalf("clearDirectoryExcept failed: %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(caches, "Homebrew", "Cask")); err != nil {
+		t.Errorf("Excluded directory should be kept: %v", err)
+	}
+	for _, gone := range []string{filepath.Join(caches, "com.example.app"), filepath.Join(caches, "Homebrew", "downloads")} {
+		if _, err := os.Stat(gone); !os.IsNotExist(err) {
+			t.Errorf("%s should have been moved to the Trash", gone)
+		}
+	}
+}
+
+func TestCleaner_TrashOlderThan(t *testing.T) {
+	root := t.TempDir()
+	trash := filepath.Join(root, ".Trash")
+	cache := filepath.Join(root, "cache")
+	if err := os.MkdirAll(filepath.Join(cache, "sub"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Mkdir(trash, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	old := time.Now().AddDate(0, 0, -30)
+	for _, name := range []string{"old.bin", "sub/old.bin", "fresh.bin"} {
+		p := filepath.Join(cache, name)
+		if err := os.WriteFile(p, []byte("1234"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if name != "fresh.bin" {
+			if err := os.Chtimes(p, old, old); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	c := &Cleaner{trashPath: trash}
+	freed, err := c.trashOlderThan(cache, time.Now().AddDate(0, 0, -7), nil)
+	if err != nil {
+		t.Fatalf("trashOlderThan failed: %v", err)
+	}
+	if freed != 8 {
+		t.Errorf("Expected 8 bytes freed, got %d", freed)
+	}
+	if _, err := os.Stat(filepath.Join(cache, "fresh.bin")); err != nil {
+		t.Error("Recent file should be kept")
+	}
+	if _, err := os.Stat(filepath.Join(cache, "sub")); err != nil {
+		t.Error("Folders should be left in place")
+	}
+	if _, err := os.Stat(filepath.Join(cache, "old.bin")); !os.IsNotExist(err) {
+		t.Error("Old file should have been moved to the Trash")
+	}
+	for _, name := range []string{"old.bin", "sub/old.bin"} {
+		if _, err := os.Stat(filepath.Join(trash, "cache", name)); err != nil {
+			t.Errorf("%s should be in the Trash under its relative path: %v", name, err)
+		}
+	}
+}
+
+func TestCleaner_TrashOlderThanFile(t *testing.T) {
+	root := t.TempDir()
+	trash := filepath.Join(root, ".Trash")
+	if err := os.Mkdir(trash, 0755); err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().AddDate(0, 0, -30)
+	stale := filepath.Join(root, "stale.log")
+	fresh := filepath.Join(root, "fresh.log")
+	for _, p := range []string{stale, fresh} {
+		if err := os.WriteFile(p, []byte("1234"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Chtimes(stale, old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	c := &Cleaner{trashPath: trash}
+	cutoff := time.Now().AddDate(0, 0, -7)
+	if freed, err := c.trashOlderThan(fresh, cutoff, nil); err != nil || freed != 0 {
+		t.Errorf("A recent file should be kept, got %d, %v", freed, err)
+	}
+	if freed, err := c.trashOlderThan(stale, cutoff, nil); err != nil || freed != 4 {
+		t.Errorf("An old file should be moved, got %d, %v", freed, err)
+	}
+	if _, err := os.Stat(fresh); err != nil {
+		t.Error("Recent file should still be in place")
+	}
+	if _, err := os.Stat(filepath.Join(trash, "stale.log")); err != nil {
+		t.Errorf("Old file should be in the Trash: %v", err)
+	}
+}
+
+func TestCleaner_TrashSize(t *testing.T) {
+	trash := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(trash, "folder", "sub"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	for name, data := range map[string]string{
+		"a.txt":            "12345",
+		"folder/b.txt":     "123",
+		"folder/sub/c.txt": "12",
+	} {
+		if err := os.WriteFile(filepath.Join(trash, name), []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	c := &Cleaner{trashPath: trash}
+	size, items, err := c.TrashSize()
+	if err != nil {
+		t.Fatalf("TrashSize failed: %v", err)
+	}
+	if size != 10 {
+		t.Errorf("Expected 10 bytes, got %d", size)
+	}
+	if items != 2 {
+		t.Errorf("Expected 2 items, got %d", items)
+	}
+
+	c = &Cleaner{trashPath: filepath.Join(trash, "missing")}
+	if _, _, err := c.TrashSize(); err == nil {
+		t.Error("Expected error for a missing Trash")
+	}
+}
+
+func TestCleaner_TrashDiskUsage(t *testing.T) {
+	trash := t.TempDir()
+	if err := os.WriteFile(filepath.Join(trash, "a.bin"), make([]byte, 64*1024), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	c := &Cleaner{trashPath: trash}
+	if got := c.TrashDiskUsage(); got < 64*1024 {
+		t.Errorf("Expected at least 64 KiB, got %d", got)
+	}
+
+	c = &Cleaner{trashPath: filepath.Join(trash, "missing")}
+	if got := c.TrashDiskUsage(); got != -1 {
+		t.Errorf("Expected -1 for a missing Trash, got %d", got)
+	}
+}
+
+func TestCleaner_directMoveToTrash_TrashInfo(t *testing.T) {
+	root := t.TempDir()
+	trash := filepath.Join(root, "Trash", "files") // Not created yet, as on a fresh Linux account
+	src := filepath.Join(root, "my file.txt")
+	if err := os.WriteFile(src, []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	c := &Cleaner{trashPath: trash}
+	if err := c.directMoveToTrash(src); err != nil {
+		t.Fatalf("directMoveToTrash failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(trash, "my file.txt")); err != nil {
+		t.Errorf("File should be in the Trash: %v", err)
+	}
+
+	info, err := os.ReadFile(filepath.Join(root, "Trash", "info", "my file.txt.trashinfo"))
+	if err != nil {
+		t.Fatalf("Expected a .trashinfo file: %v", err)
+	}
+	if !strings.Contains(string(info), "Path="+strings.ReplaceAll(src, " ", "%20")+"\n") {
+		t.Errorf("Unexpected .trashinfo contents:\n%s", info)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"time"
 )
 
 // AppScanner is the application scanner
@@ -68,17 +69,25 @@ func (s *AppScanner) getAppInfo(appPath, appName string) (AppInfo, error) {
 	}
 	info.Size = size
 
-	// Get app install date
+	// Get app install date, preferring when Spotlight saw it added
 	stat, err := os.Stat(appPath)
 	if err == nil {
 		info.InstallDate = stat.ModTime()
 	}
+	if added, lastUsed, ok := s.getAppDates(appPath); ok {
+		if !added.IsZero() {
+			info.InstallDate = added
+		}
+		info.LastUsed = lastUsed
+		info.UsageKnown = true
+	}
 
 	// Get version number
 	info.Version = s.getAppVersion(appPath)
+	info.BundleID = s.getBundleID(appPath)
 
 	// Find residual files
-	info.Residuals = s.findResiduals(appName)
+	info.Residuals = s.findResiduals(appName, info.BundleID)
 
 	return info, nil
 }
@@ -103,8 +112,52 @@ func (s *AppScanner) getAppVersion(appPath string) string {
 	return "Unknown"
 }
 
+// getAppDates asks Spotlight when the app was added and last opened. ok is
+// false when Spotlight could not be asked; a zero time means it has no date.
+func (s *AppScanner) getAppDates(appPath string) (added, lastUsed time.Time, ok bool) {
+	output, err := exec.Command("mdls", "-raw", "-name", "kMDItemDateAdded", "-name", "kMDItemLastUsedDate", appPath).Output()
+	if err != nil {
+		return time.Time{}, time.Time{}, false
+	}
+	dates := parseMdlsDates(string(output))
+	if len(dates) != 2 {
+		return time.Time{}, time.Time{}, false
+	}
+	return dates[0], dates[1], true
+}
+
+// parseMdlsDates parses the NUL-separated values `mdls -raw` prints for
+// several attributes, e.g. "2024-03-01 09:30:00 +0000"; "(null)" and
+// anything unparsable become zero times
+func parseMdlsDates(output string) []time.Time {
+	var dates []time.Time
+	for _, v := range strings.Split(output, "\x00") {
+		t, _ := time.Parse("2006-01-02 15:04:05 -0700", strings.TrimSpace(v))
+		dates = append(dates, t)
+	}
+	return dates
+}
+
+// getBundleID reads the app's CFBundleIdentifier, e.g. "com.tinyspeck.slackmacgap"
+func (s *AppScanner) getBundleID(appPath string) string {
+	infoPlist := filepath.Join(appPath, "Contents", "Info.plist")
+	output, err := exec.Command("defaults", "read", infoPlist, "CFBundleIdentifier").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// SystemLaunchDirs hold launch agents and daemons installed for all users.
+// They are searched for residuals too, and the cleaner lets the plists in
+// them through although /Library is otherwise protected.
+var SystemLaunchDirs = []string{
+	"/Library/LaunchAgents",
+	"/Library/LaunchDaemons",
+}
+
 // findResiduals finds residual files for an app
-func (s *AppScanner) findResiduals(appName string) []ResidualInfo {
+func (s *AppScanner) findResiduals(appName, bundleID string) []ResidualInfo {
 	var residuals []ResidualInfo
 	homeDir := GetRealHomeDir()
 
@@ -122,16 +175,9 @@ func (s *AppScanner) findResiduals(appName string) []ResidualInfo {
 		filepath.Join(homeDir, "Library", "HTTPStorages"),
 		filepath.Join(homeDir, "Library", "Cookies"),
 	}
+	locations = append(locations, SystemLaunchDirs...)
 
-	// Search keywords (expanded matching)
-	keywords := []string{
-		appName,
-		strings.ToLower(appName),
-		strings.ReplaceAll(appName, " ", ""),
-		strings.ReplaceAll(appName, " ", "-"),
-		strings.ReplaceAll(appName, " ", "_"),
-		strings.ReplaceAll(strings.ToLower(appName), " ", "."),
-	}
+	keywords := residualKeywords(appName)
 
 	for _, location := range locations {
 		entries, err := os.ReadDir(location)
@@ -141,18 +187,13 @@ func (s *AppScanner) findResiduals(appName string) []ResidualInfo {
 
 		for _, entry := range entries {
 			entryName := entry.Name()
-			lowerName := strings.ToLower(entryName)
-
-			for _, keyword := range keywords {
-				if strings.Contains(lowerName, strings.ToLower(keyword)) {
-					fullPath := filepath.Join(location, entryName)
-					size, _, _, _ := CalculateDirSize(fullPath, 5)
-					residuals = append(residuals, ResidualInfo{
-						Path: fullPath,
-						Size: size,
-					})
-					break
-				}
+			if matchesBundleID(entryName, bundleID) || matchesAppName(entryName, keywords) {
+				fullPath := filepath.Join(location, entryName)
+				size, _, _, _ := CalculateDirSize(fullPath, 5)
+				residuals = append(residuals, ResidualInfo{
+					Path: fullPath,
+					Size: size,
+				})
 			}
 		}
 	}
@@ -160,6 +201,95 @@ func (s *AppScanner) findResiduals(appName string) []ResidualInfo {
 	return residuals
 }
 
+// minResidualNameLen is the shortest app name (spaces aside) matched by
+// name. Shorter ones, like "Go" or "At", appear as words in too many
+// unrelated folders, so those apps' residuals are found by bundle id only.
+const minResidualNameLen = 4
+
+// sharedResidualDirs are vendor folders holding data for several apps at
+// once; they are only ever matched by bundle id
+var sharedResidualDirs = map[string]bool{
+	"apple":     true,
+	"google":    true,
+	"microsoft": true,
+	"adobe":     true,
+	"mozilla":   true,
+	"jetbrains": true,
+	"electron":  true,
+}
+
+// residualKeywords returns the lowercase spellings of appName that support
+// folders are commonly named after: as is, without spaces, and with the
+// spaces turned into dashes, underscores or dots. It returns none for names
+// shorter than minResidualNameLen.
+func residualKeywords(appName string) []string {
+	lower := strings.ToLower(strings.TrimSpace(appName))
+	if len(strings.ReplaceAll(lower, " ", "")) < minResidualNameLen {
+		return nil
+	}
+	return []string{
+		lower,
+		strings.ReplaceAll(lower, " ", ""),
+		strings.ReplaceAll(lower, " ", "-"),
+		strings.ReplaceAll(lower, " ", "_"),
+		strings.ReplaceAll(lower, " ", "."),
+	}
+}
+
+// matchesBundleID reports whether a Library entry belongs to bundleID, the
+// way macOS names them: the id itself ("com.foo.App"), the id plus a suffix
+// ("com.foo.App.plist", "com.foo.App.savedState", "com.foo.App.helper"), or
+// a group container ("ABCDE12345.com.foo.App", "group.com.foo.App")
+func matchesBundleID(entryName, bundleID string) bool {
+	if bundleID == "" {
+		return false
+	}
+	name := strings.ToLower(entryName)
+	id := strings.ToLower(bundleID)
+	if name == id || strings.HasPrefix(name, id+".") {
+		return true
+	}
+	// Group containers are prefixed with a 10-character team id or "group"
+	if i := strings.IndexByte(name, '.'); i == 10 || (i > 0 && name[:i] == "group") {
+		rest := name[i+1:]
+		return rest == id || strings.HasPrefix(rest, id+".")
+	}
+	return false
+}
+
+// matchesAppName reports whether entryName contains one of keywords as a
+// whole word, so "Slack" matches "Slack" and "slack-desktop" but not
+// "Slackware". Apple's own entries and shared vendor folders never match by
+// name: "Music" must not take com.apple.Music with it.
+func matchesAppName(entryName string, keywords []string) bool {
+	name := strings.ToLower(entryName)
+	if strings.HasPrefix(name, "com.apple.") || sharedResidualDirs[name] {
+		return false
+	}
+	for _, keyword := range keywords {
+		if keyword == "" {
+			continue
+		}
+		for start := 0; ; {
+			i := strings.Index(name[start:], keyword)
+			if i < 0 {
+				break
+			}
+			i += start
+			end := i + len(keyword)
+			if (i == 0 || !isWordChar(name[i-1])) && (end == len(name) || !isWordChar(name[end])) {
+				return true
+			}
+			start = i + 1
+		}
+	}
+	return false
+}
+
+func isWordChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= '0' && c <= '9'
+}
+
 // GetTotalResidualSize gets total residual files size
 func GetTotalResidualSize(app AppInfo) int64 {
 	var total int64
@@ -168,3 +298,35 @@ func GetTotalResidualSize(app AppInfo) int64 {
 	}
 	return total
 }
+
+// RunningApps returns the names of those apps with a process running from
+// inside their bundle. Uninstalling a running app leaves it broken until it
+// quits, so the uninstaller warns first. Without a process list it returns
+// nil rather than guessing.
+func RunningApps(apps []AppInfo) []string {
+	out, err := exec.Command("ps", "-axww", "-o", "args=").Output()
+	if err != nil {
+		return nil
+	}
+	commands := strings.Split(string(out), "\n")
+
+	var running []string
+	for _, app := range apps {
+		if runsFromBundle(commands, app.Path) {
+			running = append(running, app.Name)
+		}
+	}
+	return running
+}
+
+// runsFromBundle reports whether any of commands, as listed by ps, starts an
+// executable inside the bundle at appPath
+func runsFromBundle(commands []string, appPath string) bool {
+	prefix := filepath.Clean(appPath) + "/Contents/"
+	for _, c := range commands {
+		if strings.HasPrefix(strings.TrimSpace(c), prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,133 @@
+package scanner
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRunsFromBundle(t *testing.T) {
+	commands := []string{
+		"/sbin/launchd",
+		"/Applications/Slack.app/Contents/MacOS/Slack",
+		"  /Applications/Google Chrome.app/Contents/Frameworks/Google Chrome Framework.framework/Helpers/Google Chrome Helper.app/Contents/MacOS/Google Chrome Helper --type=renderer",
+		"/usr/bin/open /Applications/Notes Extra.app",
+		"",
+	}
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"/Applications/Slack.app", true},
+		{"/Applications/Slack.app/", true},
+		{"/Applications/Google Chrome.app", true},
+		{"/Applications/Notes Extra.app", false}, // only an argument, not the executable
+		{"/Applications/Sla.app", false},
+		{"/Applications/Zoom.app", false},
+	}
+
+	for _, tt := range tests {
+		if got := runsFromBundle(commands, tt.path); got != tt.want {
+			t.Errorf("runsFromBundle(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestMatchesBundleID(t *testing.T) {
+	id := "com.tinyspeck.slackmacgap"
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{"com.tinyspeck.slackmacgap", true},
+		{"com.tinyspeck.slackmacgap.plist", true},
+		{"com.tinyspeck.slackmacgap.savedState", true},
+		{"com.tinyspeck.slackmacgap.ShipIt", true},
+		{"BQR82RBBHL.com.tinyspeck.slackmacgap", true},
+		{"group.com.tinyspeck.slackmacgap", true},
+		{"com.tinyspeck.slackmacgapbeta", false},
+		{"com.tinyspeck", false},
+		{"other.com.tinyspeck.slackmacgap", false},
+	}
+
+	for _, tt := range tests {
+		if got := matchesBundleID(tt.name, id); got != tt.want {
+			t.Errorf("matchesBundleID(%q) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+	if matchesBundleID("com.tinyspeck.slackmacgap", "") {
+		t.Error("an empty bundle id must not match anything")
+	}
+}
+
+func TestMatchesAppName(t *testing.T) {
+	keywords := residualKeywords("Visual Studio Code")
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{"Code", false},
+		{"Visual Studio Code", true},
+		{"visual-studio-code", true},
+		{"com.microsoft.VisualStudioCode", true},
+		{"VisualStudioCodeInsiders", false},
+	}
+	for _, tt := range tests {
+		if got := matchesAppName(tt.name, keywords); got != tt.want {
+			t.Errorf("matchesAppName(%q) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	slack := residualKeywords("Slack")
+	for name, want := range map[string]bool{
+		"Slack":           true,
+		"slack-desktop":   true,
+		"com.slack.cache": true,
+		"Slackware":       false,
+		"backslack":       false,
+	} {
+		if got := matchesAppName(name, slack); got != want {
+			t.Errorf("matchesAppName(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestResidualNameGuards(t *testing.T) {
+	for _, short := range []string{"Go", "At", " X ", "A B"} {
+		if kw := residualKeywords(short); kw != nil {
+			t.Errorf("residualKeywords(%q) = %v, want none for a short name", short, kw)
+		}
+	}
+
+	tests := []struct {
+		app, entry string
+		want       bool
+	}{
+		{"Music", "com.apple.Music", false},
+		{"Google", "Google", false},
+		{"Microsoft", "Microsoft", false},
+		{"Notion", "Notion", true},
+	}
+	for _, tt := range tests {
+		if got := matchesAppName(tt.entry, residualKeywords(tt.app)); got != tt.want {
+			t.Errorf("matchesAppName(%q) for %q = %v, want %v", tt.entry, tt.app, got, tt.want)
+		}
+	}
+
+	// The bundle id still finds what the name guard skips
+	if !matchesBundleID("com.apple.Music.plist", "com.apple.Music") {
+		t.Error("bundle id match should not be affected by the name guards")
+	}
+}
+
+func TestParseMdlsDates(t *testing.T) {
+	dates := parseMdlsDates("2024-03-01 09:30:00 +0000\x00(null)")
+	if len(dates) != 2 {
+		t.Fatalf("parseMdlsDates() returned %d dates, want 2", len(dates))
+	}
+	if want := time.Date(2024, 3, 1, 9, 30, 0, 0, time.UTC); !dates[0].Equal(want) {
+		t.Errorf("dates[0] = %v, want %v", dates[0], want)
+	}
+	if !dates[1].IsZero() {
+		t.Errorf("dates[1] = %v, want zero for (null)", dates[1])
+	}
+}
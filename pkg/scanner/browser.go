@@ -3,6 +3,7 @@ package scanner
 import (
 	"os"
 	"path/filepath"
+	"strings"
 )
 
 // BrowserScanner is a browser data scanner.
@@ -17,10 +18,14 @@ func NewBrowserScanner() *BrowserScanner {
 type BrowserType string
 
 const (
-	Safari  BrowserType = "Safari"
-	Chrome  BrowserType = "Chrome"
-	Firefox BrowserType = "Firefox"
-	Edge    BrowserType = "Edge"
+	Safari       BrowserType = "Safari"
+	Chrome       BrowserType = "Chrome"
+	Firefox      BrowserType = "Firefox"
+	Edge         BrowserType = "Edge"
+	ChromeCanary BrowserType = "Chrome Canary"
+	Vivaldi      BrowserType = "Vivaldi"
+	Waterfox     BrowserType = "Waterfox"
+	Zen          BrowserType = "Zen"
 )
 
 // BrowserDataInfo holds browser data info.
@@ -34,11 +39,57 @@ type BrowserDataInfo struct {
 
 // BrowserDataItem represents a browser data item.
 type BrowserDataItem struct {
-	Name     string
-	Type     string // cache, history, cookies
-	Path     string
-	Size     int64
-	Selected bool
+	Name      string
+	Type      string // cache, history, cookies, localstorage
+	Path      string
+	Size      int64
+	RiskLevel RiskLevel // RiskHigh for data whose removal logs you out or loses history
+	Selected  bool
+}
+
+// privateDataItem is a never pre-selected, high-risk entry for cookies,
+// history or local storage at path, or false if path holds nothing.
+func privateDataItem(name, dataType, path string) (BrowserDataItem, bool) {
+	size, _, _, _ := CalculateDirSize(path, 5)
+	if size == 0 {
+		return BrowserDataItem{}, false
+	}
+	return BrowserDataItem{
+		Name:      name,
+		Type:      dataType,
+		Path:      path,
+		Size:      size,
+		RiskLevel: RiskHigh,
+	}, true
+}
+
+// isChromiumProfile reports whether a directory in a Chromium user data
+// directory is a profile, as opposed to shared folders like "ShaderCache"
+func isChromiumProfile(name string) bool {
+	return name == "Default" || strings.HasPrefix(name, "Profile ")
+}
+
+// chromiumPrivateData returns the cookies, history and local storage entries
+// of one Chromium profile. Newer versions keep cookies under Network/.
+func chromiumPrivateData(profileName, profilePath string) []BrowserDataItem {
+	var items []BrowserDataItem
+	cookies := filepath.Join(profilePath, "Network", "Cookies")
+	if _, err := os.Stat(cookies); err != nil {
+		cookies = filepath.Join(profilePath, "Cookies")
+	}
+	candidates := []struct {
+		name, dataType, path string
+	}{
+		{"Cookies", "cookies", cookies},
+		{"History", "history", filepath.Join(profilePath, "History")},
+		{"Local Storage", "localstorage", filepath.Join(profilePath, "Local Storage")},
+	}
+	for _, c := range candidates {
+		if item, ok := privateDataItem(profileName+" - "+c.name, c.dataType, c.path); ok {
+			items = append(items, item)
+		}
+	}
+	return items
 }
 
 // Scan scans all browser data.
@@ -69,6 +120,21 @@ func (s *BrowserScanner) Scan(progressCh chan<- string) ([]BrowserDataInfo, erro
 		results = append(results, *edge)
 	}
 
+	// Scan the other Chromium- and Firefox-based browsers
+	homeDir := GetRealHomeDir()
+	appSupport := filepath.Join(homeDir, "Library", "Application Support")
+	others := []*BrowserDataInfo{
+		s.scanChromium("Chrome Canary", ChromeCanary, "[CC]", filepath.Join(appSupport, "Google", "Chrome Canary")),
+		s.scanChromium("Vivaldi", Vivaldi, "[VI]", filepath.Join(appSupport, "Vivaldi")),
+		s.scanGecko("Waterfox", Waterfox, "[WF]", filepath.Join(appSupport, "Waterfox", "Profiles")),
+		s.scanGecko("Zen Browser", Zen, "[ZN]", filepath.Join(appSupport, "zen", "Profiles")),
+	}
+	for _, info := range others {
+		if info != nil {
+			results = append(results, *info)
+		}
+	}
+
 	return results, nil
 }
 
@@ -110,14 +176,25 @@ func (s *BrowserScanner) scanSafari() *BrowserDataInfo {
 	localStorage := filepath.Join(homeDir, "Library", "Safari", "LocalStorage")
 	if size, _, _, _ := CalculateDirSize(localStorage, 3); size > 0 {
 		info.Data = append(info.Data, BrowserDataItem{
-			Name:     "Local Storage",
-			Type:     "localstorage",
-			Path:     localStorage,
-			Size:     size,
-			Selected: false,
+			Name:      "Local Storage",
+			Type:      "localstorage",
+			Path:      localStorage,
+			Size:      size,
+			RiskLevel: RiskHigh,
+			Selected:  false,
 		})
 	}
 
+	// Safari cookies and history
+	cookies := filepath.Join(homeDir, "Library", "Cookies", "Cookies.binarycookies")
+	if item, ok := privateDataItem("Cookies", "cookies", cookies); ok {
+		info.Data = append(info.Data, item)
+	}
+	history := filepath.Join(homeDir, "Library", "Safari", "History.db")
+	if item, ok := privateDataItem("History", "history", history); ok {
+		info.Data = append(info.Data, item)
+	}
+
 	if len(info.Data) == 0 {
 		return nil
 	}
@@ -127,16 +204,19 @@ func (s *BrowserScanner) scanSafari() *BrowserDataInfo {
 
 // scanChrome scans Chrome data.
 func (s *BrowserScanner) scanChrome() *BrowserDataInfo {
-	homeDir := GetRealHomeDir()
+	basePath := filepath.Join(GetRealHomeDir(), "Library", "Application Support", "Google", "Chrome")
+	return s.scanChromium("Google Chrome", Chrome, "[CH]", basePath)
+}
 
+// scanChromium scans the per-profile caches of a Chromium-based browser whose
+// user data directory is basePath.
+func (s *BrowserScanner) scanChromium(name string, browserType BrowserType, icon, basePath string) *BrowserDataInfo {
 	info := &BrowserDataInfo{
-		Name: "Google Chrome",
-		Type: Chrome,
-		Icon: "[CH]",
+		Name: name,
+		Type: browserType,
+		Icon: icon,
 	}
 
-	basePath := filepath.Join(homeDir, "Library", "Application Support", "Google", "Chrome")
-	
 	if _, err := os.Stat(basePath); os.IsNotExist(err) {
 		return nil
 	}
@@ -184,6 +264,10 @@ func (s *BrowserScanner) scanChrome() *BrowserDataInfo {
 				Selected: true,
 			})
 		}
+
+		if isChromiumProfile(profileName) {
+			info.Data = append(info.Data, chromiumPrivateData(profileName, profilePath)...)
+		}
 	}
 
 	if len(info.Data) == 0 {
@@ -195,17 +279,19 @@ func (s *BrowserScanner) scanChrome() *BrowserDataInfo {
 
 // scanFirefox scans Firefox data.
 func (s *BrowserScanner) scanFirefox() *BrowserDataInfo {
-	homeDir := GetRealHomeDir()
+	basePath := filepath.Join(GetRealHomeDir(), "Library", "Application Support", "Firefox", "Profiles")
+	return s.scanGecko("Firefox", Firefox, "[FF]", basePath)
+}
 
+// scanGecko scans the per-profile caches of a Firefox-based browser whose
+// profiles live in basePath.
+func (s *BrowserScanner) scanGecko(name string, browserType BrowserType, icon, basePath string) *BrowserDataInfo {
 	info := &BrowserDataInfo{
-		Name: "Firefox",
-		Type: Firefox,
-		Icon: "[FF]",
+		Name: name,
+		Type: browserType,
+		Icon: icon,
 	}
 
-	basePath := filepath.Join(homeDir, "Library", "Application Support", "Firefox", "Profiles")
-	
-	// Check if Firefox exists
 	if _, err := os.Stat(basePath); os.IsNotExist(err) {
 		return nil
 	}
@@ -241,6 +327,15 @@ func (s *BrowserScanner) scanFirefox() *BrowserDataInfo {
 				Selected: true,
 			})
 		}
+
+		// History lives in places.sqlite together with bookmarks, so only
+		// cookies and site storage are offered
+		if item, ok := privateDataItem(entry.Name()+" - Cookies", "cookies", filepath.Join(profilePath, "cookies.sqlite")); ok {
+			info.Data = append(info.Data, item)
+		}
+		if item, ok := privateDataItem(entry.Name()+" - Local Storage", "localstorage", filepath.Join(profilePath, "storage", "default")); ok {
+			info.Data = append(info.Data, item)
+		}
 	}
 
 	if len(info.Data) == 0 {
@@ -297,6 +392,10 @@ func (s *BrowserScanner) scanEdge() *BrowserDataInfo {
 				Selected: true,
 			})
 		}
+
+		if isChromiumProfile(profileName) {
+			info.Data = append(info.Data, chromiumPrivateData(profileName, profilePath)...)
+		}
 	}
 
 	if len(info.Data) == 0 {
@@ -0,0 +1,61 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestBrowserScanner_ChromiumAndGeckoForks(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("SUDO_USER", "")
+
+	appSupport := filepath.Join(home, "Library", "Application Support")
+	files := []string{
+		filepath.Join(appSupport, "Vivaldi", "Default", "Cache", "data_0"),
+		filepath.Join(appSupport, "Vivaldi", "Default", "GPUCache", "data_1"),
+		filepath.Join(appSupport, "Vivaldi", "Default", "History"),
+		filepath.Join(appSupport, "Vivaldi", "Default", "Network", "Cookies"),
+		filepath.Join(appSupport, "Vivaldi", "ShaderCache", "History"),
+		filepath.Join(appSupport, "zen", "Profiles", "abc.default", "cache2", "entries", "x"),
+	}
+	for _, f := range files {
+		if err := os.MkdirAll(filepath.Dir(f), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(f, make([]byte, 4096), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	results, err := NewBrowserScanner().Scan(nil)
+	if err != nil {
+		t.Fatalf("Scan() error: %v", err)
+	}
+
+	byType := make(map[BrowserType]BrowserDataInfo)
+	for _, r := range results {
+		byType[r.Type] = r
+	}
+
+	vivaldi, ok := byType[Vivaldi]
+	if !ok || len(vivaldi.Data) != 4 {
+		t.Fatalf("Expected Vivaldi with two caches, cookies and history, got %+v", vivaldi)
+	}
+	for _, item := range vivaldi.Data {
+		private := item.Type == "cookies" || item.Type == "history"
+		if private && (item.RiskLevel != RiskHigh || item.Selected) {
+			t.Errorf("%s should be high risk and unselected: %+v", item.Name, item)
+		}
+		if !private && (item.RiskLevel != RiskLow || !item.Selected) {
+			t.Errorf("%s should be a selected low-risk cache: %+v", item.Name, item)
+		}
+	}
+	if zen, ok := byType[Zen]; !ok || len(zen.Data) != 1 || zen.Data[0].Name != "abc.default - Cache" {
+		t.Errorf("Expected Zen with one profile cache, got %+v", zen)
+	}
+	if _, ok := byType[Waterfox]; ok {
+		t.Error("Waterfox is not installed and should not be reported")
+	}
+}
@@ -0,0 +1,175 @@
+package scanner
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const configFileName = "config.json"
+
+// keysFileName holds the user's extra key bindings, see LoadKeyBindings
+const keysFileName = "keys.json"
+
+// Auto-select policies for scan targets
+const (
+	AutoSelectNone    = "none"     // Nothing is pre-selected
+	AutoSelectLowRisk = "low-risk" // Only low-risk targets are pre-selected
+	AutoSelectAllSafe = "all-safe" // Everything except high-risk targets is pre-selected
+)
+
+// Config holds user settings read from ~/.config/lume/config.json.
+// Zero values mean "use the built-in default".
+type Config struct {
+	// AutoSelect overrides the per-target Selected defaults, see AutoSelect* constants
+	AutoSelect string `json:"autoSelect,omitempty"`
+
+	// FullPaths turns off the "~" abbreviation of the home directory in the UI
+	FullPaths bool `json:"fullPaths,omitempty"`
+
+	// DenyPaths adds roots the cleaner must never touch to its built-in list
+	DenyPaths []string `json:"denyPaths,omitempty"`
+
+	// AllowSystemPaths lets the cleaner act on system roots such as /Library
+	AllowSystemPaths bool `json:"allowSystemPaths,omitempty"`
+
+	// MinAgeDays limits System Junk to files not modified for this many days
+	MinAgeDays int `json:"minAgeDays,omitempty"`
+
+	// MinDisplaySizeMB hides System Junk targets smaller than this many MB
+	// until "show all" is toggled on
+	MinDisplaySizeMB int `json:"minDisplaySizeMB,omitempty"`
+
+	// DuplicateKeepPaths lists folders, most preferred first, whose copy of a
+	// duplicate is kept over copies elsewhere
+	DuplicateKeepPaths []string `json:"duplicateKeepPaths,omitempty"`
+
+	// DuplicatePreferShortPaths keeps the copy with the shortest path when
+	// DuplicateKeepPaths does not decide
+	DuplicatePreferShortPaths bool `json:"duplicatePreferShortPaths,omitempty"`
+
+	// HistoryDays is how long disk history is kept, see HistoryRetentionDays
+	HistoryDays int `json:"historyDays,omitempty"`
+
+	// FollowSymlinks counts what symlinks point to when sizing directories,
+	// each canonical target once, instead of skipping them
+	FollowSymlinks bool `json:"followSymlinks,omitempty"`
+
+	// BinaryUnits shows sizes in GiB (powers of 1024, as df -h) instead of GB
+	BinaryUnits bool `json:"binaryUnits,omitempty"`
+
+	// LowPriority runs scan commands under nice and with fewer workers so
+	// scanning does not make the machine sluggish
+	LowPriority bool `json:"lowPriority,omitempty"`
+}
+
+// HistoryRetentionDays is how many days of disk history to keep: HistoryDays
+// when set, otherwise the built-in 90
+func (c Config) HistoryRetentionDays() int {
+	if c.HistoryDays > 0 {
+		return c.HistoryDays
+	}
+	return defaultHistoryDays
+}
+
+// KeepPolicy returns the duplicate keep rules from the config, with "~"
+// expanded; newest picks between copies the rules leave tied
+func (c Config) KeepPolicy(newest bool) KeepPolicy {
+	policy := KeepPolicy{Newest: newest, ShortestPath: c.DuplicatePreferShortPaths}
+	home := GetRealHomeDir()
+	for _, p := range c.DuplicateKeepPaths {
+		if p == "~" || strings.HasPrefix(p, "~/") {
+			p = filepath.Join(home, strings.TrimPrefix(p, "~"))
+		}
+		if filepath.IsAbs(p) {
+			policy.PreferPaths = append(policy.PreferPaths, filepath.Clean(p))
+		}
+	}
+	return policy
+}
+
+// LoadConfig reads the user config. A missing or malformed file yields the
+// zero Config so lume always starts with its defaults.
+func LoadConfig() Config {
+	var cfg Config
+
+	dir := LumeDataDir()
+	if dir == "" {
+		return cfg
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, configFileName))
+	if err != nil {
+		return cfg
+	}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return Config{}
+	}
+	return cfg
+}
+
+// LoadKeyBindings reads ~/.config/lume/keys.json, which maps action names
+// to extra keys for them, e.g. {"clean": ["x"], "down": ["n"]}. A missing
+// file yields no bindings; a malformed one an error, so the UI can say why
+// the bindings were ignored.
+func LoadKeyBindings() (map[string][]string, error) {
+	dir := LumeDataDir()
+	if dir == "" {
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, keysFileName))
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	var bindings map[string][]string
+	if err := json.Unmarshal(data, &bindings); err != nil {
+		return nil, fmt.Errorf("%s: %w", keysFileName, err)
+	}
+	return bindings, nil
+}
+
+// IsAutoSelectPolicy reports whether policy is one of the AutoSelect* constants
+func IsAutoSelectPolicy(policy string) bool {
+	switch policy {
+	case AutoSelectNone, AutoSelectLowRisk, AutoSelectAllSafe:
+		return true
+	}
+	return false
+}
+
+// ApplyAutoSelect sets Selected on each target according to policy.
+// An empty or unknown policy keeps the targets' built-in defaults.
+func ApplyAutoSelect(targets []ScanTarget, policy string) {
+	for i := range targets {
+		switch policy {
+		case AutoSelectNone:
+			targets[i].Selected = false
+		case AutoSelectLowRisk:
+			targets[i].Selected = targets[i].RiskLevel == RiskLow
+		case AutoSelectAllSafe:
+			targets[i].Selected = targets[i].RiskLevel != RiskHigh
+		}
+	}
+}
+
+// AbbreviateHome replaces a leading home directory in path with "~".
+// Paths outside home, and paths that merely share a prefix with it
+// (/Users/alice2 for /Users/alice), are returned unchanged.
+func AbbreviateHome(path, home string) string {
+	home = strings.TrimSuffix(home, "/")
+	if home == "" {
+		return path
+	}
+	if path == home {
+		return "~"
+	}
+	if strings.HasPrefix(path, home+"/") {
+		return "~" + path[len(home):]
+	}
+	return path
+}
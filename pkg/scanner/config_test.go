@@ -0,0 +1,161 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLoadConfig(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("SUDO_USER", "")
+
+	if cfg := LoadConfig(); !reflect.DeepEqual(cfg, Config{}) {
+		t.Errorf("Missing config should load as zero value, got %+v", cfg)
+	}
+
+	dir := filepath.Join(home, ".config", "lume")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, configFileName)
+
+	if err := os.WriteFile(path, []byte(`{"autoSelect": "low-risk"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if cfg := LoadConfig(); cfg.AutoSelect != AutoSelectLowRisk {
+		t.Errorf("AutoSelect = %q, want %q", cfg.AutoSelect, AutoSelectLowRisk)
+	}
+
+	if err := os.WriteFile(path, []byte(`{not json`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if cfg := LoadConfig(); !reflect.DeepEqual(cfg, Config{}) {
+		t.Errorf("Malformed config should load as zero value, got %+v", cfg)
+	}
+}
+
+func TestLoadKeyBindings(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("SUDO_USER", "")
+
+	if bindings, err := LoadKeyBindings(); bindings != nil || err != nil {
+		t.Errorf("Missing keys.json should load as nothing, got %v, %v", bindings, err)
+	}
+
+	dir := filepath.Join(home, ".config", "lume")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, keysFileName)
+
+	if err := os.WriteFile(path, []byte(`{"clean": ["x"], "down": ["n", "ctrl+n"]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	bindings, err := LoadKeyBindings()
+	want := map[string][]string{"clean": {"x"}, "down": {"n", "ctrl+n"}}
+	if err != nil || !reflect.DeepEqual(bindings, want) {
+		t.Errorf("LoadKeyBindings() = %v, %v, want %v", bindings, err, want)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"clean": "x"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadKeyBindings(); err == nil {
+		t.Error("Malformed keys.json should be reported")
+	}
+}
+
+func TestApplyAutoSelect(t *testing.T) {
+	newTargets := func() []ScanTarget {
+		return []ScanTarget{
+			{Name: "low", RiskLevel: RiskLow},
+			{Name: "medium", RiskLevel: RiskMedium, Selected: true},
+			{Name: "high", RiskLevel: RiskHigh},
+		}
+	}
+
+	tests := []struct {
+		policy string
+		want   []bool
+	}{
+		{"", []bool{false, true, false}},
+		{AutoSelectNone, []bool{false, false, false}},
+		{AutoSelectLowRisk, []bool{true, false, false}},
+		{AutoSelectAllSafe, []bool{true, true, false}},
+	}
+
+	for _, tt := range tests {
+		targets := newTargets()
+		ApplyAutoSelect(targets, tt.policy)
+		for i, target := range targets {
+			if target.Selected != tt.want[i] {
+				t.Errorf("policy %q: %s selected = %v, want %v", tt.policy, target.Name, target.Selected, tt.want[i])
+			}
+		}
+	}
+}
+
+func TestIsAutoSelectPolicy(t *testing.T) {
+	for _, policy := range []string{AutoSelectNone, AutoSelectLowRisk, AutoSelectAllSafe} {
+		if !IsAutoSelectPolicy(policy) {
+			t.Errorf("IsAutoSelectPolicy(%q) = false", policy)
+		}
+	}
+	for _, policy := range []string{"", "medium", "LOW-RISK"} {
+		if IsAutoSelectPolicy(policy) {
+			t.Errorf("IsAutoSelectPolicy(%q) = true", policy)
+		}
+	}
+}
+
+func TestAbbreviateHome(t *testing.T) {
+	tests := []struct {
+		path, home, want string
+	}{
+		{"/Users/alice/Library/Caches", "/Users/alice", "~/Library/Caches"},
+		{"/Users/alice", "/Users/alice", "~"},
+		{"/Users/alice/Downloads", "/Users/alice/", "~/Downloads"},
+		{"/Users/alice2/Downloads", "/Users/alice", "/Users/alice2/Downloads"},
+		{"/Library/Caches", "/Users/alice", "/Library/Caches"},
+		{"/Users/alice/Library", "", "/Users/alice/Library"},
+	}
+
+	for _, tt := range tests {
+		if got := AbbreviateHome(tt.path, tt.home); got != tt.want {
+			t.Errorf("AbbreviateHome(%q, %q) = %q, want %q", tt.path, tt.home, got, tt.want)
+		}
+	}
+}
+
+func TestConfig_HistoryRetentionDays(t *testing.T) {
+	if got := (Config{}).HistoryRetentionDays(); got != defaultHistoryDays {
+		t.Errorf("HistoryRetentionDays() = %d, want %d", got, defaultHistoryDays)
+	}
+	if got := (Config{HistoryDays: 365}).HistoryRetentionDays(); got != 365 {
+		t.Errorf("HistoryRetentionDays() = %d, want 365", got)
+	}
+}
+
+func TestConfig_KeepPolicy(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("SUDO_USER", "")
+
+	cfg := Config{
+		DuplicateKeepPaths:        []string{"~/Documents", "/Volumes/Photos/", "relative/dir"},
+		DuplicatePreferShortPaths: true,
+	}
+	policy := cfg.KeepPolicy(true)
+
+	want := []string{filepath.Join(home, "Documents"), "/Volumes/Photos"}
+	if !reflect.DeepEqual(policy.PreferPaths, want) {
+		t.Errorf("PreferPaths = %v, want %v", policy.PreferPaths, want)
+	}
+	if !policy.Newest || !policy.ShortestPath {
+		t.Errorf("Expected Newest and ShortestPath set, got %+v", policy)
+	}
+}
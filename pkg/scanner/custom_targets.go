@@ -0,0 +1,125 @@
+package scanner
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const customTargetsFileName = "targets.json"
+
+// customTarget is one entry of ~/.config/lume/targets.json
+type customTarget struct {
+	Name string `json:"name"`
+	Path string `json:"path"`
+	Risk string `json:"risk"` // "low", "medium" or "high"; empty means medium
+}
+
+// protectedRoots may never be a custom target themselves, nor may any
+// ancestor of them; a typo in targets.json must not turn into "clean /Users"
+var protectedRoots = []string{
+	"/",
+	"/Applications",
+	"/Library",
+	"/System",
+	"/Users",
+	"/bin",
+	"/private",
+	"/usr",
+	"/var",
+}
+
+// loadCustomTargets reads the user's extra scan targets. A missing file yields
+// no targets; invalid entries are skipped and described in errs.
+func loadCustomTargets(homeDir string) (targets []ScanTarget, errs []string) {
+	dir := LumeDataDir()
+	if dir == "" {
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, customTargetsFileName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, []string{fmt.Sprintf("%s: %v", customTargetsFileName, err)}
+	}
+
+	return parseCustomTargets(data, homeDir)
+}
+
+// parseCustomTargets turns the JSON array of targets.json into ScanTargets.
+// Paths may start with "~/". Low-risk targets are selected by default.
+func parseCustomTargets(data []byte, homeDir string) (targets []ScanTarget, errs []string) {
+	var entries []customTarget
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, []string{fmt.Sprintf("%s: %v", customTargetsFileName, err)}
+	}
+
+	for i, e := range entries {
+		label := e.Name
+		if label == "" {
+			label = fmt.Sprintf("entry %d", i+1)
+		}
+
+		path, err := validateCustomPath(e.Path, homeDir)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %s: %v", customTargetsFileName, label, err))
+			continue
+		}
+
+		var risk RiskLevel
+		switch strings.ToLower(e.Risk) {
+		case "low":
+			risk = RiskLow
+		case "", "medium":
+			risk = RiskMedium
+		case "high":
+			risk = RiskHigh
+		default:
+			errs = append(errs, fmt.Sprintf("%s: %s: unknown risk %q", customTargetsFileName, label, e.Risk))
+			continue
+		}
+
+		name := e.Name
+		if name == "" {
+			name = filepath.Base(path)
+		}
+
+		targets = append(targets, ScanTarget{
+			Name:      name,
+			Path:      path,
+			RiskLevel: risk,
+			Selected:  risk == RiskLow,
+		})
+	}
+
+	return targets, errs
+}
+
+// validateCustomPath expands "~" and rejects relative paths, the home
+// directory and protected system roots (or anything containing them)
+func validateCustomPath(path, homeDir string) (string, error) {
+	if path == "" {
+		return "", fmt.Errorf("missing path")
+	}
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		path = filepath.Join(homeDir, strings.TrimPrefix(path, "~"))
+	}
+	if !filepath.IsAbs(path) {
+		return "", fmt.Errorf("path %q is not absolute", path)
+	}
+	path = filepath.Clean(path)
+
+	for _, root := range append(protectedRoots, homeDir) {
+		if root == "" {
+			continue
+		}
+		if path == root || strings.HasPrefix(root, strings.TrimSuffix(path, "/")+"/") {
+			return "", fmt.Errorf("refusing to scan %s", path)
+		}
+	}
+	return path, nil
+}
@@ -0,0 +1,79 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseCustomTargets(t *testing.T) {
+	home := "/Users/alice"
+	data := []byte(`[
+		{"name": "CI build cache", "path": "~/work/build-cache", "risk": "low"},
+		{"path": "/opt/ci/tmp"},
+		{"name": "Risky", "path": "/Volumes/Scratch/old", "risk": "HIGH"},
+		{"name": "Relative", "path": "work/cache"},
+		{"name": "Home", "path": "~"},
+		{"name": "Users", "path": "/Users"},
+		{"name": "Too wide", "path": "/Users/alice/.."},
+		{"name": "Bad risk", "path": "/opt/x", "risk": "extreme"},
+		{"name": "No path"}
+	]`)
+
+	targets, errs := parseCustomTargets(data, home)
+
+	if len(targets) != 3 {
+		t.Fatalf("Expected 3 valid targets, got %d: %+v", len(targets), targets)
+	}
+	if len(errs) != 6 {
+		t.Errorf("Expected 6 errors, got %d: %v", len(errs), errs)
+	}
+
+	ci := targets[0]
+	if ci.Path != "/Users/alice/work/build-cache" || ci.RiskLevel != RiskLow || !ci.Selected {
+		t.Errorf("Unexpected CI target: %+v", ci)
+	}
+	if targets[1].Name != "tmp" || targets[1].RiskLevel != RiskMedium || targets[1].Selected {
+		t.Errorf("Unnamed target should default to base name and medium risk: %+v", targets[1])
+	}
+	if targets[2].RiskLevel != RiskHigh {
+		t.Errorf("Risk should be case-insensitive: %+v", targets[2])
+	}
+
+	if _, errs := parseCustomTargets([]byte(`{not json`), home); len(errs) != 1 {
+		t.Errorf("Malformed file should yield one error, got %v", errs)
+	}
+}
+
+func TestEnhancedJunkScanner_CustomTargets(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("SUDO_USER", "")
+
+	dir := filepath.Join(home, ".config", "lume")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	data := `[{"name": "Build cache", "path": "~/work/build-cache", "risk": "low"},
+		{"name": "Dup of npm", "path": "~/.npm"}]`
+	if err := os.WriteFile(filepath.Join(dir, customTargetsFileName), []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	s := NewEnhancedJunkScanner()
+	var found, npm int
+	for _, target := range s.BuildTargets() {
+		if target.Path == filepath.Join(home, "work", "build-cache") {
+			found++
+		}
+		if target.Path == filepath.Join(home, ".npm") {
+			npm++
+		}
+	}
+	if found != 1 {
+		t.Errorf("Custom target added %d times, want 1", found)
+	}
+	if npm != 1 {
+		t.Errorf("Custom target duplicating a built-in path was added again")
+	}
+}
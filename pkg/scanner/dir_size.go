@@ -1,13 +1,66 @@
 package scanner
 
 import (
+	"context"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"strconv"
 	"strings"
+	"sync"
 )
 
+var (
+	followOnce     sync.Once
+	followSymlinks bool
+)
+
+// SetFollowSymlinks overrides the config's followSymlinks setting
+func SetFollowSymlinks(follow bool) {
+	followOnce.Do(func() {})
+	followSymlinks = follow
+}
+
+// followSymlinksEnabled reports whether size walks follow symlinks, read
+// from the config the first time it is asked
+func followSymlinksEnabled() bool {
+	followOnce.Do(func() { followSymlinks = LoadConfig().FollowSymlinks })
+	return followSymlinks
+}
+
+// resolveSymlink returns the canonical path of the symlink at path and the
+// info of what it points to, or ok false when the link should be skipped:
+// following is off, the link is broken, or its canonical target was already
+// counted in this walk. Directory cycles are also caught by the inode check
+// the walks do, since a directory's inode is marked before its entries.
+func resolveSymlink(path string, visited map[string]bool) (string, os.FileInfo, bool) {
+	if !followSymlinksEnabled() {
+		return "", nil, false
+	}
+	target, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return "", nil, false
+	}
+	key := "link:" + target
+	if visited[key] {
+		return "", nil, false
+	}
+	visited[key] = true
+	info, err := os.Lstat(target)
+	if err != nil {
+		return "", nil, false
+	}
+	return target, info, true
+}
+
+// duFlags are du's flags for a whole-tree size: with followSymlinks set, du
+// follows every symlink, as the size walks then do
+func duFlags() string {
+	if followSymlinksEnabled() {
+		return "-skL"
+	}
+	return "-sk"
+}
+
 // CalculateDirSize calculates directory size (correctly handles symlinks and sparse files)
 func CalculateDirSize(path string, maxDepth int) (int64, int, []FileInfo, error) {
 	// Use map to track visited files (by device and inode) to avoid counting hard links twice
@@ -41,9 +94,14 @@ func calculateDirSizeInternal(path string, maxDepth int, visited map[string]bool
 		return 0, 0, nil, err
 	}
 
-	// If it's a symlink, skip (avoid double counting and cycles)
+	// A symlink is skipped (avoiding double counting and cycles) unless
+	// followSymlinks is on
 	if pathInfo.Mode()&os.ModeSymlink != 0 {
-		return 0, 0, nil, nil
+		target, info, ok := resolveSymlink(path, visited)
+		if !ok {
+			return 0, 0, nil, nil
+		}
+		path, pathInfo = target, info
 	}
 
 	// Check if already visited (by inode, prevent cycles)
@@ -78,9 +136,13 @@ func calculateDirSizeInternal(path string, maxDepth int, visited map[string]bool
 			continue
 		}
 
-		// Skip symlinks
+		// Skip symlinks unless followSymlinks is on
 		if info.Mode()&os.ModeSymlink != 0 {
-			continue
+			target, targetInfo, ok := resolveSymlink(fullPath, visited)
+			if !ok {
+				continue
+			}
+			fullPath, info = target, targetInfo
 		}
 
 		if info.IsDir() {
@@ -104,7 +166,7 @@ func calculateDirSizeInternal(path string, maxDepth int, visited map[string]bool
 			if len(files) < 50 {
 				files = append(files, FileInfo{
 					Path:     fullPath,
-					Name:     entry.Name(),
+					Name:     filepath.Base(fullPath),
 					Size:     info.Size(),
 					Modified: info.ModTime(),
 				})
@@ -117,7 +179,7 @@ func calculateDirSizeInternal(path string, maxDepth int, visited map[string]bool
 
 // getActualDiskUsage uses the du command to get actual disk usage (handles sparse files)
 func getActualDiskUsage(path string) int64 {
-	cmd := exec.Command("du", "-sk", path)
+	cmd := ScanCommand(context.Background(), "du", duFlags(), "--", path)
 	output, err := cmd.Output()
 	if err != nil {
 		return -1
@@ -0,0 +1,85 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// symlinkTree builds root/caches holding a 100-byte file and a symlink to
+// root/elsewhere (300 bytes), a second symlink to the same place, and a
+// symlink back up to root
+func symlinkTree(t *testing.T) string {
+	t.Helper()
+	root := t.TempDir()
+	caches := filepath.Join(root, "caches")
+	elsewhere := filepath.Join(root, "elsewhere")
+	for _, dir := range []string{caches, elsewhere} {
+		if err := os.Mkdir(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(caches, "local.bin"), make([]byte, 100), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(elsewhere, "moved.bin"), make([]byte, 300), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for name, target := range map[string]string{"moved": elsewhere, "moved-again": elsewhere, "loop": root} {
+		if err := os.Symlink(target, filepath.Join(caches, name)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return caches
+}
+
+func TestCalculateDirSize_SkipsSymlinks(t *testing.T) {
+	SetFollowSymlinks(false)
+	caches := symlinkTree(t)
+
+	size, count, _, err := CalculateDirSize(caches, 10)
+	if err != nil {
+		t.Fatalf("CalculateDirSize failed: %v", err)
+	}
+	if size != 100 || count != 1 {
+		t.Errorf("Expected 100 bytes in 1 file, got %d in %d", size, count)
+	}
+}
+
+func TestCalculateDirSize_FollowSymlinks(t *testing.T) {
+	SetFollowSymlinks(true)
+	defer SetFollowSymlinks(false)
+	caches := symlinkTree(t)
+
+	// The two links to elsewhere count once; the loop back to the root
+	// reaches caches again, which is not counted twice
+	size, count, _, err := CalculateDirSize(caches, 10)
+	if err != nil {
+		t.Fatalf("CalculateDirSize failed: %v", err)
+	}
+	if size != 400 || count != 2 {
+		t.Errorf("Expected 400 bytes in 2 files, got %d in %d", size, count)
+	}
+
+	size, count, _, err = calculateDirSizeDeep(caches)
+	if err != nil {
+		t.Fatalf("calculateDirSizeDeep failed: %v", err)
+	}
+	if size != 400 || count != 2 {
+		t.Errorf("Deep: expected 400 bytes in 2 files, got %d in %d", size, count)
+	}
+}
+
+func TestCalculateDirSize_FollowSymlinkedRoot(t *testing.T) {
+	SetFollowSymlinks(true)
+	defer SetFollowSymlinks(false)
+	caches := symlinkTree(t)
+	link := filepath.Join(t.TempDir(), "cache-link")
+	if err := os.Symlink(filepath.Join(filepath.Dir(caches), "elsewhere"), link); err != nil {
+		t.Fatal(err)
+	}
+
+	if size, _, _, _ := CalculateDirSize(link, 10); size != 300 {
+		t.Errorf("Expected a symlinked cache to read 300 bytes, got %d", size)
+	}
+}
@@ -1,9 +1,11 @@
 package scanner
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
+	"runtime"
 	"sort"
 	"strings"
 	"sync"
@@ -37,8 +39,17 @@ func (da *DiskAnalyzer) SetMinSize(size int64) {
 	da.minSize = size
 }
 
+// analyzerWorkers bounds how many directories are read concurrently
+var analyzerWorkers = 2 * runtime.NumCPU()
+
 // AnalyzePath analyzes the specified path
 func (da *DiskAnalyzer) AnalyzePath(rootPath string, progressCh chan<- string) (*DiskItem, error) {
+	return da.AnalyzePathContext(context.Background(), rootPath, progressCh)
+}
+
+// AnalyzePathContext is AnalyzePath with cancellation: once ctx is done no
+// more directories are read and ctx.Err() is returned
+func (da *DiskAnalyzer) AnalyzePathContext(ctx context.Context, rootPath string, progressCh chan<- string) (*DiskItem, error) {
 	if progressCh != nil {
 		progressCh <- fmt.Sprintf("Analyzing: %s", rootPath)
 	}
@@ -56,7 +67,12 @@ func (da *DiskAnalyzer) AnalyzePath(rootPath string, progressCh chan<- string) (
 	}
 
 	if info.IsDir() {
-		da.analyzeDir(root, progressCh)
+		sem := make(chan struct{}, scanWorkers(analyzerWorkers))
+		da.analyzeDir(ctx, root, sem, progressCh)
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		linkParents(root)
 	} else {
 		root.Size = info.Size()
 	}
@@ -64,8 +80,15 @@ func (da *DiskAnalyzer) AnalyzePath(rootPath string, progressCh chan<- string) (
 	return root, nil
 }
 
-// analyzeDir recursively analyzes a directory
-func (da *DiskAnalyzer) analyzeDir(item *DiskItem, progressCh chan<- string) {
+// analyzeDir recursively analyzes a directory. Subdirectories are analyzed
+// in new goroutines while sem has free slots and inline otherwise, so the
+// fan-out stays bounded without a parent ever waiting on a slot its own
+// children need. Each goroutine writes only to its own child; item itself is
+// only touched by the caller, after all children are done.
+func (da *DiskAnalyzer) analyzeDir(ctx context.Context, item *DiskItem, sem chan struct{}, progressCh chan<- string) {
+	if ctx.Err() != nil {
+		return
+	}
 	entries, err := os.ReadDir(item.Path)
 	if err != nil {
 		if progressCh != nil {
@@ -74,18 +97,15 @@ func (da *DiskAnalyzer) analyzeDir(item *DiskItem, progressCh chan<- string) {
 		return
 	}
 
-	var wg sync.WaitGroup
-	var mu sync.Mutex
-
+	children := make([]DiskItem, 0, len(entries))
 	for _, entry := range entries {
 		fullPath := filepath.Join(item.Path, entry.Name())
-		
+
 		child := DiskItem{
-			Path:   fullPath,
-			Name:   entry.Name(),
-			IsDir:  entry.IsDir(),
-			Depth:  item.Depth + 1,
-			Parent: item,
+			Path:  fullPath,
+			Name:  entry.Name(),
+			IsDir: entry.IsDir(),
+			Depth: item.Depth + 1,
 		}
 
 		if entry.IsDir() {
@@ -93,39 +113,58 @@ func (da *DiskAnalyzer) analyzeDir(item *DiskItem, progressCh chan<- string) {
 			if shouldSkipDir(fullPath) {
 				continue
 			}
-
-			wg.Add(1)
-			go func(c *DiskItem) {
-				defer wg.Done()
-				da.analyzeDir(c, progressCh)
-				
-				mu.Lock()
-				if c.Size >= da.minSize {
-					item.Children = append(item.Children, *c)
-				}
-				item.Size += c.Size
-				mu.Unlock()
-			}(&child)
 		} else {
 			info, err := entry.Info()
-			if err == nil {
-				child.Size = info.Size()
-				item.Size += child.Size
-				if child.Size >= da.minSize {
-					item.Children = append(item.Children, child)
-				}
+			if err != nil {
+				continue
 			}
+			child.Size = info.Size()
 		}
+		children = append(children, child)
 	}
 
+	var wg sync.WaitGroup
+	for i := range children {
+		if !children[i].IsDir {
+			continue
+		}
+		c := &children[i]
+		select {
+		case sem <- struct{}{}:
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				defer func() { <-sem }()
+				da.analyzeDir(ctx, c, sem, progressCh)
+			}()
+		default:
+			da.analyzeDir(ctx, c, sem, progressCh)
+		}
+	}
 	wg.Wait()
 
+	for _, child := range children {
+		item.Size += child.Size
+		if child.Size >= da.minSize {
+			item.Children = append(item.Children, child)
+		}
+	}
+
 	// Sort by size
 	sort.Slice(item.Children, func(i, j int) bool {
 		return item.Children[i].Size > item.Children[j].Size
 	})
 }
 
+// linkParents sets Parent on every item below root. It runs once the tree is
+// complete because children are copied while it is being built.
+func linkParents(item *DiskItem) {
+	for i := range item.Children {
+		item.Children[i].Parent = item
+		linkParents(&item.Children[i])
+	}
+}
+
 // shouldSkipDir checks whether to skip this directory
 func shouldSkipDir(path string) bool {
 	skipPaths := []string{
@@ -0,0 +1,72 @@
+package scanner
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDiskAnalyzer_AnalyzePath(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]int{
+		"a/big.bin":      4096,
+		"a/b/nested.bin": 2048,
+		"a/b/c/deep.bin": 1024,
+		"small.txt":      10,
+		"d/other.bin":    512,
+	}
+	for name, size := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, make([]byte, size), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Force the inline path as well as the goroutine path
+	defer func(n int) { analyzerWorkers = n }(analyzerWorkers)
+	analyzerWorkers = 1
+
+	da := NewDiskAnalyzer()
+	da.SetMinSize(100)
+	item, err := da.AnalyzePath(root, nil)
+	if err != nil {
+		t.Fatalf("AnalyzePath() error: %v", err)
+	}
+
+	if want := int64(4096 + 2048 + 1024 + 10 + 512); item.Size != want {
+		t.Errorf("root size = %d, want %d", item.Size, want)
+	}
+	if len(item.Children) != 2 || item.Children[0].Name != "a" || item.Children[0].Size != 4096+2048+1024 {
+		t.Fatalf("Expected children a, d sorted by size (small.txt below minSize), got %+v", item.Children)
+	}
+
+	var check func(it *DiskItem)
+	check = func(it *DiskItem) {
+		for i := range it.Children {
+			if it.Children[i].Parent != it {
+				t.Errorf("%s: Parent does not point at %s", it.Children[i].Path, it.Path)
+			}
+			check(&it.Children[i])
+		}
+	}
+	check(item)
+}
+
+func TestDiskAnalyzer_AnalyzePathContextCanceled(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "a", "b"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	item, err := NewDiskAnalyzer().AnalyzePathContext(ctx, root, nil)
+	if !errors.Is(err, context.Canceled) || item != nil {
+		t.Errorf("AnalyzePathContext() = %v, %v; want nil, context.Canceled", item, err)
+	}
+}
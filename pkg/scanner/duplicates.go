@@ -1,6 +1,7 @@
 package scanner
 
 import (
+	"context"
 	"crypto/sha256"
 	"encoding/hex"
 	"fmt"
@@ -9,20 +10,31 @@ import (
 	"path/filepath"
 	"runtime"
 	"sort"
+	"strings"
 	"sync"
 )
 
 // DuplicateScanner is the duplicate file scanner
 type DuplicateScanner struct {
-	rootPath string
-	minSize  int64
+	rootPaths []string
+	minSize   int64
+	maxDepth  int // 0 means unlimited
+
+	excludePatterns   []string
+	includeExtensions map[string]bool // nil means every extension
 }
 
 // NewDuplicateScanner creates a duplicate file scanner
 func NewDuplicateScanner(rootPath string) *DuplicateScanner {
+	return NewDuplicateScannerWithRoots([]string{rootPath})
+}
+
+// NewDuplicateScannerWithRoots creates a duplicate file scanner that searches
+// several root directories as one set (files are compared across roots)
+func NewDuplicateScannerWithRoots(rootPaths []string) *DuplicateScanner {
 	return &DuplicateScanner{
-		rootPath: rootPath,
-		minSize:  1024, // default minimum 1KB
+		rootPaths: rootPaths,
+		minSize:   1024, // default minimum 1KB
 	}
 }
 
@@ -31,11 +43,78 @@ func (s *DuplicateScanner) SetMinSize(size int64) {
 	s.minSize = size
 }
 
+// SetMaxDepth limits how deep below each root files are collected, like
+// find -maxdepth (1 = only files directly in the root). 0 means unlimited.
+func (s *DuplicateScanner) SetMaxDepth(depth int) {
+	s.maxDepth = depth
+}
+
+// SetExcludePatterns skips files and directories matching any of the glob
+// patterns (filepath.Match syntax). Patterns without a "/" are matched against
+// the base name, so "node_modules" or "*.app" prune whole directories;
+// patterns with a "/" are matched against the full path.
+func (s *DuplicateScanner) SetExcludePatterns(patterns []string) {
+	s.excludePatterns = patterns
+}
+
+// SetIncludeExtensions limits the scan to files with one of the given
+// extensions ("jpg" and ".JPG" are equivalent). An empty list scans every file.
+func (s *DuplicateScanner) SetIncludeExtensions(exts []string) {
+	s.includeExtensions = nil
+	for _, ext := range exts {
+		ext = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(ext), "."))
+		if ext == "" {
+			continue
+		}
+		if s.includeExtensions == nil {
+			s.includeExtensions = make(map[string]bool)
+		}
+		s.includeExtensions["."+ext] = true
+	}
+}
+
+// excluded reports whether path matches one of the exclude patterns
+func (s *DuplicateScanner) excluded(path string) bool {
+	base := filepath.Base(path)
+	for _, pattern := range s.excludePatterns {
+		name := base
+		if strings.Contains(pattern, "/") {
+			name = path
+		}
+		if ok, _ := filepath.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// included reports whether path has one of the include extensions
+func (s *DuplicateScanner) included(path string) bool {
+	if s.includeExtensions == nil {
+		return true
+	}
+	return s.includeExtensions[strings.ToLower(filepath.Ext(path))]
+}
+
 // Scan scans for duplicate files using a 3-stage pipeline for maximum performance:
 // Stage 1: Group by file size (instant, zero I/O)
 // Stage 2: Quick hash (first 8KB + last 8KB + size) to eliminate ~99% of non-duplicates
 // Stage 3: Full SHA-256 hash only for files that matched in stage 2 (zero false positives)
 func (s *DuplicateScanner) Scan(progressCh chan<- string) ([]DuplicateGroup, error) {
+	return s.ScanContext(context.Background(), progressCh)
+}
+
+// ScanContext is Scan with cancellation: once ctx is done the walk and the
+// hashing workers stop at the next file and ctx.Err() is returned
+func (s *DuplicateScanner) ScanContext(ctx context.Context, progressCh chan<- string) ([]DuplicateGroup, error) {
+	return s.ScanStream(ctx, progressCh, nil)
+}
+
+// ScanStream is ScanContext that also sends each group to found as soon as
+// its full hashes confirm it, so a caller can show results long before a big
+// tree is finished. Groups arrive roughly largest first; the returned slice
+// holds them all, sorted. found is not closed.
+func (s *DuplicateScanner) ScanStream(ctx context.Context, progressCh chan<- string, found chan<- DuplicateGroup) ([]DuplicateGroup, error) {
 	// Stage 1: Group by size
 	sizeMap := make(map[int64][]string)
 
@@ -43,26 +122,66 @@ func (s *DuplicateScanner) Scan(progressCh chan<- string) ([]DuplicateGroup, err
 		progressCh <- "Stage 1: Collecting file info..."
 	}
 
-	err := filepath.Walk(s.rootPath, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return nil
-		}
+	// Overlapping roots (e.g. ~/Pictures and ~/Pictures/2023) would otherwise
+	// report a file as a duplicate of itself
+	seen := make(map[string]bool)
+	// Hard links to one file are the same data; only the first path found
+	// is compared, or deleting the other would "reclaim" nothing
+	inodes := make(map[string]bool)
+	collected := 0
+
+	for _, rootPath := range s.rootPaths {
+		root := filepath.Clean(rootPath)
+		err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if err != nil {
+				return nil
+			}
 
-		if info.IsDir() {
-			return nil
-		}
+			if info.IsDir() {
+				if s.maxDepth > 0 && path != root && dirDepth(root, path) >= s.maxDepth {
+					return filepath.SkipDir
+				}
+				if path != root && s.excluded(path) {
+					return filepath.SkipDir
+				}
+				return nil
+			}
 
-		// Skip small files
-		if info.Size() < s.minSize {
-			return nil
-		}
+			// Skip small files
+			if info.Size() < s.minSize {
+				return nil
+			}
 
-		sizeMap[info.Size()] = append(sizeMap[info.Size()], path)
-		return nil
-	})
+			if s.excluded(path) || !s.included(path) {
+				return nil
+			}
 
-	if err != nil {
-		return nil, err
+			if seen[path] {
+				return nil
+			}
+			seen[path] = true
+
+			if key, ok := inodeKey(info); ok {
+				if inodes[key] {
+					return nil
+				}
+				inodes[key] = true
+			}
+
+			sizeMap[info.Size()] = append(sizeMap[info.Size()], path)
+			collected++
+			if progressCh != nil && collected%1000 == 0 {
+				progressCh <- fmt.Sprintf("Collecting file info: %d files...", collected)
+			}
+			return nil
+		})
+
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	// Collect candidate pairs (files with same size, at least 2)
@@ -95,6 +214,7 @@ func (s *DuplicateScanner) Scan(progressCh chan<- string) ([]DuplicateGroup, err
 	if numWorkers < 2 {
 		numWorkers = 2
 	}
+	numWorkers = scanWorkers(numWorkers)
 
 	quickHashMap := make(map[string][]string) // quickHash -> []paths
 	var mu sync.Mutex
@@ -111,6 +231,9 @@ func (s *DuplicateScanner) Scan(progressCh chan<- string) ([]DuplicateGroup, err
 		go func() {
 			defer wg.Done()
 			for job := range jobs {
+				if ctx.Err() != nil {
+					continue
+				}
 				hash, err := calculateQuickHash(job.path)
 				if err != nil {
 					continue
@@ -134,75 +257,153 @@ func (s *DuplicateScanner) Scan(progressCh chan<- string) ([]DuplicateGroup, err
 	close(jobs)
 	wg.Wait()
 
-	// Stage 3: Full hash only for quick-hash collisions (real duplicate candidates)
-	var quickDupGroups [][]string
-	for _, paths := range quickHashMap {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// Stage 3: Full hash only for quick-hash collisions (real duplicate candidates).
+	// Identical files always share a quick hash, so each quick group can be
+	// confirmed on its own; the ones wasting the most go first so they are
+	// the first to reach found.
+	type quickGroup struct {
+		paths []string
+		waste int64 // what it frees if every file turns out identical
+	}
+	var quickDupGroups []quickGroup
+	for key, paths := range quickHashMap {
 		if len(paths) >= 2 {
-			quickDupGroups = append(quickDupGroups, paths)
+			var size int64
+			fmt.Sscanf(key, "%d:", &size)
+			quickDupGroups = append(quickDupGroups, quickGroup{paths: paths, waste: int64(len(paths)-1) * size})
 		}
 	}
+	sort.Slice(quickDupGroups, func(i, j int) bool {
+		return quickDupGroups[i].waste > quickDupGroups[j].waste
+	})
 
 	if progressCh != nil {
 		progressCh <- fmt.Sprintf("Stage 3: Full hash %d potential duplicate groups...", len(quickDupGroups))
 	}
 
-	fullHashMap := make(map[string][]FileInfo)
-	jobs2 := make(chan string, 256)
+	var duplicates []DuplicateGroup
+	jobs2 := make(chan []string, 256)
 	var wg2 sync.WaitGroup
+	hashed := 0
 
 	for w := 0; w < numWorkers; w++ {
 		wg2.Add(1)
 		go func() {
 			defer wg2.Done()
-			for path := range jobs2 {
-				hash, err := calculateFullHash(path)
-				if err != nil {
-					continue
-				}
-				info, err := os.Stat(path)
-				if err != nil {
+			for paths := range jobs2 {
+				if ctx.Err() != nil {
 					continue
 				}
+				groups := fullHashGroups(ctx, paths)
 				mu.Lock()
-				fullHashMap[hash] = append(fullHashMap[hash], FileInfo{
-					Path:     path,
-					Name:     filepath.Base(path),
-					Size:     info.Size(),
-					Modified: info.ModTime(),
-				})
+				hashed++
+				if progressCh != nil && hashed%20 == 0 {
+					progressCh <- fmt.Sprintf("Full hashing: %d / %d groups...", hashed, len(quickDupGroups))
+				}
 				mu.Unlock()
+				for _, group := range groups {
+					mu.Lock()
+					duplicates = append(duplicates, group)
+					mu.Unlock()
+					if found != nil {
+						select {
+						case found <- group:
+						case <-ctx.Done():
+						}
+					}
+				}
 			}
 		}()
 	}
 
-	for _, paths := range quickDupGroups {
-		for _, p := range paths {
-			jobs2 <- p
-		}
+	for _, g := range quickDupGroups {
+		jobs2 <- g.paths
 	}
 	close(jobs2)
 	wg2.Wait()
 
-	// Build duplicate groups
-	var duplicates []DuplicateGroup
-	for hash, files := range fullHashMap {
-		if len(files) > 1 {
-			duplicates = append(duplicates, DuplicateGroup{
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// Sort by total wasted space (descending) for better UX
+	sort.Slice(duplicates, func(i, j int) bool {
+		return duplicates[i].Reclaimable() > duplicates[j].Reclaimable()
+	})
+
+	return duplicates, nil
+}
+
+// fullHashGroups hashes every file of one quick-hash group and returns the
+// sets that really are identical
+func fullHashGroups(ctx context.Context, paths []string) []DuplicateGroup {
+	byHash := make(map[string][]FileInfo)
+	var order []string
+	for _, path := range paths {
+		if ctx.Err() != nil {
+			return nil
+		}
+		hash, err := calculateFullHash(ctx, path)
+		if err != nil {
+			continue
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		if _, ok := byHash[hash]; !ok {
+			order = append(order, hash)
+		}
+		byHash[hash] = append(byHash[hash], FileInfo{
+			Path:     path,
+			Name:     filepath.Base(path),
+			Size:     info.Size(),
+			Modified: info.ModTime(),
+			Links:    linkCount(info),
+		})
+	}
+
+	var groups []DuplicateGroup
+	for _, hash := range order {
+		if files := byHash[hash]; len(files) > 1 {
+			groups = append(groups, DuplicateGroup{
 				Hash:  hash,
 				Size:  files[0].Size,
 				Files: files,
 			})
 		}
 	}
+	return groups
+}
 
-	// Sort by total wasted space (descending) for better UX
-	sort.Slice(duplicates, func(i, j int) bool {
-		wasteI := int64(len(duplicates[i].Files)-1) * duplicates[i].Size
-		wasteJ := int64(len(duplicates[j].Files)-1) * duplicates[j].Size
-		return wasteI > wasteJ
-	})
+// inodeKey identifies the file behind info by device and inode. Unlike
+// GetFileKey it reports false rather than falling back to the name, which
+// would make unrelated files with the same name look like one.
+func inodeKey(info os.FileInfo) (string, bool) {
+	if _, _, _, ok := CurrentPlatform.FileID(info); !ok {
+		return "", false
+	}
+	return GetFileKey(info), true
+}
 
-	return duplicates, nil
+// linkCount returns how many hard links the file behind info has, or 0 when
+// the platform does not say
+func linkCount(info os.FileInfo) int {
+	_, _, links, _ := CurrentPlatform.FileID(info)
+	return links
+}
+
+// dirDepth returns how many levels path is below root (root itself is 0)
+func dirDepth(root, path string) int {
+	rel, err := filepath.Rel(root, path)
+	if err != nil || rel == "." {
+		return 0
+	}
+	return strings.Count(rel, string(filepath.Separator)) + 1
 }
 
 // calculateQuickHash computes a fast hash using first 8KB + last 8KB + file size
@@ -249,7 +450,7 @@ func calculateQuickHash(path string) (string, error) {
 
 // calculateFullHash computes the full SHA-256 hash of a file using buffered I/O
 // Uses 256KB buffer for optimal throughput on SSDs
-func calculateFullHash(path string) (string, error) {
+func calculateFullHash(ctx context.Context, path string) (string, error) {
 	file, err := os.Open(path)
 	if err != nil {
 		return "", err
@@ -260,7 +461,7 @@ func calculateFullHash(path string) (string, error) {
 
 	// Use 256KB buffer for optimal SSD throughput
 	buf := make([]byte, 256*1024)
-	_, err = io.CopyBuffer(hash, file, buf)
+	_, err = io.CopyBuffer(hash, ctxReader{ctx: ctx, r: file}, buf)
 	if err != nil {
 		return "", err
 	}
@@ -268,12 +469,24 @@ func calculateFullHash(path string) (string, error) {
 	return hex.EncodeToString(hash.Sum(nil)), nil
 }
 
+// ctxReader stops a long read (hashing a multi-GB file) once ctx is done
+type ctxReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (c ctxReader) Read(p []byte) (int, error) {
+	if err := c.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return c.r.Read(p)
+}
+
 // GetDuplicateTotalSize gets the total size of duplicate files
 func GetDuplicateTotalSize(groups []DuplicateGroup) int64 {
 	var total int64
 	for _, g := range groups {
-		// Extra space occupied by each duplicate group = (file count - 1) * file size
-		total += int64(len(g.Files)-1) * g.Size
+		total += g.Reclaimable()
 	}
 	return total
 }
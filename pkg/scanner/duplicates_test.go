@@ -0,0 +1,200 @@
+package scanner
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDuplicateScanner_MultipleRoots(t *testing.T) {
+	tmpDir := t.TempDir()
+	dirA := filepath.Join(tmpDir, "a")
+	dirB := filepath.Join(tmpDir, "b")
+	os.MkdirAll(dirA, 0755)
+	os.MkdirAll(dirB, 0755)
+
+	content := make([]byte, 4096)
+	for i := range content {
+		content[i] = byte(i % 251)
+	}
+	os.WriteFile(filepath.Join(dirA, "one.bin"), content, 0644)
+	os.WriteFile(filepath.Join(dirB, "two.bin"), content, 0644)
+
+	// tmpDir overlaps both roots; files must not be counted twice
+	s := NewDuplicateScannerWithRoots([]string{dirA, dirB, tmpDir})
+	groups, err := s.Scan(nil)
+	if err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+
+	if len(groups) != 1 {
+		t.Fatalf("Expected 1 duplicate group, got %d", len(groups))
+	}
+	if len(groups[0].Files) != 2 {
+		t.Errorf("Expected 2 files in group, got %d", len(groups[0].Files))
+	}
+}
+
+func TestDuplicateScanner_MaxDepth(t *testing.T) {
+	root := t.TempDir()
+	content := bytes.Repeat([]byte("d"), 4096)
+
+	shallow := filepath.Join(root, "a.bin")
+	deep := filepath.Join(root, "x", "y", "z", "a.bin")
+	if err := os.MkdirAll(filepath.Dir(deep), 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range []string{shallow, filepath.Join(root, "x", "a.bin"), deep} {
+		if err := os.WriteFile(p, content, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	s := NewDuplicateScanner(root)
+	s.SetMaxDepth(2)
+	groups, err := s.Scan(nil)
+	if err != nil {
+		t.Fatalf("Scan() error: %v", err)
+	}
+	if len(groups) != 1 || len(groups[0].Files) != 2 {
+		t.Fatalf("Expected 1 group of 2 files within depth 2, got %+v", groups)
+	}
+	for _, f := range groups[0].Files {
+		if f.Path == deep {
+			t.Errorf("File below max depth was scanned: %s", f.Path)
+		}
+	}
+}
+
+func TestDuplicateScanner_Filters(t *testing.T) {
+	root := t.TempDir()
+	content := bytes.Repeat([]byte("f"), 4096)
+
+	files := []string{
+		filepath.Join(root, "a.JPG"),
+		filepath.Join(root, "b.jpg"),
+		filepath.Join(root, "c.txt"),
+		filepath.Join(root, "d.txt"),
+		filepath.Join(root, "node_modules", "e.jpg"),
+	}
+	for _, p := range files {
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, content, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	s := NewDuplicateScanner(root)
+	s.SetIncludeExtensions([]string{".jpg", "PNG"})
+	s.SetExcludePatterns([]string{"node_modules"})
+	groups, err := s.Scan(nil)
+	if err != nil {
+		t.Fatalf("Scan() error: %v", err)
+	}
+	if len(groups) != 1 || len(groups[0].Files) != 2 {
+		t.Fatalf("Expected 1 group of the 2 top-level .jpg files, got %+v", groups)
+	}
+	for _, f := range groups[0].Files {
+		if filepath.Dir(f.Path) != root || filepath.Ext(f.Path) == ".txt" {
+			t.Errorf("Filtered file was scanned: %s", f.Path)
+		}
+	}
+}
+
+func TestDuplicateScanner_Canceled(t *testing.T) {
+	root := t.TempDir()
+	content := bytes.Repeat([]byte("c"), 4096)
+	for _, name := range []string{"a.bin", "b.bin"} {
+		if err := os.WriteFile(filepath.Join(root, name), content, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	groups, err := NewDuplicateScanner(root).ScanContext(ctx, nil)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("ScanContext() error = %v, want context.Canceled", err)
+	}
+	if groups != nil {
+		t.Errorf("Canceled scan should return no groups, got %+v", groups)
+	}
+}
+
+func TestDuplicateScanner_ScanStream(t *testing.T) {
+	root := t.TempDir()
+	for i, content := range [][]byte{
+		bytes.Repeat([]byte("a"), 4096),
+		bytes.Repeat([]byte("b"), 8192),
+	} {
+		for _, name := range []string{"one", "two"} {
+			p := filepath.Join(root, fmt.Sprintf("%s%d.bin", name, i))
+			if err := os.WriteFile(p, content, 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	found := make(chan DuplicateGroup, 4)
+	groups, err := NewDuplicateScanner(root).ScanStream(context.Background(), nil, found)
+	if err != nil {
+		t.Fatalf("ScanStream() error: %v", err)
+	}
+	close(found)
+
+	var streamed []DuplicateGroup
+	for g := range found {
+		streamed = append(streamed, g)
+	}
+	if len(groups) != 2 || len(streamed) != 2 {
+		t.Fatalf("Expected 2 groups returned and streamed, got %d and %d", len(groups), len(streamed))
+	}
+	if groups[0].Size != 8192 {
+		t.Errorf("Expected the 8192-byte group first, got %d", groups[0].Size)
+	}
+}
+
+func TestDuplicateScanner_HardLinks(t *testing.T) {
+	root := t.TempDir()
+	content := bytes.Repeat([]byte("h"), 4096)
+
+	original := filepath.Join(root, "original.bin")
+	if err := os.WriteFile(original, content, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Link(original, filepath.Join(root, "link.bin")); err != nil {
+		t.Skipf("hard links not supported: %v", err)
+	}
+
+	// Two links to one file are not duplicates
+	groups, err := NewDuplicateScanner(root).Scan(nil)
+	if err != nil {
+		t.Fatalf("Scan() error: %v", err)
+	}
+	if len(groups) != 0 {
+		t.Fatalf("Expected no groups for hard links only, got %d", len(groups))
+	}
+
+	// A real copy is, but the linked file is listed once and deleting it
+	// would free nothing
+	if err := os.WriteFile(filepath.Join(root, "copy.bin"), content, 0644); err != nil {
+		t.Fatal(err)
+	}
+	groups, err = NewDuplicateScanner(root).Scan(nil)
+	if err != nil {
+		t.Fatalf("Scan() error: %v", err)
+	}
+	if len(groups) != 1 || len(groups[0].Files) != 2 {
+		t.Fatalf("Expected 1 group of 2 files, got %v", groups)
+	}
+	if got := groups[0].Reclaimable(); got != 4096 {
+		t.Errorf("Reclaimable() = %d, want 4096", got)
+	}
+}
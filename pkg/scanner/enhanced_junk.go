@@ -1,27 +1,114 @@
 package scanner
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
-	"os/exec"
+	"os/user"
 	"path/filepath"
 	"runtime"
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
 // EnhancedJunkScanner is the enhanced junk scanner
 type EnhancedJunkScanner struct {
-	targets []ScanTarget
-	errors  []string
+	targets    []ScanTarget
+	errors     []string
+	autoSelect string
+	minAgeDays int
+	minSize    int64    // directory targets of this size or less are not listed
+	only       []string // target names to scan, nil for all
 }
 
+// defaultMinTargetSize is the size a directory target must exceed to be
+// listed, unless the config's minDisplaySizeMB asks for smaller ones
+const defaultMinTargetSize = 10 * 1024 * 1024
+
 // NewEnhancedJunkScanner creates an enhanced junk scanner
 func NewEnhancedJunkScanner() *EnhancedJunkScanner {
+	cfg := LoadConfig()
 	return &EnhancedJunkScanner{
-		errors: make([]string, 0),
+		errors:     make([]string, 0),
+		autoSelect: cfg.AutoSelect,
+		minAgeDays: cfg.MinAgeDays,
+		minSize:    minTargetSize(cfg),
+	}
+}
+
+// minTargetSize is the listing floor for cfg: defaultMinTargetSize, or
+// minDisplaySizeMB when that is lower, so that the small targets the user
+// asked to see are listed at all
+func minTargetSize(cfg Config) int64 {
+	if mb := int64(cfg.MinDisplaySizeMB); mb > 0 && mb*1000*1000 < defaultMinTargetSize {
+		return mb * 1000 * 1000
+	}
+	return defaultMinTargetSize
+}
+
+// SetAutoSelect sets the auto-select policy applied to scanned targets
+func (s *EnhancedJunkScanner) SetAutoSelect(policy string) {
+	s.autoSelect = policy
+}
+
+// SetMinAge limits scanned targets to files not modified for days days:
+// each target then reports its stale part in StaleSize and carries the
+// cut-off in OlderThan for the cleaner. 0 turns the filter off.
+func (s *EnhancedJunkScanner) SetMinAge(days int) {
+	s.minAgeDays = days
+}
+
+// SetOnly limits scans to the targets named in names, see MatchTargets.
+// nil scans every target again.
+func (s *EnhancedJunkScanner) SetOnly(names []string) {
+	s.only = names
+}
+
+// MatchTargets returns the targets named in names, in targets' order, and
+// the names that matched none of them. Names are compared case-insensitively
+// and without surrounding spaces; empty names are ignored.
+func MatchTargets(targets []ScanTarget, names []string) (matched []ScanTarget, unknown []string) {
+	want := make(map[string]bool)
+	for _, name := range names {
+		if name = strings.ToLower(strings.TrimSpace(name)); name != "" {
+			want[name] = false
+		}
+	}
+	for _, t := range targets {
+		key := strings.ToLower(t.Name)
+		if _, ok := want[key]; ok {
+			matched = append(matched, t)
+			want[key] = true
+		}
+	}
+	for _, name := range names {
+		key := strings.ToLower(strings.TrimSpace(name))
+		if found, ok := want[key]; ok && !found {
+			unknown = append(unknown, strings.TrimSpace(name))
+			delete(want, key) // report each name once
+		}
 	}
+	return matched, unknown
+}
+
+// MinAgeDays returns the age filter in days, 0 if there is none
+func (s *EnhancedJunkScanner) MinAgeDays() int {
+	return s.minAgeDays
+}
+
+// SetMinSize sets the size a directory target must exceed to be listed
+func (s *EnhancedJunkScanner) SetMinSize(size int64) {
+	s.minSize = size
+}
+
+// MinSize returns the size a directory target must exceed to be listed
+func (s *EnhancedJunkScanner) MinSize() int64 {
+	return s.minSize
 }
 
 // GetErrors gets errors encountered during scanning
@@ -37,7 +124,7 @@ func (s *EnhancedJunkScanner) BuildTargets() []ScanTarget {
 		// === System Cache ===
 		{
 			Name:      "App Caches",
-			Path:      filepath.Join(homeDir, "Library", "Caches"),
+			Path:      CurrentPlatform.CacheDir(homeDir),
 			RiskLevel: RiskLow,
 			Selected:  true,
 		},
@@ -55,7 +142,7 @@ func (s *EnhancedJunkScanner) BuildTargets() []ScanTarget {
 		},
 		{
 			Name:      "Trash",
-			Path:      filepath.Join(homeDir, ".Trash"),
+			Path:      CurrentPlatform.TrashDir(homeDir),
 			RiskLevel: RiskLow,
 			Selected:  true,
 		},
@@ -79,6 +166,18 @@ func (s *EnhancedJunkScanner) BuildTargets() []ScanTarget {
 			RiskLevel: RiskMedium,
 			Selected:  false,
 		},
+		{
+			Name:      "watchOS DeviceSupport",
+			Path:      filepath.Join(homeDir, "Library", "Developer", "Xcode", "watchOS DeviceSupport"),
+			RiskLevel: RiskMedium,
+			Selected:  false,
+		},
+		{
+			Name:      "tvOS DeviceSupport",
+			Path:      filepath.Join(homeDir, "Library", "Developer", "Xcode", "tvOS DeviceSupport"),
+			RiskLevel: RiskMedium,
+			Selected:  false,
+		},
 		{
 			Name:      "iOS Simulator",
 			Path:      filepath.Join(homeDir, "Library", "Developer", "CoreSimulator"),
@@ -111,6 +210,30 @@ func (s *EnhancedJunkScanner) BuildTargets() []ScanTarget {
 			RiskLevel: RiskLow,
 			Selected:  true,
 		},
+		// The daemon directory holds the running daemons' registry and
+		// locks next to their logs, so it is not pre-selected
+		{
+			Name:      "Gradle Daemon Logs",
+			Path:      filepath.Join(homeDir, ".gradle", "daemon"),
+			RiskLevel: RiskMedium,
+			Selected:  false,
+		},
+
+		// === Bazel / Nix ===
+		// Bazel output trees are rebuilt from scratch, which for a large
+		// workspace takes a long time, so they are not pre-selected
+		{
+			Name:      "Bazel Cache",
+			Path:      filepath.Join(homeDir, ".cache", "bazel"),
+			RiskLevel: RiskMedium,
+			Selected:  false,
+		},
+		{
+			Name:      "Nix Cache",
+			Path:      filepath.Join(homeDir, ".cache", "nix"),
+			RiskLevel: RiskLow,
+			Selected:  true,
+		},
 
 		// === JavaScript / Node.js ===
 		{
@@ -418,10 +541,46 @@ func (s *EnhancedJunkScanner) BuildTargets() []ScanTarget {
 	}
 
 	targets = s.addDynamicTargets(targets, homeDir)
+	targets = s.addCustomTargets(targets, homeDir)
 
 	return targets
 }
 
+// BazelOutputRoot is where Bazel keeps its output trees on macOS, unless
+// --output_user_root says otherwise (on Linux it is ~/.cache/bazel). It
+// belongs to the user even though it lives under /private.
+func BazelOutputRoot() string {
+	name := os.Getenv("SUDO_USER")
+	if name == "" {
+		u, err := user.Current()
+		if err != nil {
+			return ""
+		}
+		name = u.Username
+	}
+	return "/private/var/tmp/_bazel_" + name
+}
+
+// addCustomTargets appends the user's targets.json entries, skipping paths
+// that are already built in. Invalid entries are reported via GetErrors.
+func (s *EnhancedJunkScanner) addCustomTargets(targets []ScanTarget, homeDir string) []ScanTarget {
+	custom, errs := loadCustomTargets(homeDir)
+	s.errors = append(s.errors, errs...)
+
+	known := make(map[string]bool, len(targets))
+	for _, t := range targets {
+		known[t.Path] = true
+	}
+	for _, t := range custom {
+		if known[t.Path] {
+			continue
+		}
+		known[t.Path] = true
+		targets = append(targets, t)
+	}
+	return targets
+}
+
 // addDynamicTargets adds dynamically discovered scan targets
 func (s *EnhancedJunkScanner) addDynamicTargets(targets []ScanTarget, homeDir string) []ScanTarget {
 	// Dynamic JetBrains IDE caches
@@ -453,6 +612,18 @@ func (s *EnhancedJunkScanner) addDynamicTargets(targets []ScanTarget, homeDir st
 		}
 	}
 
+	// Bazel's macOS output root, named after the user
+	if root := BazelOutputRoot(); root != "" {
+		if _, err := os.Stat(root); err == nil {
+			targets = append(targets, ScanTarget{
+				Name:      "Bazel Output Root",
+				Path:      root,
+				RiskLevel: RiskMedium,
+				Selected:  false,
+			})
+		}
+	}
+
 	// Dynamic browser caches
 	browserCaches := []struct {
 		name string
@@ -465,6 +636,10 @@ func (s *EnhancedJunkScanner) addDynamicTargets(targets []ScanTarget, homeDir st
 		{"Brave Cache", filepath.Join(homeDir, "Library", "Caches", "BraveSoftware")},
 		{"Arc Cache", filepath.Join(homeDir, "Library", "Caches", "company.thebrowser.Browser")},
 		{"Opera Cache", filepath.Join(homeDir, "Library", "Caches", "com.operasoftware.Opera")},
+		{"Chrome Canary Cache", filepath.Join(homeDir, "Library", "Caches", "Google", "Chrome Canary")},
+		{"Vivaldi Cache", filepath.Join(homeDir, "Library", "Caches", "Vivaldi")},
+		{"Waterfox Cache", filepath.Join(homeDir, "Library", "Caches", "Waterfox")},
+		{"Zen Cache", filepath.Join(homeDir, "Library", "Caches", "zen")},
 	}
 
 	for _, browser := range browserCaches {
@@ -486,6 +661,8 @@ func (s *EnhancedJunkScanner) addDynamicTargets(targets []ScanTarget, homeDir st
 		{"Chrome", filepath.Join(homeDir, "Library", "Application Support", "Google", "Chrome")},
 		{"Edge", filepath.Join(homeDir, "Library", "Application Support", "Microsoft Edge")},
 		{"Brave", filepath.Join(homeDir, "Library", "Application Support", "BraveSoftware", "Brave-Browser")},
+		{"Chrome Canary", filepath.Join(homeDir, "Library", "Application Support", "Google", "Chrome Canary")},
+		{"Vivaldi", filepath.Join(homeDir, "Library", "Application Support", "Vivaldi")},
 	}
 
 	for _, b := range chromiumBrowsers {
@@ -542,26 +719,71 @@ func (s *EnhancedJunkScanner) addDynamicTargets(targets []ScanTarget, homeDir st
 	return targets
 }
 
+// ParseScanProgress splits a "Scanning X/Y: <name>" progress message sent
+// by Scan into its parts. ok is false for any other message.
+func ParseScanProgress(msg string) (done, total int, name string, ok bool) {
+	rest, found := strings.CutPrefix(msg, "Scanning ")
+	if !found {
+		return 0, 0, "", false
+	}
+	counts, name, found := strings.Cut(rest, ": ")
+	if !found {
+		return 0, 0, "", false
+	}
+	x, y, found := strings.Cut(counts, "/")
+	if !found {
+		return 0, 0, "", false
+	}
+	done, errX := strconv.Atoi(x)
+	total, errY := strconv.Atoi(y)
+	if errX != nil || errY != nil {
+		return 0, 0, "", false
+	}
+	return done, total, name, true
+}
+
 // Scan performs the scan using du for fast size calculation
-// Uses concurrent worker pool for maximum throughput
+// Uses concurrent worker pool for maximum throughput.
+// Progress is reported as "Scanning X/Y: <name>", see ParseScanProgress.
 func (s *EnhancedJunkScanner) Scan(progressCh chan<- string) ([]ScanTarget, error) {
+	return s.ScanContext(context.Background(), progressCh)
+}
+
+// ScanContext is Scan with cancellation: once ctx is done, running du
+// processes are killed, remaining targets are skipped and ctx.Err() is returned
+func (s *EnhancedJunkScanner) ScanContext(ctx context.Context, progressCh chan<- string) ([]ScanTarget, error) {
 	s.errors = s.errors[:0]
 	targets := s.BuildTargets()
+	if s.only != nil {
+		targets, _ = MatchTargets(targets, s.only)
+	}
+	targets = collapseTargets(targets)
+	ApplyAutoSelect(targets, s.autoSelect)
+	applyLocks(targets, LoadLockedTargets())
+
+	var cutoff time.Time
+	if s.minAgeDays > 0 {
+		cutoff = time.Now().AddDate(0, 0, -s.minAgeDays)
+	}
 
 	// Use worker pool for concurrent scanning
 	numWorkers := runtime.NumCPU()
 	if numWorkers > 8 {
 		numWorkers = 8
 	}
+	numWorkers = scanWorkers(numWorkers)
 
 	type scanResult struct {
+		index  int
 		target ScanTarget
 		err    string
 		valid  bool
+		dir    bool
 	}
 
 	jobs := make(chan int, len(targets))
 	resultsCh := make(chan scanResult, len(targets))
+	var started int32
 
 	// Launch workers
 	var wg sync.WaitGroup
@@ -572,56 +794,18 @@ func (s *EnhancedJunkScanner) Scan(progressCh chan<- string) ([]ScanTarget, erro
 			for i := range jobs {
 				target := targets[i]
 
-				if progressCh != nil {
-					progressCh <- fmt.Sprintf("Scanning: %s", target.Name)
-				}
-
-				info, err := os.Lstat(target.Path)
-				if err != nil {
-					if !os.IsNotExist(err) {
-						resultsCh <- scanResult{err: fmt.Sprintf("%s: %v", target.Name, err)}
-					} else {
-						resultsCh <- scanResult{}
-					}
-					continue
-				}
-
-				// Skip symlinks
-				if info.Mode()&os.ModeSymlink != 0 {
-					resultsCh <- scanResult{}
+				if ctx.Err() != nil {
+					resultsCh <- scanResult{index: i}
 					continue
 				}
 
-				if !info.IsDir() {
-					target.Size = info.Size()
-					target.FileCount = 1
-					target.Files = []FileInfo{{
-						Path:     target.Path,
-						Name:     filepath.Base(target.Path),
-						Size:     info.Size(),
-						Modified: info.ModTime(),
-					}}
-					resultsCh <- scanResult{target: target, valid: true}
-					continue
-				}
-
-				size, permErr := getDirSizeDUFastWithPermissionCheck(target.Path)
-				if size < 0 {
-					if permErr {
-						// Path exists but permission denied - likely macOS Full Disk Access restriction
-						resultsCh <- scanResult{err: fmt.Sprintf("%s: permission denied (grant Full Disk Access in System Settings)", target.Name)}
-					}
-					// Silently skip if path doesn't exist or permission denied
-					continue
+				n := atomic.AddInt32(&started, 1)
+				if progressCh != nil {
+					progressCh <- fmt.Sprintf("Scanning %d/%d: %s", n, len(targets), target.Name)
 				}
 
-				if size > 10*1024*1024 {
-					target.Size = size
-					target.FileCount = -1
-					resultsCh <- scanResult{target: target, valid: true}
-				} else {
-					resultsCh <- scanResult{}
-				}
+				t, dir, ok, errMsg := measureTarget(ctx, target, cutoff, s.minSize)
+				resultsCh <- scanResult{index: i, target: t, err: errMsg, valid: ok, dir: dir}
 			}
 		}()
 	}
@@ -638,33 +822,306 @@ func (s *EnhancedJunkScanner) Scan(progressCh chan<- string) ([]ScanTarget, erro
 		close(resultsCh)
 	}()
 
-	var results []ScanTarget
+	measured := make([]*ScanTarget, len(targets))
+	isDir := make([]bool, len(targets))
 	for r := range resultsCh {
 		if r.err != "" {
 			s.errors = append(s.errors, r.err)
 		}
 		if r.valid {
-			results = append(results, r.target)
+			t := r.target
+			measured[r.index] = &t
+			isDir[r.index] = r.dir
 		}
 	}
 
+	// Take nested targets out of their parent so nothing is counted twice
+	byPath := make(map[string]*ScanTarget, len(targets))
+	for _, t := range measured {
+		if t != nil {
+			byPath[canonicalPath(t.Path)] = t
+		}
+	}
+	for _, t := range measured {
+		if t == nil {
+			continue
+		}
+		for _, ex := range t.Exclude {
+			if child, ok := byPath[canonicalPath(ex)]; ok {
+				t.Size -= child.Size
+			}
+		}
+		if t.Size < 0 {
+			t.Size = 0
+		}
+	}
+
+	// Small targets are not listed; nested ones go back into their parent
+	changed := foldSmallTargets(measured, isDir, s.minSize)
+
+	var results []ScanTarget
+	for i, t := range measured {
+		if t == nil {
+			continue
+		}
+		if changed[i] && t.Size > s.minSize && !t.SizeUnknown && ctx.Err() == nil {
+			countFiles(ctx, t, cutoff)
+		}
+		results = append(results, *t)
+	}
+
+	// A failed cache write only costs speed on the next scan
+	_ = dirSizeCache.Save()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 	return results, nil
 }
 
+// measureTarget sizes one target the way Scan does. ok is false when there
+// is nothing to show: the path is missing, a symlink or cannot be examined.
+// A directory du may not read is still shown, with SizeUnknown set. errMsg
+// says what went wrong when the user can do something about it. dir reports
+// whether the target is a directory, which Scan hides at minSize or less.
+// Files are only counted in directories over minSize.
+func measureTarget(ctx context.Context, target ScanTarget, cutoff time.Time, minSize int64) (t ScanTarget, dir, ok bool, errMsg string) {
+	info, err := os.Lstat(target.Path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			return target, false, false, fmt.Sprintf("%s: %v", target.Name, err)
+		}
+		return target, false, false, ""
+	}
+
+	// Skip symlinks
+	if info.Mode()&os.ModeSymlink != 0 {
+		return target, false, false, ""
+	}
+
+	target.OlderThan, target.StaleSize, target.StaleFiles = time.Time{}, 0, 0
+	target.Files = nil
+	target.SizeUnknown = false
+
+	if !info.IsDir() {
+		target.Size = info.Size()
+		target.FileCount = 1
+		if !cutoff.IsZero() {
+			target.OlderThan = cutoff
+			if info.ModTime().Before(cutoff) {
+				target.StaleSize, target.StaleFiles = info.Size(), 1
+			}
+		}
+		target.Files = []FileInfo{{
+			Path:     target.Path,
+			Name:     filepath.Base(target.Path),
+			Size:     info.Size(),
+			Modified: info.ModTime(),
+		}}
+		return target, false, true, ""
+	}
+
+	size, permErr := getDirSizeDUFastWithPermissionCheck(ctx, target.Path)
+	if size < 0 {
+		if permErr {
+			// Path exists but permission denied - likely macOS Full Disk Access
+			// restriction. It may be large, so it stays in the list, unsized.
+			target.Size, target.FileCount = 0, -1
+			target.SizeUnknown = true
+			target.Selected = false
+			return target, true, true, fmt.Sprintf("%s: permission denied (grant Full Disk Access in System Settings)", target.Name)
+		}
+		// Silently skip if path doesn't exist or permission denied
+		return target, true, false, ""
+	}
+
+	// The minSize cut-off is applied once nested targets are
+	// subtracted, so small nested targets still count
+	target.Size = size
+	target.FileCount = -1
+	if size > minSize {
+		countFiles(ctx, &target, cutoff)
+	}
+	return target, true, true, ""
+}
+
+// countFiles fills in the file count of a directory target, leaving out its
+// Exclude, and with a cutoff also how much of it is older
+func countFiles(ctx context.Context, target *ScanTarget, cutoff time.Time) {
+	if cutoff.IsZero() {
+		target.FileCount = dirFileCount(ctx, target.Path, target.Exclude)
+		return
+	}
+	target.OlderThan = cutoff
+	target.FileCount, target.StaleFiles, target.StaleSize = staleFiles(ctx, target.Path, target.Exclude, cutoff)
+}
+
+// RescanTarget measures a single target from an earlier scan again, without
+// the size cache, so its row can be refreshed without scanning everything.
+// Nested targets are taken out of its size as Scan does. ok is false when
+// Scan would no longer list the target: it is gone, or a directory now
+// holding MinSize or less; err is set when it can no longer be examined. A
+// target du is denied access to comes back with SizeUnknown, as from Scan.
+func (s *EnhancedJunkScanner) RescanTarget(ctx context.Context, target ScanTarget) (ScanTarget, bool, error) {
+	var cutoff time.Time
+	if s.minAgeDays > 0 {
+		cutoff = time.Now().AddDate(0, 0, -s.minAgeDays)
+	}
+
+	dirSizeCache.Forget(target.Path)
+	t, dir, ok, errMsg := measureTarget(ctx, target, cutoff, s.minSize)
+	if err := ctx.Err(); err != nil {
+		return target, false, err
+	}
+	if !ok {
+		if errMsg != "" {
+			return target, false, errors.New(errMsg)
+		}
+		return target, false, nil
+	}
+	if t.SizeUnknown {
+		return t, true, nil
+	}
+
+	for _, ex := range t.Exclude {
+		if size, _ := getDirSizeDUFastWithPermissionCheck(ctx, ex); size > 0 {
+			t.Size -= size
+		}
+	}
+	if t.Size < 0 {
+		t.Size = 0
+	}
+	_ = dirSizeCache.Save()
+
+	if dir && t.Size <= s.minSize {
+		return t, false, nil
+	}
+	return t, true, nil
+}
+
+// targetDUFlags are du's flags for a System Junk target, which also name
+// its mode in the size cache. -H follows a target that is itself a symlink;
+// links inside it are not followed, as cleaning moves those links rather
+// than what they point to.
+func targetDUFlags() string {
+	if followSymlinksEnabled() {
+		return "-skH"
+	}
+	return "-sk"
+}
+
 // getDirSizeDUFast uses the du command to quickly get directory size
 // It tolerates partial permission errors by using CombinedOutput and parsing stdout
 func getDirSizeDUFast(path string) int64 {
-	size, _ := getDirSizeDUFastWithPermissionCheck(path)
+	size, _ := getDirSizeDUFastWithPermissionCheck(context.Background(), path)
 	return size
 }
 
 // getDirSizeDUFastWithPermissionCheck uses du to get directory size and detects permission errors
 // Returns (size, isPermissionError)
-func getDirSizeDUFastWithPermissionCheck(path string) (int64, bool) {
-	cmd := exec.Command("du", "-sk", path)
+func getDirSizeDUFastWithPermissionCheck(ctx context.Context, path string) (int64, bool) {
+	if size, ok := dirSizeCache.Get(targetDUFlags(), path); ok {
+		return size, false
+	}
+
+	size, isPermError := duSizeWithPermissionCheck(ctx, path)
+	if size >= 0 {
+		dirSizeCache.Put(targetDUFlags(), path, size)
+	}
+	return size, isPermError
+}
+
+// deniedAt reports whether du's output says path itself could not be read,
+// as opposed to some directory below it: "du: cannot read directory
+// '/path': Permission denied" on Linux, "du: /path: Operation not
+// permitted" on macOS
+func deniedAt(output, path string) bool {
+	for _, line := range strings.Split(output, "\n") {
+		if !strings.Contains(line, "Operation not permitted") && !strings.Contains(line, "Permission denied") {
+			continue
+		}
+		if strings.Contains(line, path+"'") || strings.Contains(line, path+":") {
+			return true
+		}
+	}
+	return false
+}
+
+// walkFiles calls fn for every regular file under path, leaving out the
+// subtrees in exclude. Unreadable directories are skipped; the only error
+// returned is ctx's.
+func walkFiles(ctx context.Context, path string, exclude []string, fn func(path string, d fs.DirEntry)) error {
+	skip := make(map[string]bool, len(exclude))
+	for _, ex := range exclude {
+		skip[ex] = true
+	}
+
+	seen := 0
+	return filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+		if seen++; seen%1024 == 0 && ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err != nil {
+			// Unreadable subdirectory: count what we can see
+			return nil
+		}
+		if d.IsDir() {
+			if skip[p] {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.Type().IsRegular() {
+			fn(p, d)
+		}
+		return nil
+	})
+}
+
+// dirFileCount returns the number of regular files under path, leaving out
+// the subtrees in exclude. Counts are cached alongside du sizes; -1 means
+// the walk was cancelled.
+func dirFileCount(ctx context.Context, path string, exclude []string) int {
+	if n, ok := dirSizeCache.GetCount(targetDUFlags(), path); ok {
+		return n
+	}
+
+	count := 0
+	if err := walkFiles(ctx, path, exclude, func(string, fs.DirEntry) { count++ }); err != nil {
+		return -1
+	}
+
+	dirSizeCache.PutCount(targetDUFlags(), path, count)
+	return count
+}
+
+// staleFiles counts the files under path and those of them last modified
+// before cutoff, with their total size. files is -1 if the walk was
+// cancelled.
+func staleFiles(ctx context.Context, path string, exclude []string, cutoff time.Time) (files, stale int, staleSize int64) {
+	err := walkFiles(ctx, path, exclude, func(_ string, d fs.DirEntry) {
+		files++
+		if info, err := d.Info(); err == nil && info.ModTime().Before(cutoff) {
+			stale++
+			staleSize += info.Size()
+		}
+	})
+	if err != nil {
+		return -1, 0, 0
+	}
+	return files, stale, staleSize
+}
+
+// duSizeWithPermissionCheck runs du without consulting the size cache
+func duSizeWithPermissionCheck(ctx context.Context, path string) (int64, bool) {
+	cmd := ScanCommand(ctx, "du", targetDUFlags(), "--", path)
 	// Use CombinedOutput so we still get stdout even if du exits non-zero
 	// (happens when some subdirectories are permission-denied)
 	output, err := cmd.CombinedOutput()
+	if ctx.Err() != nil {
+		// A killed du prints nothing useful and must not reach the cache
+		return -1, false
+	}
 	outputStr := string(output)
 	
 	// Check for macOS Full Disk Access permission error
@@ -672,6 +1129,12 @@ func getDirSizeDUFastWithPermissionCheck(path string) (int64, bool) {
 	isPermError := strings.Contains(outputStr, "Operation not permitted") ||
 		strings.Contains(outputStr, "Permission denied")
 	
+	// du still prints a total, just the directory's own block, when the
+	// target itself cannot be read; that says nothing about its size
+	if isPermError && deniedAt(outputStr, path) {
+		return -1, true
+	}
+
 	// Try to parse even on error - du often prints partial results before failing
 	lines := strings.Split(outputStr, "\n")
 	for _, line := range lines {
@@ -718,7 +1181,7 @@ func calculateDirSizeDeep(path string) (int64, int, []FileInfo, error) {
 
 // getActualDiskUsageDU uses the du command to get actual disk usage
 func getActualDiskUsageDU(path string) int64 {
-	cmd := exec.Command("du", "-sk", path)
+	cmd := ScanCommand(context.Background(), "du", duFlags(), "--", path)
 	output, err := cmd.Output()
 	if err != nil {
 		return -1
@@ -749,9 +1212,13 @@ func calculateDirSizeWithLimit(path string, currentDepth, maxFiles int, visited
 		return 0, 0, nil, err
 	}
 
-	// If it's a symlink, skip
+	// If it's a symlink, skip unless followSymlinks is on
 	if pathInfo.Mode()&os.ModeSymlink != 0 {
-		return 0, 0, nil, nil
+		target, info, ok := resolveSymlink(path, visited)
+		if !ok {
+			return 0, 0, nil, nil
+		}
+		path, pathInfo = target, info
 	}
 
 	// Check if already visited (by inode)
@@ -785,9 +1252,13 @@ func calculateDirSizeWithLimit(path string, currentDepth, maxFiles int, visited
 			continue
 		}
 
-		// Skip symlinks
+		// Skip symlinks unless followSymlinks is on
 		if info.Mode()&os.ModeSymlink != 0 {
-			continue
+			target, targetInfo, ok := resolveSymlink(fullPath, visited)
+			if !ok {
+				continue
+			}
+			fullPath, info = target, targetInfo
 		}
 
 		if info.IsDir() {
@@ -811,7 +1282,7 @@ func calculateDirSizeWithLimit(path string, currentDepth, maxFiles int, visited
 			if len(files) < maxFiles {
 				files = append(files, FileInfo{
 					Path:     fullPath,
-					Name:     entry.Name(),
+					Name:     filepath.Base(fullPath),
 					Size:     info.Size(),
 					Modified: info.ModTime(),
 				})
@@ -868,7 +1339,7 @@ func AnalyzeSystemStorage() map[string]int64 {
 	homeDir := GetRealHomeDir()
 
 	// Get disk overview
-	cmd := exec.Command("du", "-sh", homeDir)
+	cmd := ScanCommand(context.Background(), "du", "-sh", "--", homeDir)
 	output, _ := cmd.Output()
 	result["Home Directory"] = parseSize(string(output))
 
@@ -885,7 +1356,7 @@ func AnalyzeSystemStorage() map[string]int64 {
 
 	for _, dir := range keyDirs {
 		fullPath := filepath.Join(homeDir, dir)
-		cmd := exec.Command("du", "-sh", fullPath)
+		cmd := ScanCommand(context.Background(), "du", "-sh", "--", fullPath)
 		output, _ := cmd.Output()
 		result[dir] = parseSize(string(output))
 	}
@@ -989,6 +1460,7 @@ func ScanTargetDetail(targetPath string) ([]DetailEntry, error) {
 	if numWorkers > 8 {
 		numWorkers = 8
 	}
+	numWorkers = scanWorkers(numWorkers)
 
 	jobs := make(chan os.DirEntry, len(entries))
 	results := make(chan result, len(entries))
@@ -1,10 +1,12 @@
 package scanner
 
 import (
+	"context"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestNewEnhancedJunkScanner(t *testing.T) {
@@ -121,3 +123,211 @@ func BenchmarkBuildTargets(b *testing.B) {
 		_ = scanner.BuildTargets()
 	}
 }
+
+func TestParseScanProgress(t *testing.T) {
+	done, total, name, ok := ParseScanProgress("Scanning 3/41: Xcode DerivedData")
+	if !ok || done != 3 || total != 41 || name != "Xcode DerivedData" {
+		t.Errorf("Unexpected parse: %d %d %q %v", done, total, name, ok)
+	}
+
+	for _, msg := range []string{"Cleaning: App Caches", "Scanning: App Caches", "Scanning x/4: A"} {
+		if _, _, _, ok := ParseScanProgress(msg); ok {
+			t.Errorf("ParseScanProgress(%q) should fail", msg)
+		}
+	}
+}
+
+func TestEnhancedJunkScanner_ScanReportsCounts(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("SUDO_USER", "")
+
+	s := NewEnhancedJunkScanner()
+	total := len(s.BuildTargets())
+	progressCh := make(chan string, total)
+
+	if _, err := s.Scan(progressCh); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	close(progressCh)
+
+	seen := make(map[int]bool)
+	for msg := range progressCh {
+		done, got, _, ok := ParseScanProgress(msg)
+		if !ok {
+			t.Fatalf("Unexpected progress message %q", msg)
+		}
+		if got != total || done < 1 || done > total || seen[done] {
+			t.Errorf("Bad progress %q for %d targets", msg, total)
+		}
+		seen[done] = true
+	}
+	if len(seen) != total {
+		t.Errorf("Expected %d progress messages, got %d", total, len(seen))
+	}
+}
+
+func TestDirFileCount(t *testing.T) {
+	root := t.TempDir()
+	nested := filepath.Join(root, "nested")
+	for _, dir := range []string{filepath.Join(root, "a", "b"), nested} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, f := range []string{"one", "a/two", "a/b/three", "nested/four", "nested/five"} {
+		if err := os.WriteFile(filepath.Join(root, f), []byte("x"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Symlink(filepath.Join(root, "one"), filepath.Join(root, "link")); err != nil {
+		t.Fatal(err)
+	}
+
+	if n := dirFileCount(context.Background(), root, nil); n != 5 {
+		t.Errorf("Expected 5 files, got %d", n)
+	}
+	if n := dirFileCount(context.Background(), root, []string{nested}); n != 3 {
+		t.Errorf("Expected 3 files outside the excluded subtree, got %d", n)
+	}
+}
+
+func TestStaleFiles(t *testing.T) {
+	root := t.TempDir()
+	old := time.Now().AddDate(0, 0, -30)
+	files := map[string]bool{"old1": true, "old2": true, "fresh": false}
+	for name, isOld := range files {
+		p := filepath.Join(root, name)
+		if err := os.WriteFile(p, []byte("12345"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if isOld {
+			if err := os.Chtimes(p, old, old); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	total, stale, size := staleFiles(context.Background(), root, nil, time.Now().AddDate(0, 0, -7))
+	if total != 3 || stale != 2 || size != 10 {
+		t.Errorf("staleFiles() = %d, %d, %d; want 3, 2, 10", total, stale, size)
+	}
+}
+
+func TestScanTarget_Reclaimable(t *testing.T) {
+	whole := ScanTarget{Size: 100, StaleSize: 40}
+	if whole.Reclaimable() != 100 {
+		t.Errorf("Without an age filter the whole target is reclaimable, got %d", whole.Reclaimable())
+	}
+	aged := ScanTarget{Size: 100, StaleSize: 40, OlderThan: time.Now()}
+	if aged.Reclaimable() != 40 {
+		t.Errorf("With an age filter only the stale part is reclaimable, got %d", aged.Reclaimable())
+	}
+}
+
+func TestMatchTargets(t *testing.T) {
+	targets := []ScanTarget{
+		{Name: "npm Cache"},
+		{Name: "Xcode DerivedData"},
+		{Name: "Homebrew Cache"},
+	}
+
+	matched, unknown := MatchTargets(targets, []string{" xcode deriveddata", "NPM CACHE", "Bazel Cache", "", "bazel cache"})
+	if len(matched) != 2 || matched[0].Name != "npm Cache" || matched[1].Name != "Xcode DerivedData" {
+		t.Errorf("Expected npm Cache and Xcode DerivedData in target order, got %v", matched)
+	}
+	if len(unknown) != 1 || unknown[0] != "Bazel Cache" {
+		t.Errorf("Expected Bazel Cache to be reported once as unknown, got %v", unknown)
+	}
+}
+
+func TestRescanTarget(t *testing.T) {
+	root := t.TempDir()
+	big := filepath.Join(root, "big.bin")
+	if err := os.WriteFile(big, make([]byte, 11*1024*1024), 0644); err != nil {
+		t.Fatal(err)
+	}
+	s := NewEnhancedJunkScanner()
+	target := ScanTarget{Name: "Test Cache", Path: root, Selected: true}
+
+	got, ok, err := s.RescanTarget(context.Background(), target)
+	if err != nil || !ok {
+		t.Fatalf("RescanTarget() = %v, %v; want the target listed", ok, err)
+	}
+	if got.Size < 11*1024*1024 || !got.Selected {
+		t.Errorf("Rescanned target = size %d, selected %v; want at least 11 MB and still selected", got.Size, got.Selected)
+	}
+
+	// Emptied since: no longer listed, as a full scan would drop it
+	if err := os.Remove(big); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok, err := s.RescanTarget(context.Background(), got); ok || err != nil {
+		t.Errorf("Emptied target: RescanTarget() = %v, %v; want not listed, no error", ok, err)
+	}
+
+	missing := ScanTarget{Name: "Gone", Path: filepath.Join(root, "gone")}
+	if _, ok, err := s.RescanTarget(context.Background(), missing); ok || err != nil {
+		t.Errorf("Missing target: RescanTarget() = %v, %v; want not listed, no error", ok, err)
+	}
+}
+
+func TestDeniedAt(t *testing.T) {
+	tests := []struct {
+		output string
+		want   bool
+	}{
+		{"du: cannot read directory '/c/Mail': Permission denied\n4\t/c/Mail\n", true},
+		{"du: /c/Mail: Operation not permitted\n0\t/c/Mail\n", true},
+		{"du: cannot read directory '/c/Mail/V10': Permission denied\n900\t/c/Mail\n", false},
+		{"du: /c/Mail/V10: Operation not permitted\n900\t/c/Mail\n", false},
+		{"900\t/c/Mail\n", false},
+	}
+	for _, tt := range tests {
+		if got := deniedAt(tt.output, "/c/Mail"); got != tt.want {
+			t.Errorf("deniedAt(%q) = %v, want %v", tt.output, got, tt.want)
+		}
+	}
+}
+
+func TestRescanTarget_AccessDenied(t *testing.T) {
+	if os.Geteuid() == 0 {
+		t.Skip("root can read every directory")
+	}
+	root := t.TempDir()
+	locked := filepath.Join(root, "locked")
+	if err := os.Mkdir(locked, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(locked, "big.bin"), make([]byte, 11*1024*1024), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chmod(locked, 0); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chmod(locked, 0755)
+
+	target := ScanTarget{Name: "Locked Cache", Path: locked, Selected: true}
+	got, ok, err := NewEnhancedJunkScanner().RescanTarget(context.Background(), target)
+	if err != nil || !ok {
+		t.Fatalf("RescanTarget() = %v, %v; want the target listed", ok, err)
+	}
+	if !got.SizeUnknown || got.Size != 0 || got.Selected {
+		t.Errorf("Unreadable target = %+v; want size unknown, 0 and not selected", got)
+	}
+}
+
+func TestMinTargetSize(t *testing.T) {
+	tests := []struct {
+		mb   int
+		want int64
+	}{
+		{0, defaultMinTargetSize},
+		{1, 1000 * 1000},
+		{100, defaultMinTargetSize},
+	}
+	for _, tt := range tests {
+		if got := minTargetSize(Config{MinDisplaySizeMB: tt.mb}); got != tt.want {
+			t.Errorf("minTargetSize(%d MB) = %d, want %d", tt.mb, got, tt.want)
+		}
+	}
+}
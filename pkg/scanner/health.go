@@ -0,0 +1,66 @@
+package scanner
+
+// Weights of the disk health score. Free space is worth up to
+// healthFreePoints, full marks from healthFreeTarget free; the rest is lost
+// as reclaimable space approaches healthWasteLimit of the disk.
+const (
+	healthFreePoints  = 60
+	healthWastePoints = 40
+	healthFreeTarget  = 0.25
+	healthWasteLimit  = 0.10
+)
+
+// DiskHealth holds the figures the disk health score is computed from.
+// Sizes below zero are unknown and count as nothing to reclaim.
+type DiskHealth struct {
+	Total   uint64 // Disk size in bytes
+	Used    uint64 // Used bytes
+	Junk    int64  // Reclaimable System Junk
+	Zombies int64  // Large files untouched for a year or more
+	Trash   int64  // Size of the Trash
+}
+
+// Reclaimable is the space a cleanup could likely free. Zombie files count
+// half: they are only candidates, and some are kept on purpose.
+func (d DiskHealth) Reclaimable() int64 {
+	var r int64
+	for _, size := range []int64{d.Junk, d.Trash, d.Zombies / 2} {
+		if size > 0 {
+			r += size
+		}
+	}
+	return r
+}
+
+// Score rates the disk from 0 to 100, or returns -1 without disk figures
+func (d DiskHealth) Score() int {
+	if d.Total == 0 || d.Used > d.Total {
+		return -1
+	}
+	total := float64(d.Total)
+
+	free := float64(d.Total-d.Used) / total / healthFreeTarget
+	if free > 1 {
+		free = 1
+	}
+	waste := float64(d.Reclaimable()) / total / healthWasteLimit
+	if waste > 1 {
+		waste = 1
+	}
+
+	return int(healthFreePoints*free + healthWastePoints*(1-waste) + 0.5)
+}
+
+// HealthLabel describes a Score in words
+func HealthLabel(score int) string {
+	switch {
+	case score < 0:
+		return "Unknown"
+	case score >= 80:
+		return "Good"
+	case score >= 50:
+		return "Fair"
+	default:
+		return "Needs attention"
+	}
+}
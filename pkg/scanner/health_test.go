@@ -0,0 +1,49 @@
+package scanner
+
+import "testing"
+
+func TestDiskHealth_Score(t *testing.T) {
+	const gb = 1 << 30
+	tests := []struct {
+		name     string
+		health   DiskHealth
+		expected int
+	}{
+		{"unknown disk", DiskHealth{}, -1},
+		{"plenty free, nothing to clean", DiskHealth{Total: 100 * gb, Used: 50 * gb}, 100},
+		{"full disk", DiskHealth{Total: 100 * gb, Used: 100 * gb}, 40},
+		{"half the free target", DiskHealth{Total: 100 * gb, Used: 87.5 * gb}, 70},
+		{"5% of the disk is junk", DiskHealth{Total: 100 * gb, Used: 50 * gb, Junk: 5 * gb}, 80},
+		{"waste capped", DiskHealth{Total: 100 * gb, Used: 100 * gb, Junk: 30 * gb, Trash: 30 * gb}, 0},
+		{"zombies count half", DiskHealth{Total: 100 * gb, Used: 50 * gb, Zombies: 10 * gb}, 80},
+		{"unknown sizes ignored", DiskHealth{Total: 100 * gb, Used: 50 * gb, Junk: -1, Trash: -1}, 100},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.health.Score(); got != tt.expected {
+				t.Errorf("Score() = %d, want %d", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestHealthLabel(t *testing.T) {
+	tests := []struct {
+		score    int
+		expected string
+	}{
+		{-1, "Unknown"},
+		{100, "Good"},
+		{80, "Good"},
+		{79, "Fair"},
+		{50, "Fair"},
+		{49, "Needs attention"},
+	}
+
+	for _, tt := range tests {
+		if got := HealthLabel(tt.score); got != tt.expected {
+			t.Errorf("HealthLabel(%d) = %q, want %q", tt.score, got, tt.expected)
+		}
+	}
+}
@@ -1,17 +1,34 @@
 package scanner
 
 import (
+	"encoding/csv"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"sort"
+	"strconv"
 	"time"
 )
 
 const (
-	historyFileName = "disk_history.json"
-	maxHistoryDays  = 90
+	historyFileName    = "disk_history.json"
+	categoryFileName   = "category_history.json"
+	defaultHistoryDays = 90
+
+	// maxSnapshotsPerDay caps how many plain readings one day keeps, so a
+	// long retention cannot grow the history file without bound. Cleanups
+	// and other events are always kept and do not count against the cap;
+	// readings beyond it are dropped oldest first.
+	maxSnapshotsPerDay = 48
+
+	// A scan-only snapshot is not recorded when the last one was also
+	// scan-only, is under coalesceWindow old, and disk usage has moved by
+	// less than coalesceBytes since. Every view records a snapshot when it
+	// scans, which otherwise floods the activity log with identical rows.
+	coalesceWindow = time.Hour
+	coalesceBytes  = 1 << 30
 )
 
 // DiskSnapshot represents a disk snapshot
@@ -25,7 +42,8 @@ type DiskSnapshot struct {
 	Details     string    `json:"details,omitempty"` // What was cleaned (e.g., "Xcode Cache, npm Cache")
 }
 
-// CategorySnapshot represents a category snapshot
+// CategorySnapshot represents a category snapshot: bytes reclaimed per
+// cleanup category (the snapshot trigger, e.g. "system_junk") at one point
 type CategorySnapshot struct {
 	Timestamp time.Time          `json:"timestamp"`
 	Category  map[string]int64   `json:"category"`
@@ -33,22 +51,25 @@ type CategorySnapshot struct {
 
 // HistoryManager is the history manager
 type HistoryManager struct {
-	dataDir string
+	dataDir       string
+	retentionDays int // 0 means defaultHistoryDays
 }
 
 // NewHistoryManager creates a history manager
 func NewHistoryManager() (*HistoryManager, error) {
-	homeDir := GetRealHomeDir()
-	if homeDir == "" {
+	dataDir := LumeDataDir()
+	if dataDir == "" {
 		return nil, fmt.Errorf("cannot determine home directory")
 	}
 
-	dataDir := filepath.Join(homeDir, ".config", "lume")
 	if err := os.MkdirAll(dataDir, 0755); err != nil {
 		return nil, err
 	}
 
-	return &HistoryManager{dataDir: dataDir}, nil
+	return &HistoryManager{
+		dataDir:       dataDir,
+		retentionDays: LoadConfig().HistoryRetentionDays(),
+	}, nil
 }
 
 // RecordSnapshot records a disk snapshot
@@ -68,11 +89,114 @@ func (h *HistoryManager) RecordSnapshot(total, used uint64, cleanedSize int64, t
 		snapshots = []DiskSnapshot{}
 	}
 
+	if n := len(snapshots); n > 0 && coalesces(snapshots[n-1], snapshot) {
+		return nil
+	}
+
 	snapshots = append(snapshots, snapshot)
 
 	snapshots = h.pruneOldSnapshots(snapshots)
 
-	return h.saveSnapshots(snapshots)
+	if err := h.saveSnapshots(snapshots); err != nil {
+		return err
+	}
+
+	if cleanedSize > 0 && trigger != "" {
+		return h.recordCategory(snapshot.Timestamp, trigger, cleanedSize)
+	}
+	return nil
+}
+
+// isScanOnly reports whether s is a plain reading rather than a cleanup or
+// another event worth its own row (emptying the Trash records details)
+func isScanOnly(s DiskSnapshot) bool {
+	return s.CleanedSize == 0 && s.Details == ""
+}
+
+// coalesces reports whether next adds nothing over prev, the last recorded
+// snapshot, and can be dropped
+func coalesces(prev, next DiskSnapshot) bool {
+	if !isScanOnly(prev) || !isScanOnly(next) {
+		return false
+	}
+	if next.Timestamp.Sub(prev.Timestamp) >= coalesceWindow {
+		return false
+	}
+	delta := int64(next.UsedBytes) - int64(prev.UsedBytes)
+	if delta < 0 {
+		delta = -delta
+	}
+	return delta < coalesceBytes
+}
+
+// recordCategory appends a category snapshot for one cleanup
+func (h *HistoryManager) recordCategory(ts time.Time, category string, size int64) error {
+	snapshots, err := h.LoadCategorySnapshots()
+	if err != nil {
+		snapshots = []CategorySnapshot{}
+	}
+
+	snapshots = append(snapshots, CategorySnapshot{
+		Timestamp: ts,
+		Category:  map[string]int64{category: size},
+	})
+
+	cutoff := time.Now().AddDate(0, 0, -h.retention())
+	var kept []CategorySnapshot
+	for _, s := range snapshots {
+		if s.Timestamp.After(cutoff) {
+			kept = append(kept, s)
+		}
+	}
+
+	data, err := json.MarshalIndent(kept, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(h.dataDir, categoryFileName), data, 0644)
+}
+
+// LoadCategorySnapshots loads all category snapshots
+func (h *HistoryManager) LoadCategorySnapshots() ([]CategorySnapshot, error) {
+	data, err := os.ReadFile(filepath.Join(h.dataDir, categoryFileName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []CategorySnapshot{}, nil
+		}
+		return nil, err
+	}
+
+	var snapshots []CategorySnapshot
+	if err := json.Unmarshal(data, &snapshots); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].Timestamp.Before(snapshots[j].Timestamp)
+	})
+
+	return snapshots, nil
+}
+
+// GetCategoryTotals sums the bytes reclaimed per category over the last N days
+func (h *HistoryManager) GetCategoryTotals(days int) (map[string]int64, error) {
+	snapshots, err := h.LoadCategorySnapshots()
+	if err != nil {
+		return nil, err
+	}
+
+	cutoff := time.Now().AddDate(0, 0, -days)
+	totals := make(map[string]int64)
+	for _, s := range snapshots {
+		if !s.Timestamp.After(cutoff) {
+			continue
+		}
+		for category, size := range s.Category {
+			totals[category] += size
+		}
+	}
+
+	return totals, nil
 }
 
 // LoadSnapshots loads all snapshots
@@ -117,6 +241,28 @@ func (h *HistoryManager) GetRecentSnapshots(days int) ([]DiskSnapshot, error) {
 	return recent, nil
 }
 
+// WriteSnapshotsCSV writes snapshots as CSV, one row per snapshot, with a
+// header row. Sizes are in bytes and timestamps in RFC 3339.
+func WriteSnapshotsCSV(w io.Writer, snapshots []DiskSnapshot) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"timestamp", "total_bytes", "used_bytes", "free_bytes", "cleaned_bytes", "trigger", "details"})
+
+	for _, s := range snapshots {
+		cw.Write([]string{
+			s.Timestamp.Format(time.RFC3339),
+			strconv.FormatUint(s.TotalBytes, 10),
+			strconv.FormatUint(s.UsedBytes, 10),
+			strconv.FormatUint(s.FreeBytes, 10),
+			strconv.FormatInt(s.CleanedSize, 10),
+			s.Trigger,
+			s.Details,
+		})
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
 // GetDailySnapshots gets daily snapshots (keeping only one per day)
 func (h *HistoryManager) GetDailySnapshots(days int) ([]DiskSnapshot, error) {
 	snapshots, err := h.GetRecentSnapshots(days)
@@ -126,6 +272,10 @@ func (h *HistoryManager) GetDailySnapshots(days int) ([]DiskSnapshot, error) {
 
 	dailyMap := make(map[string]DiskSnapshot)
 	for _, s := range snapshots {
+		// Cleanups recorded while df was unavailable have no disk numbers
+		if s.TotalBytes == 0 {
+			continue
+		}
 		dateKey := s.Timestamp.Format("2006-01-02")
 		if existing, ok := dailyMap[dateKey]; !ok || s.Timestamp.After(existing.Timestamp) {
 			dailyMap[dateKey] = s
@@ -144,6 +294,39 @@ func (h *HistoryManager) GetDailySnapshots(days int) ([]DiskSnapshot, error) {
 	return result, nil
 }
 
+// SnapshotComparison describes what changed between two snapshots
+type SnapshotComparison struct {
+	From, To DiskSnapshot
+	// UsedDelta is To's used space minus From's; only meaningful when
+	// HasDisk is set, as both snapshots then carry disk figures
+	UsedDelta int64
+	HasDisk   bool
+	// Events are the cleanups and Trash empties after From, up to and
+	// including To, oldest first; Cleaned sums what they reclaimed
+	Events  []DiskSnapshot
+	Cleaned int64
+}
+
+// CompareSnapshots compares snapshots[a] and snapshots[b] of a slice in
+// time order, whichever of the two comes first
+func CompareSnapshots(snapshots []DiskSnapshot, a, b int) SnapshotComparison {
+	if a > b {
+		a, b = b, a
+	}
+	c := SnapshotComparison{From: snapshots[a], To: snapshots[b]}
+	if c.From.TotalBytes > 0 && c.To.TotalBytes > 0 {
+		c.HasDisk = true
+		c.UsedDelta = int64(c.To.UsedBytes) - int64(c.From.UsedBytes)
+	}
+	for _, s := range snapshots[a+1 : b+1] {
+		if !isScanOnly(s) {
+			c.Events = append(c.Events, s)
+			c.Cleaned += s.CleanedSize
+		}
+	}
+	return c
+}
+
 // GetStatistics gets statistics data
 func (h *HistoryManager) GetStatistics() (*HistoryStatistics, error) {
 	snapshots, err := h.LoadSnapshots()
@@ -173,14 +356,43 @@ func (h *HistoryManager) GetStatistics() (*HistoryStatistics, error) {
 	return stats, nil
 }
 
-// pruneOldSnapshots prunes old snapshots
+// retention is how many days of history are kept
+func (h *HistoryManager) retention() int {
+	if h.retentionDays > 0 {
+		return h.retentionDays
+	}
+	return defaultHistoryDays
+}
+
+// pruneOldSnapshots drops snapshots older than the retention period and
+// thins out days holding more than maxSnapshotsPerDay. snapshots must be in
+// time order.
 func (h *HistoryManager) pruneOldSnapshots(snapshots []DiskSnapshot) []DiskSnapshot {
-	cutoff := time.Now().AddDate(0, 0, -maxHistoryDays)
+	cutoff := time.Now().AddDate(0, 0, -h.retention())
+
+	// Readings (no cleanup or other event) to drop per day, counted before
+	// dropping any
+	excess := make(map[string]int)
+	for _, s := range snapshots {
+		if s.Timestamp.After(cutoff) && isScanOnly(s) {
+			excess[s.Timestamp.Format("2006-01-02")]++
+		}
+	}
+	for day, n := range excess {
+		excess[day] = n - maxSnapshotsPerDay
+	}
+
 	var result []DiskSnapshot
 	for _, s := range snapshots {
-		if s.Timestamp.After(cutoff) {
-			result = append(result, s)
+		if !s.Timestamp.After(cutoff) {
+			continue
 		}
+		day := s.Timestamp.Format("2006-01-02")
+		if isScanOnly(s) && excess[day] > 0 {
+			excess[day]--
+			continue
+		}
+		result = append(result, s)
 	}
 	return result
 }
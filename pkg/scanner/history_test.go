@@ -1,8 +1,12 @@
 package scanner
 
 import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 )
@@ -121,6 +125,28 @@ func TestHistoryManager_GetDailySnapshots(t *testing.T) {
 	}
 }
 
+func TestHistoryManager_GetDailySnapshots_SkipsUnknownDisk(t *testing.T) {
+	tmpDir := t.TempDir()
+	hm := &HistoryManager{dataDir: tmpDir}
+
+	now := time.Now()
+	snapshots := []DiskSnapshot{
+		{Timestamp: now.Add(-time.Minute), TotalBytes: 1000000, UsedBytes: 500000},
+		{Timestamp: now, CleanedSize: 1024, Trigger: "system_junk"},
+	}
+	if err := hm.saveSnapshots(snapshots); err != nil {
+		t.Fatalf("saveSnapshots failed: %v", err)
+	}
+
+	daily, err := hm.GetDailySnapshots(7)
+	if err != nil {
+		t.Fatalf("GetDailySnapshots failed: %v", err)
+	}
+	if len(daily) != 1 || daily[0].TotalBytes != 1000000 {
+		t.Errorf("Expected only the snapshot with disk numbers, got %+v", daily)
+	}
+}
+
 func TestHistoryManager_GetStatistics(t *testing.T) {
 	tmpDir := t.TempDir()
 	hm := &HistoryManager{dataDir: tmpDir}
@@ -278,6 +304,115 @@ func TestHistoryManager_pruneOldSnapshots(t *testing.T) {
 	}
 }
 
+func TestHistoryManager_pruneOldSnapshots_Retention(t *testing.T) {
+	hm := &HistoryManager{retentionDays: 365}
+
+	snapshots := []DiskSnapshot{
+		{Timestamp: time.Now().AddDate(0, 0, -400)}, // Too old
+		{Timestamp: time.Now().AddDate(0, 0, -200)}, // Kept with a year's retention
+		{Timestamp: time.Now().AddDate(0, 0, -10)},
+	}
+
+	if pruned := hm.pruneOldSnapshots(snapshots); len(pruned) != 2 {
+		t.Errorf("Expected 2 snapshots after pruning, got %d", len(pruned))
+	}
+}
+
+func TestHistoryManager_pruneOldSnapshots_PerDayCap(t *testing.T) {
+	hm := &HistoryManager{}
+
+	day := time.Now().Add(-time.Hour).Truncate(24 * time.Hour).Add(time.Hour)
+	var snapshots []DiskSnapshot
+	for i := 0; i < maxSnapshotsPerDay+10; i++ {
+		snapshots = append(snapshots, DiskSnapshot{Timestamp: day.Add(time.Duration(i) * time.Second)})
+	}
+	// A cleanup and an emptied Trash early in the day survive the cap and
+	// do not count against it
+	snapshots[0].CleanedSize = 100
+	snapshots[1].Details = "Emptied Trash, 2 GB freed"
+
+	pruned := hm.pruneOldSnapshots(snapshots)
+	if len(pruned) != maxSnapshotsPerDay+2 {
+		t.Fatalf("Expected %d snapshots after capping, got %d", maxSnapshotsPerDay+2, len(pruned))
+	}
+	if pruned[0].CleanedSize != 100 {
+		t.Error("Expected the cleanup snapshot to be kept")
+	}
+	if pruned[1].Details == "" {
+		t.Error("Expected the Trash snapshot to be kept")
+	}
+	// The newest readings are the ones kept
+	if last := pruned[len(pruned)-1].Timestamp; !last.Equal(snapshots[len(snapshots)-1].Timestamp) {
+		t.Errorf("Expected the latest snapshot kept, got %v", last)
+	}
+}
+
+func TestHistoryManager_RecordSnapshot_CoalescesScans(t *testing.T) {
+	hm := &HistoryManager{dataDir: t.TempDir()}
+
+	hm.RecordSnapshot(100<<30, 50<<30, 0, "system_junk", "")
+	hm.RecordSnapshot(100<<30, 50<<30+1<<20, 0, "large_files", "") // Same hour, barely changed
+	hm.RecordSnapshot(100<<30, 52<<30, 0, "duplicates", "")        // Usage moved by 2 GiB
+	hm.RecordSnapshot(100<<30, 51<<30, 1<<30, "duplicates", "a")   // Cleanups are always kept
+	hm.RecordSnapshot(100<<30, 51<<30, 0, "empty_trash", "Emptied Trash")
+	hm.RecordSnapshot(100<<30, 51<<30, 0, "system_junk", "")
+
+	snapshots, err := hm.LoadSnapshots()
+	if err != nil {
+		t.Fatalf("LoadSnapshots failed: %v", err)
+	}
+	var triggers []string
+	for _, s := range snapshots {
+		triggers = append(triggers, s.Trigger)
+	}
+	want := []string{"system_junk", "duplicates", "duplicates", "empty_trash", "system_junk"}
+	if strings.Join(triggers, ",") != strings.Join(want, ",") {
+		t.Errorf("Expected snapshots %v, got %v", want, triggers)
+	}
+}
+
+func TestCoalesces_Window(t *testing.T) {
+	now := time.Now()
+	prev := DiskSnapshot{Timestamp: now.Add(-2 * time.Hour), UsedBytes: 100}
+	next := DiskSnapshot{Timestamp: now, UsedBytes: 100}
+	if coalesces(prev, next) {
+		t.Error("Expected a scan more than an hour later to be recorded")
+	}
+	prev.Timestamp = now.Add(-10 * time.Minute)
+	if !coalesces(prev, next) {
+		t.Error("Expected an unchanged scan within the hour to be dropped")
+	}
+}
+
+func TestCompareSnapshots(t *testing.T) {
+	now := time.Now()
+	snapshots := []DiskSnapshot{
+		{Timestamp: now.AddDate(0, 0, -30), TotalBytes: 1000, UsedBytes: 600},
+		{Timestamp: now.AddDate(0, 0, -20), TotalBytes: 1000, UsedBytes: 500, CleanedSize: 100, Details: "npm Cache"},
+		{Timestamp: now.AddDate(0, 0, -10), TotalBytes: 1000, UsedBytes: 700},
+		{Timestamp: now.AddDate(0, 0, -5), CleanedSize: 50, Trigger: "app_uninstall"}, // No disk figures
+		{Timestamp: now, TotalBytes: 1000, UsedBytes: 900},
+	}
+
+	c := CompareSnapshots(snapshots, 4, 0) // Either order
+	if !c.From.Timestamp.Equal(snapshots[0].Timestamp) || !c.To.Timestamp.Equal(snapshots[4].Timestamp) {
+		t.Error("Expected From to be the earlier snapshot")
+	}
+	if !c.HasDisk || c.UsedDelta != 300 {
+		t.Errorf("Expected +300 used, got %d (HasDisk %v)", c.UsedDelta, c.HasDisk)
+	}
+	if len(c.Events) != 2 || c.Cleaned != 150 {
+		t.Errorf("Expected 2 cleanups reclaiming 150, got %d reclaiming %d", len(c.Events), c.Cleaned)
+	}
+
+	if c := CompareSnapshots(snapshots, 0, 3); c.HasDisk {
+		t.Error("Expected no disk delta against a snapshot without disk figures")
+	}
+	if c := CompareSnapshots(snapshots, 2, 2); len(c.Events) != 0 || c.UsedDelta != 0 {
+		t.Error("Expected an empty comparison of a snapshot with itself")
+	}
+}
+
 func TestHistoryManager_LoadSnapshots_NotExist(t *testing.T) {
 	tmpDir := t.TempDir()
 	hm := &HistoryManager{dataDir: tmpDir}
@@ -293,3 +428,65 @@ func TestHistoryManager_LoadSnapshots_NotExist(t *testing.T) {
 		t.Errorf("Expected empty snapshots, got %d", len(snapshots))
 	}
 }
+
+func TestHistoryManager_GetCategoryTotals(t *testing.T) {
+	hm := &HistoryManager{dataDir: t.TempDir()}
+
+	hm.RecordSnapshot(1000, 500, 100, "system_junk", "")
+	hm.RecordSnapshot(1000, 400, 50, "system_junk", "")
+	hm.RecordSnapshot(1000, 350, 30, "duplicates", "")
+	hm.RecordSnapshot(1000, 350, 0, "scan", "") // Scans reclaim nothing
+
+	totals, err := hm.GetCategoryTotals(7)
+	if err != nil {
+		t.Fatalf("GetCategoryTotals failed: %v", err)
+	}
+	if len(totals) != 2 {
+		t.Fatalf("Expected 2 categories, got %v", totals)
+	}
+	if totals["system_junk"] != 150 {
+		t.Errorf("Expected system_junk 150, got %d", totals["system_junk"])
+	}
+	if totals["duplicates"] != 30 {
+		t.Errorf("Expected duplicates 30, got %d", totals["duplicates"])
+	}
+
+	// Category snapshots outside the window are not counted
+	snapshots, _ := hm.LoadCategorySnapshots()
+	snapshots[0].Timestamp = time.Now().AddDate(0, 0, -10)
+	data, _ := json.Marshal(snapshots)
+	os.WriteFile(filepath.Join(hm.dataDir, categoryFileName), data, 0644)
+
+	totals, _ = hm.GetCategoryTotals(7)
+	if totals["system_junk"] != 50 {
+		t.Errorf("Expected system_junk 50 within 7 days, got %d", totals["system_junk"])
+	}
+}
+
+func TestWriteSnapshotsCSV(t *testing.T) {
+	ts := time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)
+	snapshots := []DiskSnapshot{
+		{Timestamp: ts, TotalBytes: 1000, UsedBytes: 600, FreeBytes: 400, Trigger: "scan"},
+		{Timestamp: ts, TotalBytes: 1000, UsedBytes: 500, FreeBytes: 500, CleanedSize: 100, Trigger: "system_junk", Details: "npm Cache, Xcode Cache"},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteSnapshotsCSV(&buf, snapshots); err != nil {
+		t.Fatalf("WriteSnapshotsCSV() error: %v", err)
+	}
+
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("Output is not valid CSV: %v", err)
+	}
+	if len(rows) != 3 {
+		t.Fatalf("Expected header + 2 rows, got %d", len(rows))
+	}
+	if rows[0][0] != "timestamp" || len(rows[0]) != 7 {
+		t.Errorf("Unexpected header: %v", rows[0])
+	}
+	clean := rows[2]
+	if clean[0] != ts.Format(time.RFC3339) || clean[2] != "500" || clean[4] != "100" || clean[6] != "npm Cache, Xcode Cache" {
+		t.Errorf("Unexpected cleanup row: %v", clean)
+	}
+}
@@ -1,9 +1,11 @@
 package scanner
 
 import (
+	"context"
 	"os"
 	"path/filepath"
 	"sort"
+	"strings"
 	"time"
 )
 
@@ -35,6 +37,12 @@ func (s *LargeFileScanner) SetMaxAge(days int) {
 
 // Scan scans for large files
 func (s *LargeFileScanner) Scan(progressCh chan<- string) ([]FileInfo, error) {
+	return s.ScanContext(context.Background(), progressCh)
+}
+
+// ScanContext is Scan with cancellation: the walk stops at the next file once
+// ctx is done and ctx.Err() is returned
+func (s *LargeFileScanner) ScanContext(ctx context.Context, progressCh chan<- string) ([]FileInfo, error) {
 	var results []FileInfo
 
 	if progressCh != nil {
@@ -42,6 +50,9 @@ func (s *LargeFileScanner) Scan(progressCh chan<- string) ([]FileInfo, error) {
 	}
 
 	err := filepath.Walk(s.rootPath, func(path string, info os.FileInfo, err error) error {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
 		if err != nil {
 			return nil // Skip inaccessible files
 		}
@@ -83,3 +94,41 @@ func SortBySize(files []FileInfo) []FileInfo {
 	})
 	return files
 }
+
+// ExtensionGroup totals the files sharing one extension
+type ExtensionGroup struct {
+	Ext   string // lower case with the dot, e.g. ".mov"; "" for none
+	Count int
+	Size  int64
+}
+
+// FileExtension returns the lower-case extension of name, e.g. ".mov", or
+// "" when it has none. Dot files such as ".zshrc" have no extension.
+func FileExtension(name string) string {
+	i := strings.LastIndex(name, ".")
+	if i <= 0 || i == len(name)-1 {
+		return ""
+	}
+	return strings.ToLower(name[i:])
+}
+
+// GroupByExtension totals files by extension, largest total first
+func GroupByExtension(files []FileInfo) []ExtensionGroup {
+	index := make(map[string]int)
+	var groups []ExtensionGroup
+	for _, f := range files {
+		ext := FileExtension(f.Name)
+		i, ok := index[ext]
+		if !ok {
+			i = len(groups)
+			index[ext] = i
+			groups = append(groups, ExtensionGroup{Ext: ext})
+		}
+		groups[i].Count++
+		groups[i].Size += f.Size
+	}
+	sort.SliceStable(groups, func(i, j int) bool {
+		return groups[i].Size > groups[j].Size
+	})
+	return groups
+}
@@ -0,0 +1,43 @@
+package scanner
+
+import "testing"
+
+func TestFileExtension(t *testing.T) {
+	tests := map[string]string{
+		"Movie.MOV":     ".mov",
+		"backup.tar.gz": ".gz",
+		".zshrc":        "",
+		"Makefile":      "",
+		"trailing.":     "",
+		"Xcode_15.xip":  ".xip",
+	}
+	for name, want := range tests {
+		if got := FileExtension(name); got != want {
+			t.Errorf("FileExtension(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestGroupByExtension(t *testing.T) {
+	files := []FileInfo{
+		{Name: "a.mov", Size: 10},
+		{Name: "b.dmg", Size: 30},
+		{Name: "c.MOV", Size: 25},
+		{Name: "README", Size: 1},
+	}
+
+	groups := GroupByExtension(files)
+	want := []ExtensionGroup{
+		{Ext: ".mov", Count: 2, Size: 35},
+		{Ext: ".dmg", Count: 1, Size: 30},
+		{Ext: "", Count: 1, Size: 1},
+	}
+	if len(groups) != len(want) {
+		t.Fatalf("Expected %d groups, got %v", len(want), groups)
+	}
+	for i := range want {
+		if groups[i] != want[i] {
+			t.Errorf("groups[%d] = %+v, want %+v", i, groups[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package scanner
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// lockedTargetsFileName lists the paths of System Junk targets the user has
+// locked against cleaning, as a JSON array
+const lockedTargetsFileName = "locked.json"
+
+// LoadLockedTargets returns the paths of the locked targets. A missing or
+// malformed file yields none.
+func LoadLockedTargets() map[string]bool {
+	locked := make(map[string]bool)
+	dir := LumeDataDir()
+	if dir == "" {
+		return locked
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, lockedTargetsFileName))
+	if err != nil {
+		return locked
+	}
+	var paths []string
+	if err := json.Unmarshal(data, &paths); err != nil {
+		return locked
+	}
+	for _, p := range paths {
+		locked[p] = true
+	}
+	return locked
+}
+
+// SetTargetLocked locks or unlocks the target at path and saves the list,
+// keeping locks on targets the last scan did not find
+func SetTargetLocked(path string, lock bool) error {
+	dir := LumeDataDir()
+	if dir == "" {
+		return os.ErrNotExist
+	}
+
+	locked := LoadLockedTargets()
+	if lock {
+		locked[path] = true
+	} else {
+		delete(locked, path)
+	}
+
+	paths := make([]string, 0, len(locked))
+	for p := range locked {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	data, err := json.MarshalIndent(paths, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, lockedTargetsFileName), data, 0644)
+}
+
+// applyLocks marks the locked targets and takes them out of the selection,
+// whatever the auto-select policy picked
+func applyLocks(targets []ScanTarget, locked map[string]bool) {
+	for i := range targets {
+		if locked[targets[i].Path] {
+			targets[i].Locked = true
+			targets[i].Selected = false
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package scanner
+
+import "testing"
+
+func TestSetTargetLocked(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("SUDO_USER", "")
+
+	if locked := LoadLockedTargets(); len(locked) != 0 {
+		t.Fatalf("Expected no locked targets without a file, got %v", locked)
+	}
+
+	for _, p := range []string{"/tmp/b", "/tmp/a"} {
+		if err := SetTargetLocked(p, true); err != nil {
+			t.Fatalf("SetTargetLocked(%s) failed: %v", p, err)
+		}
+	}
+	if err := SetTargetLocked("/tmp/b", false); err != nil {
+		t.Fatalf("Unlocking failed: %v", err)
+	}
+
+	locked := LoadLockedTargets()
+	if len(locked) != 1 || !locked["/tmp/a"] {
+		t.Errorf("Expected only /tmp/a locked, got %v", locked)
+	}
+}
+
+func TestApplyLocks(t *testing.T) {
+	targets := []ScanTarget{
+		{Name: "Kept", Path: "/tmp/kept", Selected: true},
+		{Name: "Free", Path: "/tmp/free", Selected: true},
+	}
+	applyLocks(targets, map[string]bool{"/tmp/kept": true})
+
+	if !targets[0].Locked || targets[0].Selected {
+		t.Errorf("Locked target should be marked and unselected, got %+v", targets[0])
+	}
+	if targets[1].Locked || !targets[1].Selected {
+		t.Errorf("Other target should be untouched, got %+v", targets[1])
+	}
+}
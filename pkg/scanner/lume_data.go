@@ -0,0 +1,86 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// lumeCacheFiles are files under LumeDataDir that lume can regenerate on its
+// own. History and themes are user data and are never listed here.
+var lumeCacheFiles = []string{
+	sizeCacheFileName,
+}
+
+// LumeDataUsage describes the space used by lume's own files
+type LumeDataUsage struct {
+	Path      string
+	TotalSize int64
+	CacheSize int64 // Part of TotalSize that ClearLumeCaches can free
+}
+
+// LumeDataDir returns the directory where lume keeps its history, themes and
+// caches (~/.config/lume), or "" if the home directory is unknown
+func LumeDataDir() string {
+	homeDir := GetRealHomeDir()
+	if homeDir == "" {
+		return ""
+	}
+	return filepath.Join(homeDir, ".config", "lume")
+}
+
+// GetLumeDataUsage reports how much space lume itself is using
+func GetLumeDataUsage() LumeDataUsage {
+	usage := LumeDataUsage{Path: LumeDataDir()}
+	if usage.Path == "" {
+		return usage
+	}
+
+	filepath.Walk(usage.Path, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+		if !info.IsDir() {
+			usage.TotalSize += info.Size()
+		}
+		return nil
+	})
+
+	for _, name := range lumeCacheFiles {
+		if info, err := os.Stat(filepath.Join(usage.Path, name)); err == nil {
+			usage.CacheSize += info.Size()
+		}
+	}
+
+	return usage
+}
+
+// ClearLumeCaches deletes lume's own regenerable caches and returns the number
+// of bytes freed. These files are lume's, not the user's, so they are removed
+// directly instead of going to the Trash.
+func ClearLumeCaches() (int64, error) {
+	dir := LumeDataDir()
+	if dir == "" {
+		return 0, nil
+	}
+
+	var freed int64
+	var firstErr error
+	for _, name := range lumeCacheFiles {
+		path := filepath.Join(dir, name)
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		if err := os.Remove(path); err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		freed += info.Size()
+	}
+
+	dirSizeCache.Reset()
+
+	return freed, firstErr
+}
@@ -0,0 +1,45 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestClearLumeCaches_KeepsHistory(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("SUDO_USER", "")
+
+	dir := filepath.Join(home, ".config", "lume")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	cacheFile := filepath.Join(dir, sizeCacheFileName)
+	historyFile := filepath.Join(dir, historyFileName)
+	if err := os.WriteFile(cacheFile, make([]byte, 100), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(historyFile, make([]byte, 50), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	usage := GetLumeDataUsage()
+	if usage.TotalSize != 150 || usage.CacheSize != 100 {
+		t.Errorf("GetLumeDataUsage() = %d total, %d cache; want 150, 100", usage.TotalSize, usage.CacheSize)
+	}
+
+	freed, err := ClearLumeCaches()
+	if err != nil {
+		t.Fatalf("ClearLumeCaches() error: %v", err)
+	}
+	if freed != 100 {
+		t.Errorf("ClearLumeCaches() freed %d, want 100", freed)
+	}
+	if _, err := os.Stat(cacheFile); !os.IsNotExist(err) {
+		t.Error("Size cache should have been removed")
+	}
+	if _, err := os.Stat(historyFile); err != nil {
+		t.Error("History must not be removed")
+	}
+}
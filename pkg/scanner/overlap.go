@@ -0,0 +1,200 @@
+package scanner
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// canonicalPath cleans path and resolves symlinks so that /var/log and
+// /private/var/log compare equal. Paths that cannot be resolved (usually
+// because they do not exist) are only cleaned.
+func canonicalPath(path string) string {
+	path = filepath.Clean(path)
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		return resolved
+	}
+	return path
+}
+
+// pathWithin reports whether path lies strictly inside dir
+func pathWithin(path, dir string) bool {
+	return strings.HasPrefix(path, strings.TrimSuffix(dir, "/")+"/")
+}
+
+// pathOverlaps describes how a list of paths nest. dup[i] is set when path i
+// is the same location as an earlier one. children[i] holds, for path i, the
+// paths directly inside it: nested in it with no other listed path between,
+// so ~/Library/Caches lists Homebrew but not Homebrew/Cask. Empty paths take
+// no part.
+func pathOverlaps(paths []string) (canon []string, dup []bool, children [][]int) {
+	n := len(paths)
+	canon = make([]string, n)
+	dup = make([]bool, n)
+	children = make([][]int, n)
+
+	seen := make(map[string]bool)
+	for i, p := range paths {
+		if p == "" {
+			continue
+		}
+		canon[i] = canonicalPath(p)
+		dup[i] = seen[canon[i]]
+		seen[canon[i]] = true
+	}
+
+	live := func(i int) bool { return canon[i] != "" && !dup[i] }
+	for i := range canon {
+		if !live(i) {
+			continue
+		}
+		for j := range canon {
+			if j == i || !live(j) || !pathWithin(canon[j], canon[i]) {
+				continue
+			}
+			direct := true
+			for k := range canon {
+				if k != i && k != j && live(k) && pathWithin(canon[k], canon[i]) && pathWithin(canon[j], canon[k]) {
+					direct = false
+					break
+				}
+			}
+			if direct {
+				children[i] = append(children[i], j)
+			}
+		}
+	}
+	return canon, dup, children
+}
+
+// excludePath maps the canonical child path onto parent's own spelling, so
+// an exclusion stays comparable with the entries found under parent
+func excludePath(parent, parentCanon, childCanon string) string {
+	rel, err := filepath.Rel(parentCanon, childCanon)
+	if err != nil {
+		return childCanon
+	}
+	return filepath.Join(parent, rel)
+}
+
+// collapseTargets drops targets that repeat an earlier target's location and
+// sets Exclude on every target that contains other targets. Scan then counts
+// each nested directory once, under the most specific target, and cleaning a
+// parent leaves its nested targets alone.
+func collapseTargets(targets []ScanTarget) []ScanTarget {
+	paths := make([]string, len(targets))
+	for i, t := range targets {
+		paths[i] = t.Path
+	}
+	canon, dup, children := pathOverlaps(paths)
+
+	out := make([]ScanTarget, 0, len(targets))
+	for i, t := range targets {
+		if dup[i] {
+			continue
+		}
+		t.Exclude = nil
+		for _, j := range children[i] {
+			t.Exclude = append(t.Exclude, excludePath(t.Path, canon[i], canon[j]))
+		}
+		out = append(out, t)
+	}
+	return out
+}
+
+// foldSmallTargets drops the measured directory targets of floor bytes or
+// less, which Scan does not list, and hands each dropped nested target back
+// to its parent: the parent counts its bytes again and stops excluding it,
+// so cleaning the parent cleans it too. Sizes must already leave nested
+// targets out. Children are settled before their parents, so a parent that
+// grows past floor is kept. Locked targets are always kept, so that their
+// parent goes on excluding them. changed[i] is set when target i's Exclude
+// changed and its file counts are stale.
+func foldSmallTargets(targets []*ScanTarget, isDir []bool, floor int64) (changed []bool) {
+	changed = make([]bool, len(targets))
+	canon := make([]string, len(targets))
+	index := make(map[string]int, len(targets))
+	var order []int
+	for i, t := range targets {
+		if t == nil {
+			continue
+		}
+		canon[i] = canonicalPath(t.Path)
+		index[canon[i]] = i
+		order = append(order, i)
+	}
+
+	parent := make([]int, len(targets))
+	for i := range parent {
+		parent[i] = -1
+	}
+	for _, i := range order {
+		for _, ex := range targets[i].Exclude {
+			if j, ok := index[canonicalPath(ex)]; ok {
+				parent[j] = i
+			}
+		}
+	}
+
+	// A nested path always has more separators than the one holding it
+	sort.SliceStable(order, func(a, b int) bool {
+		return strings.Count(canon[order[a]], "/") > strings.Count(canon[order[b]], "/")
+	})
+
+	for _, i := range order {
+		t := targets[i]
+		if !isDir[i] || t.Size > floor || t.SizeUnknown || t.Locked {
+			continue
+		}
+		if p := parent[i]; p >= 0 {
+			pt := targets[p]
+			if !pt.SizeUnknown {
+				pt.Size += t.Size
+			}
+			exclude := make([]string, 0, len(pt.Exclude)+len(t.Exclude))
+			for _, ex := range pt.Exclude {
+				if canonicalPath(ex) != canon[i] {
+					exclude = append(exclude, ex)
+				}
+			}
+			for _, ex := range t.Exclude {
+				exclude = append(exclude, excludePath(pt.Path, canon[p], canonicalPath(ex)))
+			}
+			pt.Exclude = exclude
+			changed[p] = true
+		}
+		targets[i] = nil
+	}
+	return changed
+}
+
+// collapseSystemData is collapseTargets for System Data items, which arrive
+// already measured: nested items' sizes are taken out of their parent's.
+func collapseSystemData(items []SystemDataItem) []SystemDataItem {
+	paths := make([]string, len(items))
+	for i, item := range items {
+		paths[i] = item.Path
+	}
+	canon, dup, children := pathOverlaps(paths)
+
+	out := make([]SystemDataItem, 0, len(items))
+	for i, item := range items {
+		if dup[i] {
+			continue
+		}
+		item.Exclude = nil
+		for _, j := range children[i] {
+			item.Exclude = append(item.Exclude, excludePath(item.Path, canon[i], canon[j]))
+			item.Size -= items[j].Size
+		}
+		if item.Size < 0 {
+			item.Size = 0
+		}
+		if len(item.Exclude) > 0 && item.Size == 0 && !item.SizeUnknown {
+			// Nothing left beyond the nested items
+			continue
+		}
+		out = append(out, item)
+	}
+	return out
+}
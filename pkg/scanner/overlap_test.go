@@ -0,0 +1,115 @@
+package scanner
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestCollapseTargets(t *testing.T) {
+	home := t.TempDir()
+	caches := filepath.Join(home, "Library", "Caches")
+	targets := []ScanTarget{
+		{Name: "App Caches", Path: caches},
+		{Name: "Homebrew Cache", Path: filepath.Join(caches, "Homebrew")},
+		{Name: "Homebrew Cask", Path: filepath.Join(caches, "Homebrew", "Cask")},
+		{Name: "Caches again", Path: caches + "/"},
+		{Name: "npm", Path: filepath.Join(home, ".npm")},
+		{Name: "Caches-like sibling", Path: caches + "-old"},
+	}
+
+	got := collapseTargets(targets)
+
+	if len(got) != 5 {
+		t.Fatalf("Expected the repeated path to be dropped, got %d targets", len(got))
+	}
+	if want := []string{filepath.Join(caches, "Homebrew")}; !reflect.DeepEqual(got[0].Exclude, want) {
+		t.Errorf("App Caches should exclude only its direct nested target, got %v", got[0].Exclude)
+	}
+	if want := []string{filepath.Join(caches, "Homebrew", "Cask")}; !reflect.DeepEqual(got[1].Exclude, want) {
+		t.Errorf("Homebrew should exclude Cask, got %v", got[1].Exclude)
+	}
+	for _, tgt := range got[2:] {
+		if len(tgt.Exclude) != 0 {
+			t.Errorf("%s should not exclude anything, got %v", tgt.Name, tgt.Exclude)
+		}
+	}
+}
+
+func TestCollapseSystemData(t *testing.T) {
+	items := []SystemDataItem{
+		{Name: "Logs", Path: "/data/log", Size: 100},
+		{Name: "ASL", Path: "/data/log/asl", Size: 30},
+		{Name: "Install", Path: "/data/log/install", Size: 20},
+		{Name: "Logs again", Path: "/data/log", Size: 100},
+		{Name: "Only nested", Path: "/data/db", Size: 10},
+		{Name: "Receipts", Path: "/data/db/receipts", Size: 10},
+		{Name: "Snapshots", Size: 5},
+	}
+
+	got := collapseSystemData(items)
+
+	names := make([]string, len(got))
+	for i, item := range got {
+		names[i] = item.Name
+	}
+	if want := []string{"Logs", "ASL", "Install", "Receipts", "Snapshots"}; !reflect.DeepEqual(names, want) {
+		t.Fatalf("Unexpected items %v, want %v", names, want)
+	}
+	if got[0].Size != 50 {
+		t.Errorf("Logs should only count what is not nested, got %d", got[0].Size)
+	}
+	if len(got[0].Exclude) != 2 {
+		t.Errorf("Logs should exclude 2 nested items, got %v", got[0].Exclude)
+	}
+}
+
+func TestFoldSmallTargets(t *testing.T) {
+	const mb = 1024 * 1024
+	caches := &ScanTarget{Name: "Caches", Path: "/data/c", Size: 50 * mb, Exclude: []string{"/data/c/b", "/data/c/s"}}
+	small := &ScanTarget{Name: "Small", Path: "/data/c/b", Size: 5 * mb, Exclude: []string{"/data/c/b/x"}}
+	big := &ScanTarget{Name: "Big", Path: "/data/c/b/x", Size: 20 * mb}
+	tiny := &ScanTarget{Name: "Tiny", Path: "/data/c/s", Size: 3 * mb}
+	file := &ScanTarget{Name: "Log", Path: "/data/log.txt", Size: 1}
+	targets := []*ScanTarget{caches, small, big, tiny, file}
+
+	changed := foldSmallTargets(targets, []bool{true, true, true, true, false}, 10*mb)
+
+	if targets[1] != nil || targets[3] != nil {
+		t.Error("Directory targets of 10 MB or less should be dropped")
+	}
+	if targets[0] == nil || targets[2] == nil || targets[4] == nil {
+		t.Fatal("Large targets and files should be kept")
+	}
+	if caches.Size != 58*mb {
+		t.Errorf("Caches should take back the dropped targets' bytes, got %d", caches.Size)
+	}
+	if want := []string{"/data/c/b/x"}; !reflect.DeepEqual(caches.Exclude, want) {
+		t.Errorf("Caches should only exclude what is still listed, got %v", caches.Exclude)
+	}
+	if want := []bool{true, false, false, false, false}; !reflect.DeepEqual(changed, want) {
+		t.Errorf("Unexpected changed %v", changed)
+	}
+}
+
+func TestFoldSmallTargets_KeepsLocked(t *testing.T) {
+	const mb = 1024 * 1024
+	caches := &ScanTarget{Name: "Caches", Path: "/data/c", Size: 50 * mb, Exclude: []string{"/data/c/l"}}
+	locked := &ScanTarget{Name: "Locked", Path: "/data/c/l", Size: 2 * mb, Locked: true}
+	targets := []*ScanTarget{caches, locked}
+
+	changed := foldSmallTargets(targets, []bool{true, true}, 10*mb)
+
+	if targets[1] == nil {
+		t.Fatal("A small locked target should be kept")
+	}
+	if caches.Size != 50*mb {
+		t.Errorf("Caches should not take a locked target's bytes, got %d", caches.Size)
+	}
+	if want := []string{"/data/c/l"}; !reflect.DeepEqual(caches.Exclude, want) {
+		t.Errorf("Caches should keep excluding the locked target, got %v", caches.Exclude)
+	}
+	if want := []bool{false, false}; !reflect.DeepEqual(changed, want) {
+		t.Errorf("Unexpected changed %v", changed)
+	}
+}
@@ -0,0 +1,77 @@
+package scanner
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Platform abstracts what differs between operating systems: where caches
+// and the Trash live, and what the file system reports beyond os.FileInfo.
+// The scanners are written for macOS; through Platform the parts that are
+// not tied to ~/Library (large files, duplicates, zombie files, the disk
+// analyzer) also run on Linux.
+type Platform interface {
+	// Name is the platform's display name
+	Name() string
+
+	// CacheDir is the per-user cache directory under home
+	CacheDir(home string) string
+
+	// TrashDir is the directory cleaned files are moved to
+	TrashDir(home string) string
+
+	// EmptyTrash permanently deletes everything in the Trash at trashDir,
+	// as the desktop's own Empty Trash does
+	EmptyTrash(trashDir string) error
+
+	// LookupHomeDir finds a user's home directory when os/user cannot,
+	// returning "" if it has no other way to
+	LookupHomeDir(username string) string
+
+	// FileTimes returns the access and modification times of info. The
+	// access time is zero when the platform does not report one.
+	FileTimes(info os.FileInfo) (atime, mtime time.Time)
+
+	// FileID returns the device and inode of the file behind info and its
+	// hard link count, or ok false when the platform does not report them
+	FileID(info os.FileInfo) (dev, ino uint64, links int, ok bool)
+}
+
+// CurrentPlatform is the platform lume is running on
+var CurrentPlatform Platform = currentPlatform()
+
+// clearTrashDir deletes everything in trashDir. A freedesktop.org Trash
+// (.../Trash/files) also loses the .trashinfo records in its sibling info
+// folder, so file managers do not list items that are gone.
+func clearTrashDir(trashDir string) error {
+	dirs := []string{trashDir}
+	if filepath.Base(trashDir) == "files" {
+		dirs = append(dirs, filepath.Join(filepath.Dir(trashDir), "info"))
+	}
+
+	failed := 0
+	var firstErr error
+	for _, dir := range dirs {
+		entries, err := os.ReadDir(dir)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			if err := os.RemoveAll(filepath.Join(dir, entry.Name())); err != nil {
+				failed++
+				if firstErr == nil {
+					firstErr = err
+				}
+			}
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d items could not be deleted: %w", failed, firstErr)
+	}
+	return nil
+}
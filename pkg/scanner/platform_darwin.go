@@ -0,0 +1,70 @@
+package scanner
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+)
+
+type darwinPlatform struct{}
+
+func currentPlatform() Platform { return darwinPlatform{} }
+
+func (darwinPlatform) Name() string { return "macOS" }
+
+func (darwinPlatform) CacheDir(home string) string {
+	return filepath.Join(home, "Library", "Caches")
+}
+
+func (darwinPlatform) TrashDir(home string) string {
+	return filepath.Join(home, ".Trash")
+}
+
+// EmptyTrash goes through Finder, the same as Finder > Empty Trash: the
+// Trash of every volume is emptied, not only trashDir, and Finder keeps
+// its own bookkeeping straight
+func (darwinPlatform) EmptyTrash(trashDir string) error {
+	out, err := exec.Command("osascript", "-e", `tell application "Finder" to empty trash`).CombinedOutput()
+	if err != nil {
+		msg := strings.TrimSpace(string(out))
+		if msg == "" {
+			msg = err.Error()
+		}
+		return fmt.Errorf("%s", msg)
+	}
+	return nil
+}
+
+// LookupHomeDir asks Directory Services, which knows network and managed
+// accounts that os/user may not
+func (darwinPlatform) LookupHomeDir(username string) string {
+	out, err := exec.Command("dscl", ".", "-read", "/Users/"+username, "NFSHomeDirectory").Output()
+	if err != nil {
+		return ""
+	}
+	for _, line := range strings.Split(string(out), "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "NFSHomeDirectory:") {
+			return strings.TrimSpace(strings.TrimPrefix(line, "NFSHomeDirectory:"))
+		}
+	}
+	return ""
+}
+
+func (darwinPlatform) FileTimes(info os.FileInfo) (atime, mtime time.Time) {
+	if sys, ok := info.Sys().(*syscall.Stat_t); ok {
+		return time.Unix(sys.Atimespec.Unix()), time.Unix(sys.Mtimespec.Unix())
+	}
+	return time.Time{}, info.ModTime()
+}
+
+func (darwinPlatform) FileID(info os.FileInfo) (dev, ino uint64, links int, ok bool) {
+	if sys, ok := info.Sys().(*syscall.Stat_t); ok {
+		return uint64(sys.Dev), sys.Ino, int(sys.Nlink), true
+	}
+	return 0, 0, 0, false
+}
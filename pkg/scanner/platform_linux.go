@@ -0,0 +1,52 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+	"time"
+)
+
+type linuxPlatform struct{}
+
+func currentPlatform() Platform { return linuxPlatform{} }
+
+func (linuxPlatform) Name() string { return "Linux" }
+
+// CacheDir follows the XDG base directory spec
+func (linuxPlatform) CacheDir(home string) string {
+	if dir := os.Getenv("XDG_CACHE_HOME"); filepath.IsAbs(dir) {
+		return dir
+	}
+	return filepath.Join(home, ".cache")
+}
+
+// TrashDir is the freedesktop.org home trash
+func (linuxPlatform) TrashDir(home string) string {
+	if dir := os.Getenv("XDG_DATA_HOME"); filepath.IsAbs(dir) {
+		return filepath.Join(dir, "Trash", "files")
+	}
+	return filepath.Join(home, ".local", "share", "Trash", "files")
+}
+
+// EmptyTrash deletes the Trash's files and their .trashinfo records, which
+// is all a freedesktop.org file manager does
+func (linuxPlatform) EmptyTrash(trashDir string) error {
+	return clearTrashDir(trashDir)
+}
+
+func (linuxPlatform) LookupHomeDir(username string) string { return "" }
+
+func (linuxPlatform) FileTimes(info os.FileInfo) (atime, mtime time.Time) {
+	if sys, ok := info.Sys().(*syscall.Stat_t); ok {
+		return time.Unix(sys.Atim.Unix()), time.Unix(sys.Mtim.Unix())
+	}
+	return time.Time{}, info.ModTime()
+}
+
+func (linuxPlatform) FileID(info os.FileInfo) (dev, ino uint64, links int, ok bool) {
+	if sys, ok := info.Sys().(*syscall.Stat_t); ok {
+		return uint64(sys.Dev), uint64(sys.Ino), int(sys.Nlink), true
+	}
+	return 0, 0, 0, false
+}
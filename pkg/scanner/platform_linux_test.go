@@ -0,0 +1,24 @@
+package scanner
+
+import "testing"
+
+func TestLinuxPlatform_Dirs(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", "")
+	t.Setenv("XDG_DATA_HOME", "")
+	p := linuxPlatform{}
+	if got := p.CacheDir("/home/u"); got != "/home/u/.cache" {
+		t.Errorf("CacheDir = %q", got)
+	}
+	if got := p.TrashDir("/home/u"); got != "/home/u/.local/share/Trash/files" {
+		t.Errorf("TrashDir = %q", got)
+	}
+
+	t.Setenv("XDG_CACHE_HOME", "/xdg/cache")
+	t.Setenv("XDG_DATA_HOME", "/xdg/data")
+	if got := p.CacheDir("/home/u"); got != "/xdg/cache" {
+		t.Errorf("CacheDir with XDG_CACHE_HOME = %q", got)
+	}
+	if got := p.TrashDir("/home/u"); got != "/xdg/data/Trash/files" {
+		t.Errorf("TrashDir with XDG_DATA_HOME = %q", got)
+	}
+}
@@ -0,0 +1,43 @@
+//go:build !darwin && !linux
+
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"time"
+)
+
+// genericPlatform covers systems lume has no specific support for, using
+// only what os.FileInfo reports
+type genericPlatform struct{}
+
+func currentPlatform() Platform { return genericPlatform{} }
+
+func (genericPlatform) Name() string { return runtime.GOOS }
+
+func (genericPlatform) CacheDir(home string) string {
+	if dir, err := os.UserCacheDir(); err == nil {
+		return dir
+	}
+	return filepath.Join(home, ".cache")
+}
+
+func (genericPlatform) TrashDir(home string) string {
+	return filepath.Join(home, ".Trash")
+}
+
+func (genericPlatform) EmptyTrash(trashDir string) error {
+	return clearTrashDir(trashDir)
+}
+
+func (genericPlatform) LookupHomeDir(username string) string { return "" }
+
+func (genericPlatform) FileTimes(info os.FileInfo) (atime, mtime time.Time) {
+	return time.Time{}, info.ModTime()
+}
+
+func (genericPlatform) FileID(info os.FileInfo) (dev, ino uint64, links int, ok bool) {
+	return 0, 0, 0, false
+}
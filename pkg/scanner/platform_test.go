@@ -0,0 +1,94 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCurrentPlatform_FileTimes(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(path, []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	atime := time.Now().AddDate(-2, 0, 0).Truncate(time.Second)
+	mtime := time.Now().AddDate(-1, 0, 0).Truncate(time.Second)
+	if err := os.Chtimes(path, atime, mtime); err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := os.Lstat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gotA, gotM := CurrentPlatform.FileTimes(info)
+	if !gotM.Equal(mtime) {
+		t.Errorf("mtime = %v, want %v", gotM, mtime)
+	}
+	if !gotA.IsZero() && !gotA.Equal(atime) {
+		t.Errorf("atime = %v, want %v", gotA, atime)
+	}
+}
+
+func TestCurrentPlatform_FileID(t *testing.T) {
+	dir := t.TempDir()
+	a := filepath.Join(dir, "a")
+	b := filepath.Join(dir, "b")
+	if err := os.WriteFile(a, []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Link(a, b); err != nil {
+		t.Skipf("hard links not supported: %v", err)
+	}
+
+	infoA, _ := os.Lstat(a)
+	infoB, _ := os.Lstat(b)
+	devA, inoA, links, ok := CurrentPlatform.FileID(infoA)
+	if !ok {
+		t.Skip("platform does not report file IDs")
+	}
+	devB, inoB, _, _ := CurrentPlatform.FileID(infoB)
+	if devA != devB || inoA != inoB {
+		t.Error("Expected hard links to share a device and inode")
+	}
+	if links != 2 {
+		t.Errorf("links = %d, want 2", links)
+	}
+}
+
+func TestCurrentPlatform_Dirs(t *testing.T) {
+	home := "/home/test"
+	if !filepath.IsAbs(CurrentPlatform.CacheDir(home)) {
+		t.Errorf("CacheDir should be absolute, got %q", CurrentPlatform.CacheDir(home))
+	}
+	if !filepath.IsAbs(CurrentPlatform.TrashDir(home)) {
+		t.Errorf("TrashDir should be absolute, got %q", CurrentPlatform.TrashDir(home))
+	}
+}
+
+func TestClearTrashDir(t *testing.T) {
+	trash := filepath.Join(t.TempDir(), "Trash")
+	files, info := filepath.Join(trash, "files"), filepath.Join(trash, "info")
+	for _, p := range []string{filepath.Join(files, "dir", "a.txt"), filepath.Join(files, "b.txt"), filepath.Join(info, "b.txt.trashinfo")} {
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte("x"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := clearTrashDir(files); err != nil {
+		t.Fatalf("clearTrashDir failed: %v", err)
+	}
+	for _, dir := range []string{files, info} {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			t.Fatalf("%s should be kept: %v", dir, err)
+		}
+		if len(entries) != 0 {
+			t.Errorf("%s should be empty, has %d entries", dir, len(entries))
+		}
+	}
+}
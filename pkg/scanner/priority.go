@@ -0,0 +1,68 @@
+package scanner
+
+import (
+	"context"
+	"os/exec"
+	"sync"
+)
+
+// lowPriorityNice is the niceness scan commands run at in low-priority mode
+const lowPriorityNice = "10"
+
+// lowPriorityWorkers caps the goroutines a scan runs at once in
+// low-priority mode, so hashing and stat-ing leave the other cores free
+const lowPriorityWorkers = 2
+
+var (
+	priorityOnce sync.Once
+	lowPriority  bool
+)
+
+// SetLowPriority overrides the config's lowPriority setting
+func SetLowPriority(low bool) {
+	priorityOnce.Do(func() {})
+	lowPriority = low
+}
+
+// lowPriorityEnabled reports whether scans should yield to other work, read
+// from the config the first time it is asked
+func lowPriorityEnabled() bool {
+	priorityOnce.Do(func() { lowPriority = LoadConfig().LowPriority })
+	return lowPriority
+}
+
+// scanWorkers returns how many workers a scan that would use n may start
+func scanWorkers(n int) int {
+	if lowPriorityEnabled() && n > lowPriorityWorkers {
+		return lowPriorityWorkers
+	}
+	return n
+}
+
+// ScanCommand builds the exec.Cmd for a scan tool such as du or find. In
+// low-priority mode it runs under nice, and on Linux also under ionice's
+// idle class, so a long scan does not slow down everything else. Every du
+// and find lume runs should go through it.
+func ScanCommand(ctx context.Context, name string, args ...string) *exec.Cmd {
+	if !lowPriorityEnabled() {
+		return exec.CommandContext(ctx, name, args...)
+	}
+	argv := append([]string{"nice", "-n", lowPriorityNice, name}, args...)
+	if CurrentPlatform.Name() == "Linux" && hasIonice() {
+		argv = append([]string{"ionice", "-c", "3"}, argv...)
+	}
+	return exec.CommandContext(ctx, argv[0], argv[1:]...)
+}
+
+var (
+	ioniceOnce  sync.Once
+	ioniceFound bool
+)
+
+func hasIonice() bool {
+	ioniceOnce.Do(func() {
+		_, err := exec.LookPath("ionice")
+		ioniceFound = err == nil
+	})
+	return ioniceFound
+}
@@ -0,0 +1,47 @@
+package scanner
+
+import (
+	"context"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestScanWorkers(t *testing.T) {
+	SetLowPriority(false)
+	if got := scanWorkers(8); got != 8 {
+		t.Errorf("scanWorkers(8) = %d, want 8", got)
+	}
+
+	SetLowPriority(true)
+	defer SetLowPriority(false)
+	if got := scanWorkers(8); got != lowPriorityWorkers {
+		t.Errorf("low priority scanWorkers(8) = %d, want %d", got, lowPriorityWorkers)
+	}
+	if got := scanWorkers(1); got != 1 {
+		t.Errorf("low priority scanWorkers(1) = %d, want 1", got)
+	}
+}
+
+func TestScanCommand_LowPriority(t *testing.T) {
+	if _, err := exec.LookPath("nice"); err != nil {
+		t.Skip("nice not available")
+	}
+	dir := t.TempDir()
+
+	SetLowPriority(false)
+	cmd := ScanCommand(context.Background(), "du", "-sk", "--", dir)
+	if filepath.Base(cmd.Path) != "du" {
+		t.Errorf("normal priority runs %s, want du", cmd.Path)
+	}
+
+	SetLowPriority(true)
+	defer SetLowPriority(false)
+	cmd = ScanCommand(context.Background(), "du", "-sk", "--", dir)
+	if name := filepath.Base(cmd.Path); name != "nice" && name != "ionice" {
+		t.Errorf("low priority runs %s, want nice or ionice", cmd.Path)
+	}
+	if _, err := cmd.Output(); err != nil {
+		t.Errorf("low priority du failed: %v", err)
+	}
+}
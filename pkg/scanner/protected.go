@@ -0,0 +1,109 @@
+package scanner
+
+import (
+	"io/fs"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// syncedDataDirs hold user data that iCloud or an Apple app keeps in sync.
+// Trashing something inside them can remove it on every device, so the UI
+// warns about it whatever risk level the item carries.
+var syncedDataDirs = []struct {
+	label string
+	rel   string // relative to the home directory
+}{
+	{"iCloud Drive", "Library/Mobile Documents"},
+	{"Mail", "Library/Mail"},
+	{"Photos Library", "Pictures/Photos Library.photoslibrary"},
+}
+
+// SyncedDataLabel names the synced location path lies in (or is), e.g.
+// "iCloud Drive", or returns "" when it lies in none. Photos libraries are
+// recognised anywhere by their .photoslibrary bundle.
+func SyncedDataLabel(path string) string {
+	return syncedDataLabel(path, GetRealHomeDir())
+}
+
+func syncedDataLabel(path, homeDir string) string {
+	path = filepath.Clean(path)
+
+	if homeDir != "" {
+		for _, d := range syncedDataDirs {
+			dir := filepath.Join(homeDir, d.rel)
+			if path == dir || strings.HasPrefix(path, dir+"/") {
+				return d.label
+			}
+		}
+	}
+
+	for _, part := range strings.Split(path, "/") {
+		if strings.HasSuffix(part, ".photoslibrary") {
+			return "Photos Library"
+		}
+	}
+	return ""
+}
+
+// SyncedDataLabels returns the distinct synced locations among paths, in
+// the order they are first seen
+func SyncedDataLabels(paths []string) []string {
+	homeDir := GetRealHomeDir()
+	seen := make(map[string]bool)
+	var labels []string
+	for _, p := range paths {
+		if label := syncedDataLabel(p, homeDir); label != "" && !seen[label] {
+			seen[label] = true
+			labels = append(labels, label)
+		}
+	}
+	return labels
+}
+
+// RecentActivityWindow is how recently a file must have changed for
+// RecentlyModified to report it: an app writing there now is probably
+// still running, and trashing its cache under it can lose data.
+const RecentActivityWindow = 5 * time.Minute
+
+// recentCheckLimit caps the entries RecentlyModified looks at per path, so
+// the check stays quick on caches with hundreds of thousands of files
+const recentCheckLimit = 20000
+
+// RecentlyModified returns those of paths holding an entry modified within
+// window of now. Each path is walked only until the first such entry, and
+// at most recentCheckLimit entries are examined; a path that cannot be read
+// is left out.
+func RecentlyModified(paths []string, window time.Duration) []string {
+	cutoff := time.Now().Add(-window)
+	var recent []string
+	for _, p := range paths {
+		if modifiedSince(p, cutoff) {
+			recent = append(recent, p)
+		}
+	}
+	return recent
+}
+
+func modifiedSince(root string, cutoff time.Time) bool {
+	found := false
+	checked := 0
+	filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if d != nil && d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		checked++
+		if checked > recentCheckLimit {
+			return filepath.SkipAll
+		}
+		if info, err := d.Info(); err == nil && info.ModTime().After(cutoff) {
+			found = true
+			return filepath.SkipAll
+		}
+		return nil
+	})
+	return found
+}
@@ -0,0 +1,68 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSyncedDataLabel(t *testing.T) {
+	home := "/Users/alice"
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"/Users/alice/Library/Mobile Documents", "iCloud Drive"},
+		{"/Users/alice/Library/Mobile Documents/com~apple~CloudDocs/report.pdf", "iCloud Drive"},
+		{"/Users/alice/Library/Mobile Documents Backup", ""},
+		{"/Users/alice/Library/Mail/V10", "Mail"},
+		{"/Users/alice/Pictures/Photos Library.photoslibrary/originals", "Photos Library"},
+		{"/Volumes/Archive/Family.photoslibrary", "Photos Library"},
+		{"/Users/alice/Library/Caches/com.apple.Safari", ""},
+		{"/Users/bob/Library/Mail", ""},
+	}
+
+	for _, tt := range tests {
+		if got := syncedDataLabel(tt.path, home); got != tt.want {
+			t.Errorf("syncedDataLabel(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestRecentlyModified(t *testing.T) {
+	dir := t.TempDir()
+	old := time.Now().Add(-time.Hour)
+
+	idle := filepath.Join(dir, "idle")
+	busy := filepath.Join(dir, "busy")
+	for _, d := range []string{idle, filepath.Join(busy, "sub")} {
+		if err := os.MkdirAll(d, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	files := map[string]time.Time{
+		filepath.Join(idle, "a.cache"):       old,
+		filepath.Join(busy, "b.cache"):       old,
+		filepath.Join(busy, "sub", "c.lock"): time.Now(),
+	}
+	for f, mtime := range files {
+		if err := os.WriteFile(f, []byte("x"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(f, mtime, mtime); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Directories were just created; age them so only the files count
+	for _, d := range []string{idle, busy, filepath.Join(busy, "sub")} {
+		if err := os.Chtimes(d, old, old); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got := RecentlyModified([]string{idle, busy, filepath.Join(dir, "missing")}, RecentActivityWindow)
+	if len(got) != 1 || got[0] != busy {
+		t.Errorf("RecentlyModified() = %v, want [%s]", got, busy)
+	}
+}
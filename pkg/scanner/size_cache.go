@@ -0,0 +1,266 @@
+package scanner
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	sizeCacheFileName = "sizecache.json"
+	sizeCacheTTL      = 30 * time.Minute
+)
+
+// sizeCacheEntry is a cached du result for one directory
+type sizeCacheEntry struct {
+	Size     int64     `json:"size"`
+	Files    int       `json:"files,omitempty"` // regular files below, 0 if not counted
+	ModTime  time.Time `json:"mod_time"`
+	CachedAt time.Time `json:"cached_at"`
+}
+
+// sizeCache caches directory sizes so refreshes don't re-run du on trees that
+// haven't changed. An entry is reused only while the directory's mtime is the
+// same and the entry is younger than the TTL; the TTL bounds staleness from
+// changes deep in the tree, which don't touch the top-level mtime. Entries
+// are kept per du mode (its flags, e.g. -sk or -skH), since one directory
+// can measure differently depending on how symlinks are followed.
+type sizeCache struct {
+	mu      sync.Mutex
+	entries map[string]sizeCacheEntry
+	ttl     time.Duration
+	file    string // empty means memory only
+	loaded  bool
+	dirty   bool
+}
+
+// dirSizeCache is shared by all du-based size lookups in this package
+var dirSizeCache = newSizeCache(sizeCachePath(), sizeCacheTTL)
+
+func newSizeCache(file string, ttl time.Duration) *sizeCache {
+	return &sizeCache{
+		entries: make(map[string]sizeCacheEntry),
+		ttl:     ttl,
+		file:    file,
+	}
+}
+
+// sizeCachePath returns ~/.config/lume/sizecache.json, or "" if there is no home
+func sizeCachePath() string {
+	dir := LumeDataDir()
+	if dir == "" {
+		return ""
+	}
+	return filepath.Join(dir, sizeCacheFileName)
+}
+
+// sizeCacheKey is the entries key of path measured in mode
+func sizeCacheKey(mode, path string) string {
+	return mode + " " + path
+}
+
+// sizeCachePathOf returns the path part of an entries key
+func sizeCachePathOf(key string) string {
+	_, path, _ := strings.Cut(key, " ")
+	return path
+}
+
+// Get returns the size cached for path in mode if it is still valid
+func (c *sizeCache) Get(mode, path string) (int64, bool) {
+	entry, ok := c.lookup(mode, path)
+	return entry.Size, ok
+}
+
+// GetCount returns the cached file count for path in mode if it is still
+// valid and was recorded with PutCount
+func (c *sizeCache) GetCount(mode, path string) (int, bool) {
+	entry, ok := c.lookup(mode, path)
+	return entry.Files, ok && entry.Files > 0
+}
+
+// lookup returns the entry for path in mode if it is still valid
+func (c *sizeCache) lookup(mode, path string) (sizeCacheEntry, bool) {
+	info, statErr := os.Stat(path)
+	key := sizeCacheKey(mode, path)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.load()
+
+	entry, ok := c.entries[key]
+	if !ok {
+		return sizeCacheEntry{}, false
+	}
+
+	if statErr != nil {
+		// Directory is gone (or unreadable) - the cached size means nothing now
+		delete(c.entries, key)
+		c.dirty = true
+		return sizeCacheEntry{}, false
+	}
+
+	if !info.ModTime().Equal(entry.ModTime) || time.Since(entry.CachedAt) > c.ttl {
+		return sizeCacheEntry{}, false
+	}
+
+	return entry, true
+}
+
+// Put stores a size freshly measured for path in mode
+func (c *sizeCache) Put(mode, path string, size int64) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.load()
+
+	c.entries[sizeCacheKey(mode, path)] = sizeCacheEntry{
+		Size:     size,
+		ModTime:  info.ModTime(),
+		CachedAt: time.Now(),
+	}
+	c.dirty = true
+}
+
+// PutCount adds a file count to the size cached for path in mode. It is
+// dropped if the size entry is missing or belongs to an older state of the
+// directory.
+func (c *sizeCache) PutCount(mode, path string, files int) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return
+	}
+	key := sizeCacheKey(mode, path)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok || !info.ModTime().Equal(entry.ModTime) {
+		return
+	}
+	entry.Files = files
+	c.entries[key] = entry
+	c.dirty = true
+}
+
+// Forget drops the entries for path in every mode, so the next lookup runs
+// du again
+func (c *sizeCache) Forget(path string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.load()
+
+	for key := range c.entries {
+		if sizeCachePathOf(key) == path {
+			delete(c.entries, key)
+			c.dirty = true
+		}
+	}
+}
+
+// ForgetSizes drops the cached sizes of paths, and of every cached directory
+// holding one of them since its size counted them too, then saves the cache.
+// Call it after files were moved out of paths: cleaning below the top level
+// leaves a directory's mtime alone, so the entries would otherwise still be
+// used until the TTL runs out.
+func ForgetSizes(paths []string) {
+	for _, p := range paths {
+		dirSizeCache.forgetHolding(filepath.Clean(p))
+	}
+	_ = dirSizeCache.Save()
+}
+
+// forgetHolding drops the entries for path and for the directories it lies in
+func (c *sizeCache) forgetHolding(path string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.load()
+
+	for key := range c.entries {
+		if p := sizeCachePathOf(key); p == path || pathWithin(path, p) {
+			delete(c.entries, key)
+			c.dirty = true
+		}
+	}
+}
+
+// Reset forgets every entry without reading the on-disk cache again
+func (c *sizeCache) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries = make(map[string]sizeCacheEntry)
+	c.loaded = true
+	c.dirty = false
+}
+
+// Save writes the cache to disk, dropping expired entries and entries for
+// directories that no longer exist
+func (c *sizeCache) Save() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.file == "" || !c.dirty {
+		return nil
+	}
+
+	for key, entry := range c.entries {
+		if time.Since(entry.CachedAt) > c.ttl {
+			delete(c.entries, key)
+			continue
+		}
+		if _, err := os.Stat(sizeCachePathOf(key)); err != nil {
+			delete(c.entries, key)
+		}
+	}
+
+	data, err := json.Marshal(c.entries)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(c.file), 0755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(c.file, data, 0644); err != nil {
+		return err
+	}
+
+	c.dirty = false
+	return nil
+}
+
+// load reads the on-disk cache once; the caller must hold c.mu
+func (c *sizeCache) load() {
+	if c.loaded {
+		return
+	}
+	c.loaded = true
+
+	if c.file == "" {
+		return
+	}
+
+	data, err := os.ReadFile(c.file)
+	if err != nil {
+		return
+	}
+
+	var entries map[string]sizeCacheEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		// Corrupt cache is not worth reporting, just start over
+		return
+	}
+	for key, entry := range entries {
+		if _, ok := c.entries[key]; !ok {
+			c.entries[key] = entry
+		}
+	}
+}
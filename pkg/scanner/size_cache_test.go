@@ -0,0 +1,148 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSizeCache_HitAndInvalidation(t *testing.T) {
+	dir := t.TempDir()
+	target := filepath.Join(dir, "target")
+	if err := os.Mkdir(target, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	cache := newSizeCache("", time.Hour)
+	cache.Put("-sk", target, 4096)
+
+	if size, ok := cache.Get("-sk", target); !ok || size != 4096 {
+		t.Fatalf("Get() = %d, %v; want 4096, true", size, ok)
+	}
+
+	// A changed mtime means the directory contents changed
+	later := time.Now().Add(time.Minute)
+	if err := os.Chtimes(target, later, later); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := cache.Get("-sk", target); ok {
+		t.Error("Expected cache miss after mtime change")
+	}
+
+	cache.Put("-sk", target, 8192)
+	if err := os.Remove(target); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := cache.Get("-sk", target); ok {
+		t.Error("Expected cache miss for removed directory")
+	}
+	if _, ok := cache.entries[target]; ok {
+		t.Error("Removed directory should be dropped from the cache")
+	}
+}
+
+func TestSizeCache_TTL(t *testing.T) {
+	dir := t.TempDir()
+
+	cache := newSizeCache("", time.Hour)
+	cache.Put("-sk", dir, 1024)
+	key := sizeCacheKey("-sk", dir)
+	entry := cache.entries[key]
+	entry.CachedAt = time.Now().Add(-2 * time.Hour)
+	cache.entries[key] = entry
+
+	if _, ok := cache.Get("-sk", dir); ok {
+		t.Error("Expected cache miss for expired entry")
+	}
+}
+
+func TestSizeCache_SaveAndLoad(t *testing.T) {
+	dir := t.TempDir()
+	target := filepath.Join(dir, "target")
+	if err := os.Mkdir(target, 0755); err != nil {
+		t.Fatal(err)
+	}
+	file := filepath.Join(dir, "config", sizeCacheFileName)
+
+	cache := newSizeCache(file, time.Hour)
+	cache.Put("-sk", target, 2048)
+	if err := cache.Save(); err != nil {
+		t.Fatalf("Save() error: %v", err)
+	}
+
+	reloaded := newSizeCache(file, time.Hour)
+	if size, ok := reloaded.Get("-sk", target); !ok || size != 2048 {
+		t.Errorf("Get() after reload = %d, %v; want 2048, true", size, ok)
+	}
+}
+
+func TestSizeCache_Count(t *testing.T) {
+	target := t.TempDir()
+	cache := newSizeCache("", time.Hour)
+
+	cache.PutCount("-sk", target, 12)
+	if _, ok := cache.GetCount("-sk", target); ok {
+		t.Error("A count without a cached size should not be stored")
+	}
+
+	cache.Put("-sk", target, 4096)
+	if _, ok := cache.GetCount("-sk", target); ok {
+		t.Error("Expected no count before PutCount")
+	}
+	cache.PutCount("-sk", target, 12)
+	if n, ok := cache.GetCount("-sk", target); !ok || n != 12 {
+		t.Errorf("GetCount() = %d, %v; want 12, true", n, ok)
+	}
+
+	// A fresh size measurement invalidates the count
+	cache.Put("-sk", target, 8192)
+	if _, ok := cache.GetCount("-sk", target); ok {
+		t.Error("Put should drop the previous count")
+	}
+}
+
+func TestSizeCache_Modes(t *testing.T) {
+	dir := t.TempDir()
+
+	cache := newSizeCache("", time.Hour)
+	cache.Put("-sk", dir, 1024)
+	if _, ok := cache.Get("-skH", dir); ok {
+		t.Error("A size measured with -sk should not answer for -skH")
+	}
+
+	cache.Put("-skH", dir, 4096)
+	if size, ok := cache.Get("-sk", dir); !ok || size != 1024 {
+		t.Errorf("Get(-sk) = %d, %v; want 1024, true", size, ok)
+	}
+
+	cache.Forget(dir)
+	if _, ok := cache.Get("-skH", dir); ok {
+		t.Error("Forget should drop every mode")
+	}
+}
+
+func TestSizeCache_ForgetHolding(t *testing.T) {
+	parent := t.TempDir()
+	child := filepath.Join(parent, "child")
+	other := t.TempDir()
+	if err := os.Mkdir(child, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	cache := newSizeCache("", time.Hour)
+	cache.Put("-sk", parent, 4096)
+	cache.Put("-sk", child, 1024)
+	cache.Put("-sk", other, 2048)
+
+	cache.forgetHolding(child)
+	if _, ok := cache.Get("-sk", child); ok {
+		t.Error("The cleaned directory should be forgotten")
+	}
+	if _, ok := cache.Get("-sk", parent); ok {
+		t.Error("A directory holding the cleaned one should be forgotten")
+	}
+	if _, ok := cache.Get("-sk", other); !ok {
+		t.Error("Unrelated directories should stay cached")
+	}
+}
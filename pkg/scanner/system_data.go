@@ -1,12 +1,15 @@
 package scanner
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"runtime"
 	"strconv"
 	"strings"
+	"sync"
 )
 
 // SystemDataScanner deep system data scanner
@@ -14,6 +17,7 @@ import (
 type SystemDataScanner struct {
 	results []SystemDataItem
 	errors  []string
+	ctx     context.Context // kills running du processes once done
 }
 
 // SystemDataItem system data item
@@ -24,6 +28,9 @@ type SystemDataItem struct {
 	Description string
 	RiskLevel   RiskLevel
 	CanClean    bool
+	SizeUnknown bool     // Size could not be measured; Size is 0, not an estimate
+	Snapshots   []string // APFS snapshot names, set on the snapshots item only
+	Exclude     []string // Nested items, measured and cleaned on their own
 }
 
 // NewSystemDataScanner creates system data scanner
@@ -31,115 +38,171 @@ func NewSystemDataScanner() *SystemDataScanner {
 	return &SystemDataScanner{
 		results: make([]SystemDataItem, 0),
 		errors:  make([]string, 0),
+		ctx:     context.Background(),
 	}
 }
 
-// Scan scans system data
+// Scan scans system data. The sub-scans are independent and mostly wait on
+// du, so they run on a worker pool; each writes into its own scanner and the
+// results are joined in the fixed order below.
 func (s *SystemDataScanner) Scan() ([]SystemDataItem, error) {
+	return s.ScanContext(context.Background())
+}
+
+// ScanContext is Scan with cancellation: once ctx is done, running du
+// processes are killed, remaining sub-scans are skipped and ctx.Err() is
+// returned
+func (s *SystemDataScanner) ScanContext(ctx context.Context) ([]SystemDataItem, error) {
+	s.ctx = ctx
 	homeDir := GetRealHomeDir()
 
-	// 1. Time Machine local snapshots
-	s.scanTimeMachineSnapshots()
+	tasks := []func(s *SystemDataScanner){
+		// 1. Time Machine local snapshots
+		func(s *SystemDataScanner) { s.scanTimeMachineSnapshots() },
+
+		// 2. Spotlight index
+		func(s *SystemDataScanner) { s.scanSpotlightIndex() },
+
+		// 3. System swap files and sleep images
+		func(s *SystemDataScanner) { s.scanSwapFiles() },
 
-	// 2. Spotlight index
-	s.scanSpotlightIndex()
+		// 4. System-level caches
+		func(s *SystemDataScanner) { s.scanSystemCaches(homeDir) },
 
-	// 3. System swap files and sleep images
-	s.scanSwapFiles()
+		// 5. System logs
+		func(s *SystemDataScanner) { s.scanSystemLogs() },
 
-	// 4. System-level caches
-	s.scanSystemCaches(homeDir)
+		// 6. FSEvents database
+		func(s *SystemDataScanner) { s.scanFSEvents() },
 
-	// 5. System logs
-	s.scanSystemLogs()
+		// 7. iCloud Drive cache
+		func(s *SystemDataScanner) { s.scanICloudData(homeDir) },
 
-	// 6. FSEvents database
-	s.scanFSEvents()
+		// 8. System temporary files
+		func(s *SystemDataScanner) { s.scanSystemTemp() },
 
-	// 7. iCloud Drive cache
-	s.scanICloudData(homeDir)
+		// 9. CoreDuet database (search history, etc.)
+		func(s *SystemDataScanner) { s.scanCoreDuet(homeDir) },
 
-	// 8. System temporary files
-	s.scanSystemTemp()
+		// 10. Siri data
+		func(s *SystemDataScanner) { s.scanSiriData(homeDir) },
 
-	// 9. CoreDuet database (search history, etc.)
-	s.scanCoreDuet(homeDir)
+		// 11. System diagnostic data
+		func(s *SystemDataScanner) { s.scanSystemDiagnostics(homeDir) },
 
-	// 10. Siri data
-	s.scanSiriData(homeDir)
+		// 12. Safari data
+		func(s *SystemDataScanner) { s.scanSafariData(homeDir) },
 
-	// 11. System diagnostic data
-	s.scanSystemDiagnostics(homeDir)
+		// 13. Mail data
+		func(s *SystemDataScanner) { s.scanMailData(homeDir) },
 
-	// 12. Safari data
-	s.scanSafariData(homeDir)
+		// 14. Photos database
+		func(s *SystemDataScanner) { s.scanPhotosData(homeDir) },
 
-	// 13. Mail data
-	s.scanMailData(homeDir)
+		// 15. App container data
+		func(s *SystemDataScanner) { s.scanAppContainers(homeDir) },
 
-	// 14. Photos database
-	s.scanPhotosData(homeDir)
+		// 16. System framework caches
+		func(s *SystemDataScanner) { s.scanFrameworkCaches() },
 
-	// 15. App container data
-	s.scanAppContainers(homeDir)
+		// 17. APFS snapshots
+		func(s *SystemDataScanner) { s.scanAPFSSnapshots() },
 
-	// 16. System framework caches
-	s.scanFrameworkCaches()
+		// 18. System preload files
+		func(s *SystemDataScanner) { s.scanPrelinkedKernels() },
 
-	// 17. APFS snapshots
-	s.scanAPFSSnapshots()
+		// 19. System font caches
+		func(s *SystemDataScanner) { s.scanFontCaches() },
 
-	// 18. System preload files
-	s.scanPrelinkedKernels()
+		// 20. System audio caches
+		func(s *SystemDataScanner) { s.scanAudioCaches() },
 
-	// 19. System font caches
-	s.scanFontCaches()
+		// 21. System update cache
+		func(s *SystemDataScanner) { s.scanSoftwareUpdateCache() },
 
-	// 20. System audio caches
-	s.scanAudioCaches()
+		// 22. System resource files
+		func(s *SystemDataScanner) { s.scanSystemResources() },
 
-	// 21. System update cache
-	s.scanSoftwareUpdateCache()
+		// 23. System databases
+		func(s *SystemDataScanner) { s.scanSystemDatabases() },
 
-	// 22. System resource files
-	s.scanSystemResources()
+		// 24. User databases
+		func(s *SystemDataScanner) { s.scanUserDatabases(homeDir) },
 
-	// 23. System databases
-	s.scanSystemDatabases()
+		// 25. System metadata
+		func(s *SystemDataScanner) { s.scanSystemMetadata() },
 
-	// 24. User databases
-	s.scanUserDatabases(homeDir)
+		// 26. Virtual machine data
+		func(s *SystemDataScanner) { s.scanVirtualMachines(homeDir) },
 
-	// 25. System metadata
-	s.scanSystemMetadata()
+		// 27. Docker images and container data
+		func(s *SystemDataScanner) { s.scanDockerData(homeDir) },
 
-	// 26. Virtual machine data
-	s.scanVirtualMachines(homeDir)
+		// 28. User data directories
+		func(s *SystemDataScanner) { s.scanUserDataDirectories(homeDir) },
 
-	// 27. Docker images and container data
-	s.scanDockerData(homeDir)
+		// 29. System backups and archives
+		func(s *SystemDataScanner) { s.scanSystemArchives(homeDir) },
 
-	// 28. User data directories
-	s.scanUserDataDirectories(homeDir)
+		// 30. Large app data
+		func(s *SystemDataScanner) { s.scanLargeAppData(homeDir) },
 
-	// 29. System backups and archives
-	s.scanSystemArchives(homeDir)
+		// 31. System extensions and plugins
+		func(s *SystemDataScanner) { s.scanSystemExtensions() },
 
-	// 30. Large app data
-	s.scanLargeAppData(homeDir)
+		// 32. Hidden system and app data
+		func(s *SystemDataScanner) { s.scanHiddenSystemData(homeDir) },
 
-	// 31. System extensions and plugins
-	s.scanSystemExtensions()
+		// 33. User container data
+		func(s *SystemDataScanner) { s.scanUserContainers(homeDir) },
 
-	// 32. Hidden system and app data
-	s.scanHiddenSystemData(homeDir)
+		// 34. System preload and cache
+		func(s *SystemDataScanner) { s.scanSystemPreload() },
+	}
 
-	// 33. User container data
-	s.scanUserContainers(homeDir)
+	numWorkers := runtime.NumCPU()
+	if numWorkers > 8 {
+		numWorkers = 8
+	}
+	numWorkers = scanWorkers(numWorkers)
 
-	// 34. System preload and cache
-	s.scanSystemPreload()
+	partial := make([]*SystemDataScanner, len(tasks))
+	jobs := make(chan int, len(tasks))
+	for i := range tasks {
+		jobs <- i
+	}
+	close(jobs)
 
+	var wg sync.WaitGroup
+	for w := 0; w < numWorkers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				sub := NewSystemDataScanner()
+				if ctx.Err() == nil {
+					sub.ctx = ctx
+					tasks[i](sub)
+				}
+				partial[i] = sub
+			}
+		}()
+	}
+	wg.Wait()
+
+	s.results = s.results[:0]
+	s.errors = s.errors[:0]
+	for _, sub := range partial {
+		s.results = append(s.results, sub.results...)
+		s.errors = append(s.errors, sub.errors...)
+	}
+	s.results = collapseSystemData(s.results)
+
+	_ = dirSizeCache.Save()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 	return s.results, nil
 }
 
@@ -151,7 +214,7 @@ func (s *SystemDataScanner) scanTimeMachineSnapshots() {
 		return
 	}
 
-	size := getDirSizeDU(snapshotsPath)
+	size := s.dirSize(snapshotsPath)
 	if size > 0 {
 		s.results = append(s.results, SystemDataItem{
 			Name:        "Time Machine Local Snapshots",
@@ -176,18 +239,71 @@ func (s *SystemDataScanner) scanSpotlightIndex() {
 			continue
 		}
 
-		size := getDirSizeDU(path)
+		size := s.dirSize(path)
 		if size > 0 {
 			s.results = append(s.results, SystemDataItem{
 				Name:        "Spotlight Index",
 				Path:        path,
 				Size:        size,
-				Description: "Spotlight search index database",
+				Description: "Spotlight search index database (rebuild with: sudo mdutil -E /)",
 				RiskLevel:   RiskMedium,
 				CanClean:    false,
 			})
 		}
 	}
+
+	if homeDir := GetRealHomeDir(); homeDir != "" {
+		s.scanRebuildable([]rebuildableData{
+			{"Core Spotlight Index", filepath.Join(homeDir, "Library", "Metadata", "CoreSpotlight"), "Per-app search index; apps re-donate their items and Spotlight rebuilds it"},
+		})
+	}
+}
+
+// rebuildableData is a database or index that its owner regenerates on its
+// own, so removing it frees space without losing user data
+type rebuildableData struct {
+	name        string
+	pattern     string // filepath.Glob pattern
+	description string
+}
+
+// scanRebuildable adds a cleanable item for every path matching the given
+// patterns and returns their total size, so callers reporting the enclosing
+// directory can avoid counting the same bytes twice
+func (s *SystemDataScanner) scanRebuildable(entries []rebuildableData) int64 {
+	var total int64
+	for _, entry := range entries {
+		matches, _ := filepath.Glob(entry.pattern)
+		for _, path := range matches {
+			info, err := os.Stat(path)
+			if err != nil {
+				continue
+			}
+
+			size := info.Size()
+			if info.IsDir() {
+				size = s.dirSize(path)
+			}
+			if size <= 0 {
+				continue
+			}
+
+			name := entry.name
+			if len(matches) > 1 {
+				name = fmt.Sprintf("%s (%s)", entry.name, filepath.Base(path))
+			}
+			s.results = append(s.results, SystemDataItem{
+				Name:        name,
+				Path:        path,
+				Size:        size,
+				Description: entry.description,
+				RiskLevel:   RiskMedium,
+				CanClean:    true,
+			})
+			total += size
+		}
+	}
+	return total
 }
 
 // scanSystemExtensions scans system extensions and plugins
@@ -210,7 +326,7 @@ func (s *SystemDataScanner) scanSystemExtensions() {
 			continue
 		}
 
-		size := getDirSizeDU(ext.path)
+		size := s.dirSize(ext.path)
 		if size > 0 {
 			s.results = append(s.results, SystemDataItem{
 				Name:        ext.name,
@@ -252,7 +368,7 @@ func (s *SystemDataScanner) scanHiddenSystemData(homeDir string) {
 			continue
 		}
 
-		size := getDirSizeDU(hidden.path)
+		size := s.dirSize(hidden.path)
 		if size > 0 {
 			s.results = append(s.results, SystemDataItem{
 				Name:        hidden.name,
@@ -285,7 +401,7 @@ func (s *SystemDataScanner) scanUserContainers(homeDir string) {
 		}
 
 		fullPath := filepath.Join(containerPath, entry.Name())
-		size := getDirSizeDU(fullPath)
+		size := s.dirSize(fullPath)
 		
 		if size > 100*1024*1024 { // Only show containers larger than 100MB
 			s.results = append(s.results, SystemDataItem{
@@ -319,7 +435,7 @@ func (s *SystemDataScanner) scanSystemPreload() {
 			continue
 		}
 
-		size := getDirSizeDU(preload.path)
+		size := s.dirSize(preload.path)
 		if size > 0 {
 			s.results = append(s.results, SystemDataItem{
 				Name:        preload.name,
@@ -387,7 +503,7 @@ func (s *SystemDataScanner) scanSystemCaches(homeDir string) {
 			continue
 		}
 
-		size := getDirSizeDU(cache.path)
+		size := s.dirSize(cache.path)
 		if size > 0 {
 			s.results = append(s.results, SystemDataItem{
 				Name:        cache.name,
@@ -419,7 +535,7 @@ func (s *SystemDataScanner) scanSystemLogs() {
 			continue
 		}
 
-		size := getDirSizeDU(log.path)
+		size := s.dirSize(log.path)
 		if size > 0 {
 			s.results = append(s.results, SystemDataItem{
 				Name:        log.name,
@@ -441,7 +557,7 @@ func (s *SystemDataScanner) scanFSEvents() {
 		return
 	}
 
-	size := getDirSizeDU(fseventsPath)
+	size := s.dirSize(fseventsPath)
 	if size > 0 {
 		s.results = append(s.results, SystemDataItem{
 			Name:        "FSEvents Database",
@@ -470,7 +586,7 @@ func (s *SystemDataScanner) scanICloudData(homeDir string) {
 			continue
 		}
 
-		size := getDirSizeDU(icloud.path)
+		size := s.dirSize(icloud.path)
 		if size > 0 {
 			s.results = append(s.results, SystemDataItem{
 				Name:        icloud.name,
@@ -500,7 +616,7 @@ func (s *SystemDataScanner) scanSystemTemp() {
 			continue
 		}
 
-		size := getDirSizeDU(temp.path)
+		size := s.dirSize(temp.path)
 		if size > 0 {
 			s.results = append(s.results, SystemDataItem{
 				Name:        temp.name,
@@ -522,7 +638,7 @@ func (s *SystemDataScanner) scanCoreDuet(homeDir string) {
 		return
 	}
 
-	size := getDirSizeDU(coreDuetPath)
+	size := s.dirSize(coreDuetPath)
 	if size > 0 {
 		s.results = append(s.results, SystemDataItem{
 			Name:        "CoreDuet Database",
@@ -550,7 +666,7 @@ func (s *SystemDataScanner) scanSiriData(homeDir string) {
 			continue
 		}
 
-		size := getDirSizeDU(siri.path)
+		size := s.dirSize(siri.path)
 		if size > 0 {
 			s.results = append(s.results, SystemDataItem{
 				Name:        siri.name,
@@ -580,7 +696,7 @@ func (s *SystemDataScanner) scanSystemDiagnostics(homeDir string) {
 			continue
 		}
 
-		size := getDirSizeDU(diag.path)
+		size := s.dirSize(diag.path)
 		if size > 0 {
 			s.results = append(s.results, SystemDataItem{
 				Name:        diag.name,
@@ -609,7 +725,7 @@ func (s *SystemDataScanner) scanSafariData(homeDir string) {
 			continue
 		}
 
-		size := getDirSizeDU(safari.path)
+		size := s.dirSize(safari.path)
 		if size > 0 {
 			s.results = append(s.results, SystemDataItem{
 				Name:        safari.name,
@@ -631,7 +747,10 @@ func (s *SystemDataScanner) scanMailData(homeDir string) {
 		return
 	}
 
-	size := getDirSizeDU(mailPath)
+	size := s.dirSize(mailPath)
+	size -= s.scanRebuildable([]rebuildableData{
+		{"Mail Envelope Index", filepath.Join(mailPath, "V*", "MailData", "Envelope Index*"), "Mail will rebuild this index on next launch (quit Mail first)"},
+	})
 	if size > 0 {
 		s.results = append(s.results, SystemDataItem{
 			Name:        "Mail Data",
@@ -652,7 +771,10 @@ func (s *SystemDataScanner) scanPhotosData(homeDir string) {
 		return
 	}
 
-	size := getDirSizeDU(photosPath)
+	size := s.dirSize(photosPath)
+	size -= s.scanRebuildable([]rebuildableData{
+		{"Photos Search Index", filepath.Join(photosPath, "database", "search"), "Photos will rebuild its search index in the background (quit Photos first)"},
+	})
 	if size > 0 {
 		s.results = append(s.results, SystemDataItem{
 			Name:        "Photos Library",
@@ -673,7 +795,7 @@ func (s *SystemDataScanner) scanAppContainers(homeDir string) {
 		return
 	}
 
-	size := getDirSizeDU(containersPath)
+	size := s.dirSize(containersPath)
 	if size > 0 {
 		s.results = append(s.results, SystemDataItem{
 			Name:        "App Containers",
@@ -698,7 +820,7 @@ func (s *SystemDataScanner) scanFrameworkCaches() {
 			continue
 		}
 
-		size := getDirSizeDU(path)
+		size := s.dirSize(path)
 		if size > 0 {
 			s.results = append(s.results, SystemDataItem{
 				Name:        fmt.Sprintf("System Frameworks (%s)", filepath.Base(path)),
@@ -742,92 +864,284 @@ func (s *SystemDataScanner) GetErrors() []string {
 	return s.errors
 }
 
-// getDirSizeDU uses du command to get directory size
-func getDirSizeDU(path string) int64 {
-	cmd := exec.Command("du", "-sk", path)
+// duDirSize uses the du command to get a directory's size. du still prints a
+// total when parts of the tree are unreadable, but that total is too low, so
+// it is treated as a failure and the reason returned.
+func duDirSize(ctx context.Context, path string) (int64, error) {
+	if size, ok := dirSizeCache.Get("-sk", path); ok {
+		return size, nil
+	}
+
+	cmd := ScanCommand(ctx, "du", "-sk", "--", path)
 	output, err := cmd.Output()
 	if err != nil {
-		return -1
+		return -1, duError(err)
 	}
 
 	fields := strings.Fields(string(output))
 	if len(fields) < 1 {
-		return -1
+		return -1, fmt.Errorf("unexpected du output")
 	}
 
 	sizeKB, err := strconv.ParseInt(fields[0], 10, 64)
 	if err != nil {
-		return -1
+		return -1, fmt.Errorf("unexpected du output %q", fields[0])
 	}
 
-	return sizeKB * 1024
+	dirSizeCache.Put("-sk", path, sizeKB*1024)
+	return sizeKB * 1024, nil
 }
 
-// scanAPFSSnapshots scans APFS snapshots
-func (s *SystemDataScanner) scanAPFSSnapshots() {
-	cmd := exec.Command("tmutil", "listlocalsnapshots", "/")
-	output, err := cmd.Output()
+// duError turns a failed du run into a short reason, pointing at Full Disk
+// Access for the common permission case
+func duError(err error) error {
+	exitErr, ok := err.(*exec.ExitError)
+	if !ok {
+		return err
+	}
+	stderr := string(exitErr.Stderr)
+	switch {
+	case strings.Contains(stderr, "Operation not permitted"), strings.Contains(stderr, "Permission denied"):
+		return fmt.Errorf("permission denied (grant Full Disk Access or run with sudo)")
+	case stderr != "":
+		line, _, _ := strings.Cut(strings.TrimSpace(stderr), "\n")
+		return fmt.Errorf("%s", line)
+	}
+	return err
+}
+
+// dirSize measures path with du, recording a failure in s.errors so that a
+// directory that cannot be measured is reported instead of silently missing
+func (s *SystemDataScanner) dirSize(path string) int64 {
+	size, err := duDirSize(s.ctx, path)
 	if err != nil {
+		s.errors = append(s.errors, fmt.Sprintf("%s: %v", path, err))
+		return -1
+	}
+	return size
+}
+
+// scanAPFSSnapshots scans APFS snapshots, see LocalSnapshots
+func (s *SystemDataScanner) scanAPFSSnapshots() {
+	names, size, purgeable := LocalSnapshots()
+	if len(names) == 0 {
 		return
 	}
 
-	snapshots := strings.Split(string(output), "\n")
-	snapshotCount := 0
-	for _, snap := range snapshots {
-		if strings.TrimSpace(snap) != "" {
-			snapshotCount++
-		}
-	}
-
-	if snapshotCount > 0 {
-		// Get actual space used by APFS snapshots
-		cmd = exec.Command("tmutil", "localsnapshot")
-		output, err = cmd.CombinedOutput()
-		if err == nil {
-			// Use diskutil to get snapshot info
-			cmd = exec.Command("diskutil", "apfs", "listSnapshots", "/")
-			output, err = cmd.CombinedOutput()
-			
-			var snapshotSize int64
-			if err == nil {
-				lines := strings.Split(string(output), "\n")
-				for _, line := range lines {
-					if strings.Contains(line, "size") || strings.Contains(line, "Size") {
-						fields := strings.Fields(line)
-						for i, field := range fields {
-							if strings.Contains(field, "GB") || strings.Contains(field, "MB") {
-								if i > 0 {
-									sizeStr := strings.TrimSuffix(fields[i-1], "(")
-									sizeStr = strings.TrimSpace(sizeStr)
-									if size, err := strconv.ParseFloat(sizeStr, 64); err == nil {
-										if strings.Contains(field, "GB") {
-											snapshotSize += int64(size * 1024 * 1024 * 1024)
-										} else if strings.Contains(field, "MB") {
-											snapshotSize += int64(size * 1024 * 1024)
-										}
-									}
-								}
-							}
-						}
-					}
+	item := SystemDataItem{
+		Name:        fmt.Sprintf("APFS Local Snapshots (%d)", len(names)),
+		Path:        "/.snapshots",
+		Size:        size,
+		Description: "APFS file system snapshots, consuming disk space",
+		RiskLevel:   RiskMedium,
+		CanClean:    false,
+		Snapshots:   names,
+	}
+	if size == 0 {
+		item.SizeUnknown = true
+		item.Description = "APFS file system snapshots (size unavailable; they share blocks with your files, so deleting them may free little)"
+	}
+	if purgeable {
+		item.Description += "; purgeable, macOS frees them automatically when space runs low"
+	}
+	s.results = append(s.results, item)
+}
+
+// LocalSnapshots lists the APFS snapshots of the boot volume. diskutil's
+// plist output is preferred, then tmutil, diskutil's text output and mount
+// are tried in turn. size is 0 when no tool reports one; it is never guessed.
+// purgeable is true if diskutil marks every snapshot purgeable.
+func LocalSnapshots() (names []string, size int64, purgeable bool) {
+	fromPlist := false
+	purgeableCount := 0
+
+	if out, err := runTool("diskutil", "apfs", "listSnapshots", "-plist", "/"); err == nil {
+		if snaps, err := parseDiskutilSnapshotsPlist(out); err == nil {
+			fromPlist = true
+			for _, snap := range snaps {
+				names = append(names, snap.Name)
+				size += snap.Size
+				if snap.Purgeable {
+					purgeableCount++
 				}
 			}
+		}
+	}
+
+	if !fromPlist {
+		if out, err := runTool("tmutil", "listlocalsnapshots", "/"); err == nil {
+			names = parseTmutilSnapshots(out)
+		}
 
-			// If unable to get specific size, use estimated value
-			if snapshotSize == 0 {
-				snapshotSize = int64(snapshotCount) * 1024 * 1024 * 1024 // Assume 1GB per snapshot
+		// diskutil is the only tool that may report sizes, so ask it even when
+		// tmutil already listed the snapshots
+		if out, err := runTool("diskutil", "apfs", "listSnapshots", "/"); err == nil {
+			diskutilNames, diskutilSize := parseDiskutilSnapshots(out)
+			if len(names) == 0 {
+				names = diskutilNames
 			}
+			size = diskutilSize
+		}
+	}
 
-			s.results = append(s.results, SystemDataItem{
-				Name:        fmt.Sprintf("APFS Local Snapshots (%d)", snapshotCount),
-				Path:        "/.snapshots",
-				Size:        snapshotSize,
-				Description: "APFS file system snapshots, consuming disk space",
-				RiskLevel:   RiskMedium,
-				CanClean:    false,
-			})
+	if len(names) == 0 {
+		if out, err := runTool("mount"); err == nil {
+			names = parseMountedSnapshots(out)
+		}
+	}
+
+	return names, size, len(names) > 0 && purgeableCount == len(names)
+}
+
+// apfsSnapshot is one snapshot from `diskutil apfs listSnapshots -plist`
+type apfsSnapshot struct {
+	Name      string
+	Size      int64 // 0 if diskutil does not report a size
+	Purgeable bool
+}
+
+// parseDiskutilSnapshotsPlist parses `diskutil apfs listSnapshots -plist /`.
+// Each entry of the Snapshots array is a dict with SnapshotName and, on some
+// macOS versions, Purgeable and size keys; any integer key containing "Size"
+// is taken as the snapshot's byte count.
+func parseDiskutilSnapshotsPlist(output string) ([]apfsSnapshot, error) {
+	v, err := decodePlist(strings.NewReader(output))
+	if err != nil {
+		return nil, err
+	}
+	root, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unexpected plist root")
+	}
+	list, _ := root["Snapshots"].([]interface{})
+
+	var snaps []apfsSnapshot
+	for _, entry := range list {
+		dict, ok := entry.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		snap := apfsSnapshot{}
+		snap.Name, _ = dict["SnapshotName"].(string)
+		snap.Purgeable, _ = dict["Purgeable"].(bool)
+		for key, value := range dict {
+			if n, ok := value.(int64); ok && strings.Contains(key, "Size") {
+				snap.Size += n
+			}
+		}
+		if snap.Name != "" {
+			snaps = append(snaps, snap)
+		}
+	}
+	return snaps, nil
+}
+
+// runTool runs a system command and returns its stdout. A missing binary or a
+// non-zero exit is an error, so callers can fall back to another tool.
+func runTool(name string, args ...string) (string, error) {
+	path, err := exec.LookPath(name)
+	if err != nil {
+		return "", err
+	}
+	out, err := exec.Command(path, args...).Output()
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", name, err)
+	}
+	return string(out), nil
+}
+
+// parseTmutilSnapshots parses `tmutil listlocalsnapshots /`:
+//
+//	Snapshots for disk /:
+//	com.apple.TimeMachine.2024-01-15-101010.local
+func parseTmutilSnapshots(output string) []string {
+	var names []string
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasSuffix(line, ":") {
+			continue
+		}
+		names = append(names, line)
+	}
+	return names
+}
+
+// parseDiskutilSnapshots parses `diskutil apfs listSnapshots /`. Each snapshot
+// has a "Name:" line; some macOS versions add a size line such as
+// "Size: 1.2 GB (1234567890 Bytes)". Byte counts are preferred when present.
+func parseDiskutilSnapshots(output string) ([]string, int64) {
+	var names []string
+	var total int64
+
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(strings.TrimLeft(line, "+-| "))
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+
+		switch {
+		case key == "name":
+			names = append(names, value)
+		case strings.Contains(key, "size"):
+			total += parseSizeField(value)
+		}
+	}
+
+	return names, total
+}
+
+// parseSizeField parses "1.2 GB (1234567890 Bytes)" or "512 MB", returning 0
+// if the value is not understood
+func parseSizeField(value string) int64 {
+	if open := strings.Index(value, "("); open >= 0 {
+		fields := strings.Fields(value[open+1:])
+		if len(fields) >= 2 && strings.HasPrefix(strings.ToLower(fields[1]), "byte") {
+			if n, err := strconv.ParseInt(fields[0], 10, 64); err == nil {
+				return n
+			}
+		}
+	}
+
+	fields := strings.Fields(value)
+	if len(fields) < 2 {
+		return 0
+	}
+	n, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return 0
+	}
+	switch strings.ToUpper(fields[1]) {
+	case "KB":
+		return int64(n * 1e3)
+	case "MB":
+		return int64(n * 1e6)
+	case "GB":
+		return int64(n * 1e9)
+	case "TB":
+		return int64(n * 1e12)
+	}
+	return 0
+}
+
+// parseMountedSnapshots finds Time Machine snapshots in `mount` output, e.g.
+//
+//	com.apple.TimeMachine.2024-01-15-101010.local@/dev/disk3s5 on /Volumes/... (apfs, ...)
+func parseMountedSnapshots(output string) []string {
+	var names []string
+	for _, line := range strings.Split(output, "\n") {
+		device := strings.Fields(line)
+		if len(device) == 0 {
+			continue
+		}
+		name, _, ok := strings.Cut(device[0], "@")
+		if ok && strings.HasPrefix(name, "com.apple.TimeMachine.") {
+			names = append(names, name)
 		}
 	}
+	return names
 }
 
 // scanPrelinkedKernels scans system preload files
@@ -846,7 +1160,7 @@ func (s *SystemDataScanner) scanPrelinkedKernels() {
 			continue
 		}
 
-		size := getDirSizeDU(p.path)
+		size := s.dirSize(p.path)
 		if size > 0 {
 			s.results = append(s.results, SystemDataItem{
 				Name:        p.name,
@@ -875,7 +1189,7 @@ func (s *SystemDataScanner) scanFontCaches() {
 			continue
 		}
 
-		size := getDirSizeDU(p.path)
+		size := s.dirSize(p.path)
 		if size > 0 {
 			s.results = append(s.results, SystemDataItem{
 				Name:        p.name,
@@ -904,7 +1218,7 @@ func (s *SystemDataScanner) scanAudioCaches() {
 			continue
 		}
 
-		size := getDirSizeDU(p.path)
+		size := s.dirSize(p.path)
 		if size > 0 {
 			s.results = append(s.results, SystemDataItem{
 				Name:        p.name,
@@ -934,7 +1248,7 @@ func (s *SystemDataScanner) scanSoftwareUpdateCache() {
 			continue
 		}
 
-		size := getDirSizeDU(p.path)
+		size := s.dirSize(p.path)
 		if size > 0 {
 			s.results = append(s.results, SystemDataItem{
 				Name:        p.name,
@@ -966,7 +1280,7 @@ func (s *SystemDataScanner) scanSystemResources() {
 			continue
 		}
 
-		size := getDirSizeDU(p.path)
+		size := s.dirSize(p.path)
 		if size > 0 {
 			s.results = append(s.results, SystemDataItem{
 				Name:        p.name,
@@ -997,7 +1311,7 @@ func (s *SystemDataScanner) scanSystemDatabases() {
 			continue
 		}
 
-		size := getDirSizeDU(p.path)
+		size := s.dirSize(p.path)
 		if size > 0 {
 			s.results = append(s.results, SystemDataItem{
 				Name:        p.name,
@@ -1029,7 +1343,7 @@ func (s *SystemDataScanner) scanUserDatabases(homeDir string) {
 			continue
 		}
 
-		size := getDirSizeDU(p.path)
+		size := s.dirSize(p.path)
 		if size > 0 {
 			s.results = append(s.results, SystemDataItem{
 				Name:        p.name,
@@ -1059,7 +1373,7 @@ func (s *SystemDataScanner) scanSystemMetadata() {
 			continue
 		}
 
-		size := getDirSizeDU(p.path)
+		size := s.dirSize(p.path)
 		if size > 0 {
 			s.results = append(s.results, SystemDataItem{
 				Name:        p.name,
@@ -1091,7 +1405,7 @@ func (s *SystemDataScanner) scanVirtualMachines(homeDir string) {
 			continue
 		}
 
-		size := getDirSizeDU(vm.path)
+		size := s.dirSize(vm.path)
 		if size > 0 {
 			s.results = append(s.results, SystemDataItem{
 				Name:        vm.name,
@@ -1121,7 +1435,7 @@ func (s *SystemDataScanner) scanDockerData(homeDir string) {
 			continue
 		}
 
-		size := getDirSizeDU(docker.path)
+		size := s.dirSize(docker.path)
 		if size > 0 {
 			s.results = append(s.results, SystemDataItem{
 				Name:        docker.name,
@@ -1155,7 +1469,7 @@ func (s *SystemDataScanner) scanUserDataDirectories(homeDir string) {
 			continue
 		}
 
-		size := getDirSizeDU(data.path)
+		size := s.dirSize(data.path)
 		if size > 0 {
 			s.results = append(s.results, SystemDataItem{
 				Name:        data.name,
@@ -1186,7 +1500,7 @@ func (s *SystemDataScanner) scanSystemArchives(homeDir string) {
 			continue
 		}
 
-		size := getDirSizeDU(archive.path)
+		size := s.dirSize(archive.path)
 		if size > 0 {
 			s.results = append(s.results, SystemDataItem{
 				Name:        archive.name,
@@ -1223,7 +1537,7 @@ func (s *SystemDataScanner) scanLargeAppData(homeDir string) {
 			continue
 		}
 
-		size := getDirSizeDU(app.path)
+		size := s.dirSize(app.path)
 		if size > 0 {
 			s.results = append(s.results, SystemDataItem{
 				Name:        app.name,
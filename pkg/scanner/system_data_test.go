@@ -0,0 +1,207 @@
+package scanner
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseTmutilSnapshots(t *testing.T) {
+	out := "Snapshots for disk /:\ncom.apple.TimeMachine.2024-01-15-101010.local\ncom.apple.TimeMachine.2024-01-15-111010.local\n"
+	names := parseTmutilSnapshots(out)
+	if len(names) != 2 || names[0] != "com.apple.TimeMachine.2024-01-15-101010.local" {
+		t.Errorf("parseTmutilSnapshots() = %v", names)
+	}
+
+	if names := parseTmutilSnapshots("Snapshots for disk /:\n"); len(names) != 0 {
+		t.Errorf("Expected no snapshots, got %v", names)
+	}
+}
+
+func TestParseDiskutilSnapshots(t *testing.T) {
+	out := `Snapshots for disk3s5 (2 found)
+|
++-- 6B1F2C4A-0000-0000-0000-000000000001
+|   Name:        com.apple.TimeMachine.2024-01-15-101010.local
+|   XID:         123456
+|   Size:        1.5 GB (1500000000 Bytes)
+|
++-- 6B1F2C4A-0000-0000-0000-000000000002
+    Name:        com.apple.TimeMachine.2024-01-15-111010.local
+    XID:         123457
+    Size:        512 MB
+`
+	names, size := parseDiskutilSnapshots(out)
+	if len(names) != 2 {
+		t.Errorf("Expected 2 snapshots, got %v", names)
+	}
+	if want := int64(1500000000 + 512000000); size != want {
+		t.Errorf("size = %d, want %d", size, want)
+	}
+
+	// No size lines: size must stay unknown (0), never estimated
+	_, size = parseDiskutilSnapshots("+-- X\n    Name: com.apple.TimeMachine.a.local\n")
+	if size != 0 {
+		t.Errorf("Expected unknown size, got %d", size)
+	}
+}
+
+func TestParseDiskutilSnapshotsPlist(t *testing.T) {
+	out := `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+	<key>Snapshots</key>
+	<array>
+		<dict>
+			<key>Purgeable</key>
+			<true/>
+			<key>SnapshotName</key>
+			<string>com.apple.TimeMachine.2024-01-15-101010.local</string>
+			<key>SnapshotXID</key>
+			<integer>123456</integer>
+		</dict>
+		<dict>
+			<key>Purgeable</key>
+			<false/>
+			<key>SizeInBytes</key>
+			<integer>2048</integer>
+			<key>SnapshotName</key>
+			<string>manual</string>
+		</dict>
+	</array>
+</dict>
+</plist>
+`
+	snaps, err := parseDiskutilSnapshotsPlist(out)
+	if err != nil {
+		t.Fatalf("parseDiskutilSnapshotsPlist() error: %v", err)
+	}
+	if len(snaps) != 2 {
+		t.Fatalf("Expected 2 snapshots, got %+v", snaps)
+	}
+	if !snaps[0].Purgeable || snaps[0].Size != 0 {
+		t.Errorf("XID must not be read as a size: %+v", snaps[0])
+	}
+	if snaps[1].Name != "manual" || snaps[1].Purgeable || snaps[1].Size != 2048 {
+		t.Errorf("Unexpected second snapshot: %+v", snaps[1])
+	}
+
+	if _, err := parseDiskutilSnapshotsPlist("Snapshots for disk3s5 (0 found)"); err == nil {
+		t.Error("Text output should not parse as a plist")
+	}
+}
+
+func TestParseMountedSnapshots(t *testing.T) {
+	out := `/dev/disk3s1s1 on / (apfs, sealed, local, read-only, journaled)
+com.apple.TimeMachine.2024-01-15-101010.local@/dev/disk3s5 on /Volumes/com.apple.TimeMachine.localsnapshots/Backups.backupdb (apfs, local, read-only)
+`
+	names := parseMountedSnapshots(out)
+	if len(names) != 1 || names[0] != "com.apple.TimeMachine.2024-01-15-101010.local" {
+		t.Errorf("parseMountedSnapshots() = %v", names)
+	}
+}
+
+func TestScanMailData_RebuildableIndex(t *testing.T) {
+	home := t.TempDir()
+	mailData := filepath.Join(home, "Library", "Mail", "V10", "MailData")
+	if err := os.MkdirAll(mailData, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for name, size := range map[string]int{"Envelope Index": 8192, "Envelope Index-wal": 4096} {
+		if err := os.WriteFile(filepath.Join(mailData, name), make([]byte, size), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	s := NewSystemDataScanner()
+	s.scanMailData(home)
+
+	var rebuildable int64
+	for _, item := range s.GetResults() {
+		if item.Name == "Mail Data" {
+			if item.CanClean {
+				t.Errorf("Mail Data itself must not be cleanable")
+			}
+			continue
+		}
+		if !item.CanClean || item.Description == "" {
+			t.Errorf("Rebuildable index should be cleanable with a description: %+v", item)
+		}
+		rebuildable += item.Size
+	}
+	if rebuildable != 8192+4096 {
+		t.Errorf("Rebuildable size = %d, want %d", rebuildable, 8192+4096)
+	}
+}
+
+func TestSystemDataScanner_ScanIsRepeatable(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("SUDO_USER", "")
+
+	for _, dir := range []string{"Library/Mail/V10/MailData", "Library/Containers/com.example.app/Data"} {
+		path := filepath.Join(home, dir)
+		if err := os.MkdirAll(path, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(path, "blob"), make([]byte, 64*1024), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	s := NewSystemDataScanner()
+	first, _ := s.Scan()
+	if len(first) == 0 {
+		t.Fatal("Expected the Mail and container data to be found")
+	}
+	names := make([]string, len(first))
+	for i, item := range first {
+		names[i] = item.Name
+	}
+
+	second, _ := s.Scan()
+	if len(second) != len(names) {
+		t.Fatalf("Second scan returned %d items, want %d (results must not accumulate)", len(second), len(names))
+	}
+	for i, item := range second {
+		if item.Name != names[i] {
+			t.Errorf("Item %d = %q, want %q: order should not depend on scheduling", i, item.Name, names[i])
+		}
+	}
+}
+
+func TestSystemDataScanner_DirSizeRecordsErrors(t *testing.T) {
+	s := NewSystemDataScanner()
+
+	dir := t.TempDir()
+	if size := s.dirSize(dir); size < 0 {
+		t.Fatalf("dirSize(%s) = %d", dir, size)
+	}
+	if len(s.errors) != 0 {
+		t.Errorf("Readable directory should not record errors: %v", s.errors)
+	}
+
+	missing := filepath.Join(dir, "missing")
+	if size := s.dirSize(missing); size != -1 {
+		t.Errorf("dirSize of a missing path = %d, want -1", size)
+	}
+	if len(s.errors) != 1 || !strings.HasPrefix(s.errors[0], missing+": ") {
+		t.Errorf("Expected one error naming %s, got %v", missing, s.errors)
+	}
+}
+
+func TestSystemDataScanner_ScanContextCanceled(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("SUDO_USER", "")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	items, err := NewSystemDataScanner().ScanContext(ctx)
+	if !errors.Is(err, context.Canceled) || items != nil {
+		t.Errorf("ScanContext() = %d items, %v; want none, context.Canceled", len(items), err)
+	}
+}
@@ -5,8 +5,8 @@ import (
 	"os"
 	"os/exec"
 	"os/user"
+	"path/filepath"
 	"strings"
-	"syscall"
 	"time"
 )
 
@@ -21,18 +21,9 @@ func GetRealHomeDir() string {
 		if err == nil && u.HomeDir != "" {
 			return u.HomeDir
 		}
-		// Fallback: try dscl on macOS
-		out, err := exec.Command("dscl", ".", "-read", "/Users/"+sudoUser, "NFSHomeDirectory").Output()
-		if err == nil {
-			for _, line := range strings.Split(string(out), "\n") {
-				line = strings.TrimSpace(line)
-				if strings.HasPrefix(line, "NFSHomeDirectory:") {
-					dir := strings.TrimSpace(strings.TrimPrefix(line, "NFSHomeDirectory:"))
-					if dir != "" {
-						return dir
-					}
-				}
-			}
+		// Fallback: ask the platform (Directory Services on macOS)
+		if dir := CurrentPlatform.LookupHomeDir(sudoUser); dir != "" {
+			return dir
 		}
 	}
 	// Default behavior
@@ -57,12 +48,23 @@ func HasFullDiskAccess() bool {
 
 // GetFileKey gets the unique file identifier (used for detecting hard links)
 func GetFileKey(info os.FileInfo) string {
-	if sys, ok := info.Sys().(*syscall.Stat_t); ok {
-		return fmt.Sprintf("%d:%d", sys.Dev, sys.Ino)
+	if dev, ino, _, ok := CurrentPlatform.FileID(info); ok {
+		return fmt.Sprintf("%d:%d", dev, ino)
 	}
 	return info.Name()
 }
 
+// FindRoot makes path safe to pass to find as a starting point. find reads
+// an argument starting with a dash as part of the expression, and unlike du
+// it has no "--" that works for this on both BSD and GNU, so such a path
+// gets a "./" prefix instead.
+func FindRoot(path string) string {
+	if strings.HasPrefix(path, "-") {
+		return "./" + path
+	}
+	return path
+}
+
 // RiskLevel represents the risk level
 type RiskLevel int
 
@@ -120,6 +122,47 @@ type ScanTarget struct {
 	FileCount int
 	Selected  bool
 	Files     []FileInfo // File list (for preview)
+	Exclude   []string   // Nested targets, measured and cleaned on their own
+	Locked    bool       // Locked by the user: never selected, see SetTargetLocked
+
+	// SizeUnknown is set when du was denied access, usually for want of
+	// Full Disk Access: Size is 0, not an estimate, and it is never selected
+	SizeUnknown bool
+
+	// With an age filter, only files last modified before OlderThan are
+	// cleaned; StaleSize and StaleFiles describe them. Zero means the whole
+	// target.
+	OlderThan  time.Time
+	StaleSize  int64
+	StaleFiles int
+}
+
+// Reclaimable is what cleaning the target frees: its stale part when an age
+// filter applies, otherwise all of it
+func (t ScanTarget) Reclaimable() int64 {
+	if !t.OlderThan.IsZero() {
+		return t.StaleSize
+	}
+	return t.Size
+}
+
+// regenerableTargets are low-risk targets not named as caches that their
+// owner still rebuilds on demand
+var regenerableTargets = map[string]bool{
+	"Xcode DerivedData":     true,
+	"Xcode Products":        true,
+	"Gradle Wrapper":        true,
+	"Swift Package Manager": true,
+}
+
+// Regenerable reports whether the target is a low-risk cache or build
+// output that its app or tool recreates on its own, so cleaning it costs
+// at most a slower first run. Smart select picks exactly these.
+func (t ScanTarget) Regenerable() bool {
+	if t.RiskLevel != RiskLow {
+		return false
+	}
+	return regenerableTargets[t.Name] || strings.HasSuffix(t.Name, " Cache") || strings.HasSuffix(t.Name, " Caches")
 }
 
 // FileInfo represents file information
@@ -128,6 +171,7 @@ type FileInfo struct {
 	Name     string
 	Size     int64
 	Modified time.Time
+	Links    int // hard links to the file; 0 when not known
 }
 
 // DuplicateGroup represents a group of duplicate files
@@ -135,6 +179,85 @@ type DuplicateGroup struct {
 	Hash  string
 	Size  int64
 	Files []FileInfo
+	Keep  string // path of the copy to keep; empty leaves it to the newest/oldest strategy
+}
+
+// Reclaimable is what cleaning the group can free. A copy with other hard
+// links frees nothing when deleted, since the data stays reachable through
+// the remaining links, so only singly-linked copies other than the kept one
+// count. That is the copy Keep names, or else one with other links when
+// there is one, which every KeepPolicy prefers.
+func (g DuplicateGroup) Reclaimable() int64 {
+	if len(g.Files) < 2 {
+		return 0
+	}
+	single, keepSingle := 0, false
+	for _, f := range g.Files {
+		if f.Links <= 1 {
+			single++
+			if g.Keep != "" && f.Path == g.Keep {
+				keepSingle = true
+			}
+		}
+	}
+	if keepSingle {
+		return int64(single-1) * g.Size
+	}
+	return int64(min(single, len(g.Files)-1)) * g.Size
+}
+
+// KeepPolicy decides which copy of a duplicate group cleaning keeps when
+// the group has no Keep of its own. Rules apply in order: a copy with other
+// hard links wins, as deleting it would free nothing, then a copy under an
+// earlier PreferPaths folder, then (with ShortestPath) the shorter path,
+// then the newest or oldest modification time.
+type KeepPolicy struct {
+	Newest       bool
+	PreferPaths  []string // absolute folders, most preferred first
+	ShortestPath bool
+}
+
+// preferRank is the index of the first PreferPaths folder holding path, or
+// len(PreferPaths) when none does
+func (p KeepPolicy) preferRank(path string) int {
+	for i, dir := range p.PreferPaths {
+		if strings.HasPrefix(path, strings.TrimSuffix(dir, "/")+"/") {
+			return i
+		}
+	}
+	return len(p.PreferPaths)
+}
+
+// better reports whether a should be kept over b
+func (p KeepPolicy) better(a, b FileInfo) bool {
+	if la, lb := a.Links > 1, b.Links > 1; la != lb {
+		return la
+	}
+	if ra, rb := p.preferRank(a.Path), p.preferRank(b.Path); ra != rb {
+		return ra < rb
+	}
+	if p.ShortestPath && len(a.Path) != len(b.Path) {
+		return len(a.Path) < len(b.Path)
+	}
+	if p.Newest {
+		return a.Modified.After(b.Modified)
+	}
+	return a.Modified.Before(b.Modified)
+}
+
+// KeepIndex returns the index in Files of the copy cleaning keeps: the one
+// named by Keep if it is in the group, otherwise the one policy prefers
+func (g DuplicateGroup) KeepIndex(policy KeepPolicy) int {
+	keep := -1
+	for i, f := range g.Files {
+		if g.Keep != "" && f.Path == g.Keep {
+			return i
+		}
+		if keep < 0 || policy.better(f, g.Files[keep]) {
+			keep = i
+		}
+	}
+	return keep
 }
 
 // AppInfo represents application information
@@ -143,7 +266,10 @@ type AppInfo struct {
 	Path        string
 	Size        int64
 	InstallDate time.Time
+	LastUsed    time.Time // last opened per Spotlight; zero if never or unknown
+	UsageKnown  bool      // Spotlight answered, so a zero LastUsed means never opened
 	Version     string
+	BundleID    string         // CFBundleIdentifier, "" if unreadable
 	Residuals   []ResidualInfo // Residual files
 }
 
@@ -153,6 +279,13 @@ type ResidualInfo struct {
 	Size int64
 }
 
+// IsLaunchItem reports whether the residual is a launch agent or daemon
+// plist, which launchd keeps trying to start until it is unloaded
+func (r ResidualInfo) IsLaunchItem() bool {
+	dir := filepath.Base(filepath.Dir(r.Path))
+	return (dir == "LaunchAgents" || dir == "LaunchDaemons") && strings.HasSuffix(r.Path, ".plist")
+}
+
 // BrowserData represents browser data
 type BrowserData struct {
 	Name     string
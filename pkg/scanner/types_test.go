@@ -123,6 +123,101 @@ func TestDuplicateGroup(t *testing.T) {
 	}
 }
 
+func TestDuplicateGroup_Reclaimable(t *testing.T) {
+	tests := []struct {
+		name  string
+		links []int
+		want  int64
+	}{
+		{"plain copies", []int{1, 1, 1}, 2000},
+		{"links unknown", []int{0, 0}, 1000},
+		{"one copy linked elsewhere", []int{2, 1, 1}, 2000},
+		{"only one unlinked copy", []int{3, 2, 1}, 1000},
+		{"every copy linked elsewhere", []int{2, 2}, 0},
+	}
+	for _, tt := range tests {
+		group := DuplicateGroup{Size: 1000}
+		for _, n := range tt.links {
+			group.Files = append(group.Files, FileInfo{Size: 1000, Links: n})
+		}
+		if got := group.Reclaimable(); got != tt.want {
+			t.Errorf("%s: Reclaimable() = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+
+	// Keeping an unlinked copy by hand leaves only the other unlinked ones
+	group := DuplicateGroup{Size: 1000, Keep: "/docs/b", Files: []FileInfo{
+		{Path: "/backup/a", Size: 1000, Links: 2},
+		{Path: "/docs/b", Size: 1000, Links: 1},
+		{Path: "/docs/c", Size: 1000, Links: 1},
+	}}
+	if got := group.Reclaimable(); got != 1000 {
+		t.Errorf("Kept unlinked copy: Reclaimable() = %d, want 1000", got)
+	}
+	group.Keep = ""
+	if keep := group.KeepIndex(KeepPolicy{PreferPaths: []string{"/docs"}}); keep != 0 {
+		t.Errorf("The policy should keep the linked copy, got index %d", keep)
+	}
+}
+
+func TestDuplicateGroup_KeepIndex(t *testing.T) {
+	now := time.Now()
+	group := DuplicateGroup{
+		Files: []FileInfo{
+			{Path: "/Downloads/a.jpg", Modified: now.Add(-time.Hour)},
+			{Path: "/Documents/a.jpg", Modified: now.Add(-2 * time.Hour)},
+			{Path: "/Desktop/a.jpg", Modified: now},
+		},
+	}
+
+	if got := group.KeepIndex(KeepPolicy{Newest: true}); got != 2 {
+		t.Errorf("KeepIndex(newest) = %d, want 2", got)
+	}
+	if got := group.KeepIndex(KeepPolicy{}); got != 1 {
+		t.Errorf("KeepIndex(oldest) = %d, want 1", got)
+	}
+
+	group.Keep = "/Downloads/a.jpg"
+	if got := group.KeepIndex(KeepPolicy{Newest: true}); got != 0 {
+		t.Errorf("KeepIndex with Keep set = %d, want 0", got)
+	}
+
+	// A pick that is no longer in the group falls back to the strategy
+	group.Keep = "/gone/a.jpg"
+	if got := group.KeepIndex(KeepPolicy{Newest: true}); got != 2 {
+		t.Errorf("KeepIndex with stale Keep = %d, want 2", got)
+	}
+}
+
+func TestDuplicateGroup_KeepIndexPolicy(t *testing.T) {
+	now := time.Now()
+	group := DuplicateGroup{
+		Files: []FileInfo{
+			{Path: "/Users/me/Downloads/IMG_1.jpg", Modified: now},
+			{Path: "/Users/me/Documents/Trips/2023/IMG_1.jpg", Modified: now.Add(-time.Hour)},
+			{Path: "/Users/me/Pictures/IMG_1.jpg", Modified: now.Add(-2 * time.Hour)},
+		},
+	}
+
+	tests := []struct {
+		name   string
+		policy KeepPolicy
+		want   int
+	}{
+		{"newest", KeepPolicy{Newest: true}, 0},
+		{"preferred folder beats newest", KeepPolicy{Newest: true, PreferPaths: []string{"/Users/me/Documents"}}, 1},
+		{"earlier folder wins", KeepPolicy{PreferPaths: []string{"/Users/me/Pictures/", "/Users/me/Documents"}}, 2},
+		{"unmatched folders fall through", KeepPolicy{Newest: true, PreferPaths: []string{"/Volumes/Backup"}}, 0},
+		{"shortest path", KeepPolicy{Newest: true, ShortestPath: true}, 2},
+		{"folder before shortest", KeepPolicy{PreferPaths: []string{"/Users/me/Documents"}, ShortestPath: true}, 1},
+	}
+	for _, tt := range tests {
+		if got := group.KeepIndex(tt.policy); got != tt.want {
+			t.Errorf("%s: KeepIndex() = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
 func TestAppInfo_Residuals(t *testing.T) {
 	app := AppInfo{
 		Name:    "TestApp",
@@ -147,3 +242,57 @@ func TestAppInfo_Residuals(t *testing.T) {
 		t.Errorf("Expected total residual size 300, got %d", totalResidualSize)
 	}
 }
+
+func TestResidualInfo_IsLaunchItem(t *testing.T) {
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"/Users/me/Library/LaunchAgents/com.example.agent.plist", true},
+		{"/Library/LaunchDaemons/com.example.helper.plist", true},
+		{"/Library/LaunchDaemons/com.example.helper", false},
+		{"/Users/me/Library/Preferences/com.example.plist", false},
+	}
+	for _, tt := range tests {
+		if got := (ResidualInfo{Path: tt.path}).IsLaunchItem(); got != tt.want {
+			t.Errorf("IsLaunchItem(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestFindRoot(t *testing.T) {
+	tests := []struct {
+		path     string
+		expected string
+	}{
+		{"/Users/me", "/Users/me"},
+		{"-name", "./-name"},
+		{"dir/-x", "dir/-x"},
+	}
+
+	for _, tt := range tests {
+		if got := FindRoot(tt.path); got != tt.expected {
+			t.Errorf("FindRoot(%q) = %q, want %q", tt.path, got, tt.expected)
+		}
+	}
+}
+
+func TestScanTarget_Regenerable(t *testing.T) {
+	tests := []struct {
+		target   ScanTarget
+		expected bool
+	}{
+		{ScanTarget{Name: "npm Cache", RiskLevel: RiskLow}, true},
+		{ScanTarget{Name: "App Caches", RiskLevel: RiskLow}, true},
+		{ScanTarget{Name: "Xcode DerivedData", RiskLevel: RiskLow}, true},
+		{ScanTarget{Name: "virtualenv Cache", RiskLevel: RiskMedium}, false},
+		{ScanTarget{Name: "Trash", RiskLevel: RiskLow}, false},
+		{ScanTarget{Name: "Saved Application State", RiskLevel: RiskLow}, false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.target.Regenerable(); got != tt.expected {
+			t.Errorf("%s Regenerable() = %v, want %v", tt.target.Name, got, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package scanner
+
+import (
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// dataVolume is where the startup disk's user data lives on macOS; "/" is
+// the sealed system snapshot and says little about free space
+const dataVolume = "/System/Volumes/Data"
+
+// Volume is one mounted disk as reported by df
+type Volume struct {
+	Name       string // display name, e.g. "Startup disk" or "External"
+	MountPoint string
+	Total      uint64 // bytes
+	Used       uint64
+}
+
+// ListVolumes returns the mounted disks worth showing, the startup disk
+// first and the rest by mount point
+func ListVolumes() ([]Volume, error) {
+	out, err := exec.Command("df", "-kP").Output()
+	if err != nil {
+		return nil, err
+	}
+	return parseDfVolumes(string(out)), nil
+}
+
+// parseDfVolumes reads `df -kP` output. Only device-backed file systems are
+// kept, and of the APFS volumes under /System/Volumes only Data, which then
+// stands in for "/" as the startup disk.
+func parseDfVolumes(output string) []Volume {
+	var mounted []Volume
+	hasData := false
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		// The capacity column ends in %; everything after it is the mount
+		// point and everything before the three numbers the file system,
+		// either of which may contain spaces
+		capIdx := -1
+		for i := 4; i < len(fields); i++ {
+			if strings.HasSuffix(fields[i], "%") {
+				capIdx = i
+				break
+			}
+		}
+		if capIdx < 0 || capIdx+1 >= len(fields) {
+			continue
+		}
+		fs := strings.Join(fields[:capIdx-3], " ")
+		total, err1 := strconv.ParseUint(fields[capIdx-3], 10, 64)
+		used, err2 := strconv.ParseUint(fields[capIdx-2], 10, 64)
+		if err1 != nil || err2 != nil || total == 0 || !strings.HasPrefix(fs, "/dev/") {
+			continue
+		}
+		v := Volume{MountPoint: strings.Join(fields[capIdx+1:], " "), Total: total * 1024, Used: used * 1024}
+		hasData = hasData || v.MountPoint == dataVolume
+		mounted = append(mounted, v)
+	}
+
+	var volumes, others []Volume
+	for _, v := range mounted {
+		switch {
+		case v.MountPoint == dataVolume || (v.MountPoint == "/" && !hasData):
+			v.Name = "Startup disk"
+			volumes = append(volumes, v)
+		case v.MountPoint == "/" || strings.HasPrefix(v.MountPoint, "/System/Volumes/"):
+			continue
+		default:
+			v.Name = filepath.Base(v.MountPoint)
+			others = append(others, v)
+		}
+	}
+	sort.Slice(others, func(i, j int) bool {
+		return others[i].MountPoint < others[j].MountPoint
+	})
+	return append(volumes, others...)
+}
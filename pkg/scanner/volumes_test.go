@@ -0,0 +1,49 @@
+package scanner
+
+import "testing"
+
+func TestParseDfVolumes_MacOS(t *testing.T) {
+	output := `Filesystem     1024-blocks      Used Available Capacity  Mounted on
+/dev/disk3s1s1   482797652  10245344 250000000     4%    /
+devfs                  205       205         0   100%    /dev
+/dev/disk3s6     482797652   4194324 250000000     2%    /System/Volumes/VM
+/dev/disk3s2     482797652   6291456 250000000     3%    /System/Volumes/Preboot
+/dev/disk3s5     482797652 210000000 250000000    46%    /System/Volumes/Data
+map auto_home            0         0         0   100%    /System/Volumes/Data/home
+/dev/disk5s1     976762584 500000000 476762584    52%    /Volumes/Backup Drive
+`
+	volumes := parseDfVolumes(output)
+	if len(volumes) != 2 {
+		t.Fatalf("parseDfVolumes() returned %d volumes, want 2: %+v", len(volumes), volumes)
+	}
+
+	startup := volumes[0]
+	if startup.Name != "Startup disk" || startup.MountPoint != "/System/Volumes/Data" {
+		t.Errorf("startup = %+v, want the Data volume", startup)
+	}
+	if startup.Total != 482797652*1024 || startup.Used != 210000000*1024 {
+		t.Errorf("startup sizes = %d/%d", startup.Used, startup.Total)
+	}
+
+	if ext := volumes[1]; ext.Name != "Backup Drive" || ext.MountPoint != "/Volumes/Backup Drive" {
+		t.Errorf("external = %+v, want Backup Drive", ext)
+	}
+}
+
+func TestParseDfVolumes_Linux(t *testing.T) {
+	output := `Filesystem     1024-blocks     Used Available Capacity Mounted on
+tmpfs              6158152        0   6158152       0% /dev/shm
+/dev/vda         264212084 18771352  82676388      19% /
+/dev/sdb1        100000000 50000000  50000000      50% /mnt/data
+`
+	volumes := parseDfVolumes(output)
+	if len(volumes) != 2 {
+		t.Fatalf("parseDfVolumes() returned %d volumes, want 2: %+v", len(volumes), volumes)
+	}
+	if volumes[0].MountPoint != "/" || volumes[0].Name != "Startup disk" {
+		t.Errorf("volumes[0] = %+v, want / as the startup disk", volumes[0])
+	}
+	if volumes[1].Name != "data" {
+		t.Errorf("volumes[1].Name = %q, want data", volumes[1].Name)
+	}
+}
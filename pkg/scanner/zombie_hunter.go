@@ -1,7 +1,10 @@
 package scanner
 
 import (
+	"context"
+	"encoding/csv"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -81,12 +84,19 @@ type ZombieHunterStats struct {
 type ZombieHunterScanner struct {
 	rootPath     string
 	minSize      int64
+	byModTime    bool
 	errors       []string
 	results      []ZombieFileInfo
 	stats        map[AccessTimeRange]*ZombieHunterStats
 	scanProgress chan<- string
 }
 
+// Thresholds for deciding that access times are not being tracked
+const (
+	atimeMinSample      = 20  // Too few files to judge below this
+	atimeUnreliableFrac = 0.8 // Fraction of files whose atime says nothing
+)
+
 // NewZombieHunterScanner creates a new zombie hunter scanner
 func NewZombieHunterScanner(rootPath string) *ZombieHunterScanner {
 	if rootPath == "" {
@@ -109,9 +119,30 @@ func (s *ZombieHunterScanner) GetErrors() []string {
 	return s.errors
 }
 
-// Scan scans files and categorizes by access time
+// ScanByModTime scans like Scan but ages files by modification time,
+// for volumes where access times are not tracked
+func (s *ZombieHunterScanner) ScanByModTime(progressCh chan<- string) (*ZombieHunterResult, error) {
+	return s.ScanByModTimeContext(context.Background(), progressCh)
+}
+
+// ScanByModTimeContext is ScanByModTime with cancellation, see ScanContext
+func (s *ZombieHunterScanner) ScanByModTimeContext(ctx context.Context, progressCh chan<- string) (*ZombieHunterResult, error) {
+	s.byModTime = true
+	return s.ScanContext(ctx, progressCh)
+}
+
+// Scan scans files and categorizes by access time. If access times turn out
+// to be unreliable (noatime mounts), it falls back to modification time and
+// says so in the result.
 func (s *ZombieHunterScanner) Scan(progressCh chan<- string) (*ZombieHunterResult, error) {
+	return s.ScanContext(context.Background(), progressCh)
+}
+
+// ScanContext is Scan with cancellation: once ctx is done the find and stat
+// processes are killed, remaining files are skipped and ctx.Err() is returned
+func (s *ZombieHunterScanner) ScanContext(ctx context.Context, progressCh chan<- string) (*ZombieHunterResult, error) {
 	s.scanProgress = progressCh
+	s.results = nil
 
 	// Initialize stats
 	for i := RangeRecent7d; i <= RangeZombie; i++ {
@@ -133,7 +164,7 @@ func (s *ZombieHunterScanner) Scan(progressCh chan<- string) (*ZombieHunterResul
 		progressCh <- "Scanning large files..."
 	}
 
-	files, err := s.findLargeFiles()
+	files, err := s.findLargeFiles(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -143,7 +174,19 @@ func (s *ZombieHunterScanner) Scan(progressCh chan<- string) (*ZombieHunterResul
 	}
 
 	// Second pass: get access times and categorize
-	s.categorizeFiles(files, progressCh)
+	s.collectFileInfo(ctx, files, progressCh)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	byModTime := s.byModTime || atimeUnreliable(s.results)
+	for i := range s.results {
+		info := &s.results[i]
+		info.Range = s.determineRange(agingTime(*info, byModTime))
+		if stat, ok := s.stats[info.Range]; ok {
+			stat.Files = append(stat.Files, *info)
+		}
+	}
 
 	// Sort files by size within each range
 	for _, stat := range s.stats {
@@ -157,20 +200,59 @@ func (s *ZombieHunterScanner) Scan(progressCh chan<- string) (*ZombieHunterResul
 	}
 
 	return &ZombieHunterResult{
-		RootPath: s.rootPath,
-		MinSize:  s.minSize,
-		Stats:    s.stats,
-		AllFiles: s.results,
+		RootPath:  s.rootPath,
+		MinSize:   s.minSize,
+		ByModTime: byModTime,
+		Stats:     s.stats,
+		AllFiles:  s.results,
 	}, nil
 }
 
-func (s *ZombieHunterScanner) findLargeFiles() ([]string, error) {
+// agingTime returns the timestamp a file is aged by
+func agingTime(info ZombieFileInfo, byModTime bool) time.Time {
+	if byModTime {
+		return info.ModTime
+	}
+	return info.AccessTime
+}
+
+// atimeUnreliable reports whether access times look untracked: on noatime
+// volumes atime is missing, equal to mtime, or pinned to the mount time, so it
+// collapses onto a single value for most files
+func atimeUnreliable(files []ZombieFileInfo) bool {
+	if len(files) < atimeMinSample {
+		return false
+	}
+
+	useless := 0
+	sameMinute := make(map[int64]int)
+	maxSame := 0
+	for _, f := range files {
+		if f.AccessTime.IsZero() || f.AccessTime.Sub(f.ModTime).Abs() < time.Second {
+			useless++
+			continue
+		}
+		minute := f.AccessTime.Unix() / 60
+		sameMinute[minute]++
+		if sameMinute[minute] > maxSame {
+			maxSame = sameMinute[minute]
+		}
+	}
+
+	threshold := int(float64(len(files)) * atimeUnreliableFrac)
+	return useless >= threshold || useless+maxSame >= threshold
+}
+
+func (s *ZombieHunterScanner) findLargeFiles(ctx context.Context) ([]string, error) {
 	var files []string
 	
 	// Use find to get files larger than minSize
 	// Use stat to get file info including access time
-	cmd := exec.Command("find", s.rootPath, "-type", "f", "-size", fmt.Sprintf("+%dc", s.minSize), "-print0")
+	cmd := ScanCommand(ctx, "find", FindRoot(s.rootPath), "-type", "f", "-size", fmt.Sprintf("+%dc", s.minSize), "-print0")
 	output, err := cmd.Output()
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
 	if err != nil {
 		// Some directories might have permission errors, that's ok
 		if _, ok := err.(*exec.ExitError); ok && len(output) > 0 {
@@ -191,8 +273,9 @@ func (s *ZombieHunterScanner) findLargeFiles() ([]string, error) {
 	return files, nil
 }
 
-func (s *ZombieHunterScanner) categorizeFiles(files []string, progressCh chan<- string) {
-	numWorkers := 8
+// collectFileInfo stats files concurrently into s.results
+func (s *ZombieHunterScanner) collectFileInfo(ctx context.Context, files []string, progressCh chan<- string) {
+	numWorkers := scanWorkers(8)
 	if len(files) < numWorkers {
 		numWorkers = len(files)
 	}
@@ -216,6 +299,9 @@ func (s *ZombieHunterScanner) categorizeFiles(files []string, progressCh chan<-
 		go func() {
 			defer wg.Done()
 			for j := range jobs {
+				if ctx.Err() != nil {
+					continue
+				}
 				info, err := s.getFileInfo(j.path)
 				if err != nil {
 					results <- result{err: err}
@@ -250,13 +336,8 @@ func (s *ZombieHunterScanner) categorizeFiles(files []string, progressCh chan<-
 		if !r.valid {
 			continue
 		}
-		
+
 		s.results = append(s.results, *r.info)
-		rangeType := s.determineRange(r.info.AccessTime)
-		r.info.Range = rangeType
-		if stat, ok := s.stats[rangeType]; ok {
-			stat.Files = append(stat.Files, *r.info)
-		}
 	}
 }
 
@@ -271,8 +352,10 @@ func (s *ZombieHunterScanner) getFileInfo(path string) (*ZombieFileInfo, error)
 		return nil, fmt.Errorf("symlink skipped")
 	}
 
-	// Get access time using stat command for better accuracy
-	accessTime, modTime := s.getTimesFromStat(path)
+	accessTime, modTime := CurrentPlatform.FileTimes(info)
+	if accessTime.IsZero() {
+		accessTime = modTime // No access time on this platform
+	}
 
 	return &ZombieFileInfo{
 		Path:       path,
@@ -283,36 +366,6 @@ func (s *ZombieHunterScanner) getFileInfo(path string) (*ZombieFileInfo, error)
 	}, nil
 }
 
-// getTimesFromStat uses stat command to get access and modification times
-func (s *ZombieHunterScanner) getTimesFromStat(path string) (accessTime, modTime time.Time) {
-	// Default to file info times
-	if info, err := os.Stat(path); err == nil {
-		modTime = info.ModTime()
-		// For access time, try to use stat command
-	}
-
-	// macOS stat command: stat -f "%a %m %N" file
-	// %a = access time (seconds since epoch)
-	// %m = modification time (seconds since epoch)
-	cmd := exec.Command("stat", "-f", "%a %m", path)
-	output, err := cmd.Output()
-	if err != nil {
-		return modTime, modTime // Fallback to modTime
-	}
-
-	fields := strings.Fields(string(output))
-	if len(fields) >= 2 {
-		if at, err := strconv.ParseInt(fields[0], 10, 64); err == nil {
-			accessTime = time.Unix(at, 0)
-		}
-		if mt, err := strconv.ParseInt(fields[1], 10, 64); err == nil {
-			modTime = time.Unix(mt, 0)
-		}
-	}
-
-	return accessTime, modTime
-}
-
 func (s *ZombieHunterScanner) determineRange(accessTime time.Time) AccessTimeRange {
 	if accessTime.IsZero() {
 		return RangeZombie
@@ -336,10 +389,16 @@ func (s *ZombieHunterScanner) determineRange(accessTime time.Time) AccessTimeRan
 
 // ZombieHunterResult holds the scan result
 type ZombieHunterResult struct {
-	RootPath string
-	MinSize  int64
-	Stats    map[AccessTimeRange]*ZombieHunterStats
-	AllFiles []ZombieFileInfo
+	RootPath  string
+	MinSize   int64
+	ByModTime bool // Files were aged by modification time instead of access time
+	Stats     map[AccessTimeRange]*ZombieHunterStats
+	AllFiles  []ZombieFileInfo
+}
+
+// AgeOf returns the timestamp the file was aged by in this result
+func (r *ZombieHunterResult) AgeOf(f ZombieFileInfo) time.Time {
+	return agingTime(f, r.ByModTime)
 }
 
 // GetTotalSize returns total size of all scanned files
@@ -379,6 +438,36 @@ func (r *ZombieHunterResult) GetTopZombies(n int) []ZombieFileInfo {
 	return nil
 }
 
+// WriteCSV writes every scanned file, grouped by range from most recent to
+// zombie and largest first within a range
+func (r *ZombieHunterResult) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"path", "size_bytes", "access_time", "modification_time", "range"})
+
+	for i := RangeRecent7d; i <= RangeZombie; i++ {
+		stat, ok := r.Stats[i]
+		if !ok {
+			continue
+		}
+		for _, f := range stat.Files {
+			accessTime := ""
+			if !f.AccessTime.IsZero() {
+				accessTime = f.AccessTime.Format(time.RFC3339)
+			}
+			cw.Write([]string{
+				f.Path,
+				strconv.FormatInt(f.Size, 10),
+				accessTime,
+				f.ModTime.Format(time.RFC3339),
+				i.String(),
+			})
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
 // GetHeatmapData returns data for heatmap visualization
 func (r *ZombieHunterResult) GetHeatmapData() []struct {
 	Range     AccessTimeRange
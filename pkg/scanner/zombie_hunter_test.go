@@ -0,0 +1,78 @@
+package scanner
+
+import (
+	"bytes"
+	"encoding/csv"
+	"testing"
+	"time"
+)
+
+func TestZombieHunterResult_WriteCSV(t *testing.T) {
+	old := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	result := &ZombieHunterResult{
+		Stats: map[AccessTimeRange]*ZombieHunterStats{
+			RangeRecent7d: {Files: []ZombieFileInfo{
+				{Path: "/tmp/hot, file.bin", Size: 100, AccessTime: time.Now(), ModTime: old},
+			}},
+			RangeZombie: {Files: []ZombieFileInfo{
+				{Path: "/tmp/cold.bin", Size: 200, ModTime: old},
+			}},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := result.WriteCSV(&buf); err != nil {
+		t.Fatalf("WriteCSV() error: %v", err)
+	}
+
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("Output is not valid CSV: %v", err)
+	}
+	if len(rows) != 3 {
+		t.Fatalf("Expected header + 2 rows, got %d", len(rows))
+	}
+	if rows[1][0] != "/tmp/hot, file.bin" || rows[1][4] != RangeRecent7d.String() {
+		t.Errorf("Unexpected first row: %v", rows[1])
+	}
+	zombie := rows[2]
+	if zombie[1] != "200" || zombie[2] != "" || zombie[3] != old.Format(time.RFC3339) || zombie[4] != RangeZombie.String() {
+		t.Errorf("Unexpected zombie row: %v", zombie)
+	}
+}
+
+func TestAtimeUnreliable(t *testing.T) {
+	now := time.Now()
+	makeFiles := func(n int, atime func(i int) time.Time) []ZombieFileInfo {
+		files := make([]ZombieFileInfo, n)
+		for i := range files {
+			mtime := now.Add(-time.Duration(i+1) * 24 * time.Hour)
+			files[i] = ZombieFileInfo{ModTime: mtime, AccessTime: atime(i)}
+		}
+		return files
+	}
+
+	// Real access times, spread out and different from mtime
+	tracked := makeFiles(30, func(i int) time.Time { return now.Add(-time.Duration(i) * time.Hour) })
+	if atimeUnreliable(tracked) {
+		t.Error("Spread-out access times should be treated as reliable")
+	}
+
+	// noatime: atime mirrors mtime
+	mirrored := makeFiles(30, func(i int) time.Time { return now.Add(-time.Duration(i+1) * 24 * time.Hour) })
+	if !atimeUnreliable(mirrored) {
+		t.Error("atime == mtime for every file should be treated as unreliable")
+	}
+
+	// atime pinned to the mount time
+	mount := now.Add(-3 * time.Hour)
+	pinned := makeFiles(30, func(i int) time.Time { return mount })
+	if !atimeUnreliable(pinned) {
+		t.Error("atime pinned to one moment should be treated as unreliable")
+	}
+
+	// Too few files to judge
+	if atimeUnreliable(mirrored[:5]) {
+		t.Error("Small samples should not trigger the fallback")
+	}
+}
@@ -17,10 +17,16 @@ type App struct {
 	duplicates     *DuplicatesView
 	browserData    *BrowserDataView
 	diskTrend      *DiskTrend
+	diskAnalyzer   *DiskAnalyzerView
+	systemData     *SystemDataView
+	themeEditor    *ThemeEditorView
+	quickScan      *QuickScanView
+	trash          *TrashView
 	width          int
 	height         int
 	themeNotif     string // theme switch notification
 	themeNotifTick int    // notification display counter
+	showHelp       bool   // ? key reference open over the current view
 }
 
 // NewApp creates the main application
@@ -35,6 +41,11 @@ func NewApp() *App {
 		duplicates:   NewDuplicatesView(),
 		browserData:  NewBrowserDataView(),
 		diskTrend:    NewDiskTrend(),
+		diskAnalyzer: NewDiskAnalyzerView(),
+		systemData:   NewSystemDataView(),
+		themeEditor:  NewThemeEditorView(),
+		quickScan:    NewQuickScanView(),
+		trash:        NewTrashView(),
 	}
 }
 
@@ -49,8 +60,54 @@ type ThemeChangedMsg struct{}
 // tickMsg is used for the notification timer
 type tickMsg struct{}
 
+// typingText reports whether the current view is taking text, where every
+// key must arrive as typed rather than as a key binding
+func (a *App) typingText() bool {
+	switch a.currentView {
+	case ViewSystemJunk:
+		return a.systemJunk.filtering
+	case ViewDuplicates:
+		return a.duplicates.editingRoot
+	case ViewDiskAnalyzer:
+		return a.diskAnalyzer.editingRoot
+	case ViewSystemData:
+		return a.systemData.deletingSnaps
+	case ViewThemeEditor:
+		return a.themeEditor.editing
+	}
+	return false
+}
+
+// confirmShowing reports whether the current view is asking for y/n, where
+// keys must reach the dialog as pressed
+func (a *App) confirmShowing() bool {
+	switch a.currentView {
+	case ViewSystemJunk:
+		return a.systemJunk.confirming
+	case ViewLargeFiles:
+		return a.largeFiles.confirming
+	case ViewZombieHunter:
+		return a.zombieHunter.confirming
+	case ViewAppUninstaller:
+		return a.appUninstall.confirming
+	case ViewDuplicates:
+		return a.duplicates.confirming
+	case ViewBrowserData:
+		return a.browserData.confirming
+	case ViewSystemData:
+		return a.systemData.confirming
+	case ViewEmptyTrash:
+		return a.trash.confirming
+	}
+	return false
+}
+
 // Update handles state updates
 func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if key, ok := msg.(tea.KeyMsg); ok && !a.typingText() && !a.confirmShowing() {
+		msg = translateKey(key)
+	}
+
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
 		a.width = msg.Width
@@ -70,8 +127,28 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		a.browserData.height = msg.Height
 		a.diskTrend.width = msg.Width
 		a.diskTrend.height = msg.Height
+		a.diskAnalyzer.width = msg.Width
+		a.diskAnalyzer.height = msg.Height
+		a.systemData.width = msg.Width
+		a.systemData.height = msg.Height
+		a.themeEditor.width = msg.Width
+		a.themeEditor.height = msg.Height
+		a.quickScan.width = msg.Width
+		a.quickScan.height = msg.Height
+		a.trash.width = msg.Width
+		a.trash.height = msg.Height
 
 	case tea.KeyMsg:
+		// The key reference closes on any key except ctrl+c, which still quits
+		if a.showHelp && msg.String() != "ctrl+c" {
+			a.showHelp = false
+			return a, nil
+		}
+		if msg.String() == "?" && !a.typingText() {
+			a.showHelp = true
+			return a, nil
+		}
+
 		// Global hotkey: t to switch theme
 		if msg.String() == "t" && a.currentView == ViewMainMenu && GlobalThemeManager != nil {
 			nextTheme := GlobalThemeManager.NextTheme()
@@ -94,9 +171,16 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 	case MenuSelectedMsg:
-		// Menu selection, switch view
+		// Menu selection, switch view. A tool scanning what the health
+		// score is still measuring takes over from the menu's scan.
 		a.currentView = msg.View
 		switch msg.View {
+		case ViewSystemJunk:
+			stopScan(&a.mainMenu.cancelJunk)
+		case ViewZombieHunter:
+			stopScan(&a.mainMenu.cancelZombie)
+		}
+		switch msg.View {
 		case ViewSystemJunk:
 			return a, a.systemJunk.Init()
 		case ViewLargeFiles:
@@ -111,12 +195,37 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return a, a.browserData.Init()
 		case ViewDiskTrend:
 			return a, a.diskTrend.Init()
+		case ViewDiskAnalyzer:
+			return a, a.diskAnalyzer.Init()
+		case ViewSystemData:
+			return a, a.systemData.Init()
+		case ViewThemeEditor:
+			return a, a.themeEditor.Init()
+		case ViewQuickScan:
+			return a, a.quickScan.Init()
+		case ViewEmptyTrash:
+			return a, a.trash.Init()
 		}
 
 	case BackToMenuMsg:
-		// Return to main menu
+		// Return to main menu, refreshing the disk and Trash figures a
+		// cleanup may have changed, and Full Disk Access the user may have
+		// granted meanwhile. The junk figure comes from System Junk's own
+		// scan when it has one; only junk cleaned without one is measured
+		// again.
+		left := a.currentView
 		a.currentView = ViewMainMenu
-		return a, nil
+		if size, ok := a.systemJunk.reclaimable(); ok {
+			a.mainMenu.junkSize = size
+		} else if left == ViewSystemJunk {
+			a.mainMenu.junkSize = -1
+		}
+		return a, tea.Batch(getDiskInfo(), getVolumes(), getTrashUsage(), a.mainMenu.healthScans(), checkFullDiskAccess())
+
+	case junkSizeMsg, zombieSizeMsg, fullDiskAccessMsg:
+		// The health score's scans can finish after another view opened
+		_, cmd := a.mainMenu.Update(msg)
+		return a, cmd
 	}
 
 	// Forward messages to current view
@@ -173,6 +282,41 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			a.diskTrend = updated
 		}
 		return a, cmd
+
+	case ViewDiskAnalyzer:
+		model, cmd := a.diskAnalyzer.Update(msg)
+		if updated, ok := model.(*DiskAnalyzerView); ok {
+			a.diskAnalyzer = updated
+		}
+		return a, cmd
+
+	case ViewSystemData:
+		model, cmd := a.systemData.Update(msg)
+		if updated, ok := model.(*SystemDataView); ok {
+			a.systemData = updated
+		}
+		return a, cmd
+
+	case ViewThemeEditor:
+		model, cmd := a.themeEditor.Update(msg)
+		if updated, ok := model.(*ThemeEditorView); ok {
+			a.themeEditor = updated
+		}
+		return a, cmd
+
+	case ViewQuickScan:
+		model, cmd := a.quickScan.Update(msg)
+		if updated, ok := model.(*QuickScanView); ok {
+			a.quickScan = updated
+		}
+		return a, cmd
+
+	case ViewEmptyTrash:
+		model, cmd := a.trash.Update(msg)
+		if updated, ok := model.(*TrashView); ok {
+			a.trash = updated
+		}
+		return a, cmd
 	}
 
 	return a, nil
@@ -187,6 +331,12 @@ func tickCmd() tea.Cmd {
 
 // View renders the current view
 func (a App) View() string {
+	if a.showHelp {
+		if help := helpOverlay(a.currentView, a.width, a.height); help != "" {
+			return help
+		}
+	}
+
 	var content string
 	switch a.currentView {
 	case ViewMainMenu:
@@ -205,6 +355,16 @@ func (a App) View() string {
 		content = a.browserData.View()
 	case ViewDiskTrend:
 		content = a.diskTrend.View()
+	case ViewDiskAnalyzer:
+		content = a.diskAnalyzer.View()
+	case ViewSystemData:
+		content = a.systemData.View()
+	case ViewThemeEditor:
+		content = a.themeEditor.View()
+	case ViewQuickScan:
+		content = a.quickScan.View()
+	case ViewEmptyTrash:
+		content = a.trash.View()
 	default:
 		content = "Unknown view"
 	}
@@ -2,15 +2,13 @@ package ui
 
 import (
 	"fmt"
-	"os/exec"
-	"path/filepath"
-	"strconv"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
-	"github.com/dustin/go-humanize"
 
 	"github.com/Tyooughtul/lume/pkg/cleaner"
 	"github.com/Tyooughtul/lume/pkg/scanner"
@@ -22,25 +20,67 @@ type AppUninstallerView struct {
 	scrollOffset int
 	scanning     bool
 	uninstalling bool
+	quitArmed    bool
 	confirming   bool
 	showDetail   bool
 	spinner      spinner.Model
 	width        int
 	height       int
 	resultCh     chan appScanResult
+	selected     map[int]bool
 	cleanedSize  int64
+	failures     []string // "App: error" for apps the last batch could not remove
 	err          error
+
+	sortColumn appSortColumn
+
+	// includeResiduals moves the app's leftover data to Trash along with the
+	// bundle. Off until asked for with t: residuals can include launch items.
+	includeResiduals bool
+
+	// fromDetail is set while a confirmation opened from the detail view
+	// shows: it acts on the app shown there, whatever else is checked
+	fromDetail bool
+
+	// running names the apps about to be uninstalled that were running
+	// when the confirmation opened; runningChecking until ps has answered
+	running         []string
+	runningChecking bool
 }
 
+// appSortColumn is the column the app list is sorted by; s cycles through
+// them in order
+type appSortColumn int
+
+const (
+	appSortSize     appSortColumn = iota // largest first
+	appSortName                          // A-Z
+	appSortLastUsed                      // never opened first, then least recently
+)
+
 type appScanResult struct {
 	apps []scanner.AppInfo
 	err  error
 }
 
+// runningAppsMsg carries the apps checkRunningApps found running
+type runningAppsMsg struct {
+	names []string
+}
+
+// checkRunningApps runs ps in the background while the confirmation is
+// already showing
+func checkRunningApps(apps []scanner.AppInfo) tea.Cmd {
+	return func() tea.Msg {
+		return runningAppsMsg{names: scanner.RunningApps(apps)}
+	}
+}
+
 type uninstallResultMsg struct {
-	size    int64
-	err     error
-	appName string
+	size     int64
+	err      error
+	removed  []string // names of apps moved to Trash
+	failures []string
 }
 
 func NewAppUninstallerView() *AppUninstallerView {
@@ -49,8 +89,9 @@ func NewAppUninstallerView() *AppUninstallerView {
 	s.Style = lipgloss.NewStyle().Foreground(PrimaryColor)
 
 	return &AppUninstallerView{
-		spinner:    s,
-		resultCh:   make(chan appScanResult, 1),
+		spinner:  s,
+		resultCh: make(chan appScanResult, 1),
+		selected: make(map[int]bool),
 	}
 }
 
@@ -64,6 +105,7 @@ func (m *AppUninstallerView) Init() tea.Cmd {
 func (m *AppUninstallerView) startScan() tea.Cmd {
 	m.scanning = true
 	m.apps = []scanner.AppInfo{}
+	m.selected = make(map[int]bool)
 
 	go func() {
 		s := scanner.NewAppScanner()
@@ -76,42 +118,6 @@ func (m *AppUninstallerView) startScan() tea.Cmd {
 	}
 }
 
-func (m *AppUninstallerView) scanApps() []scanner.AppInfo {
-	var apps []scanner.AppInfo
-
-	entries, _ := filepath.Glob("/Applications/*.app")
-	for _, path := range entries {
-		name := filepath.Base(path)
-		name = strings.TrimSuffix(name, ".app")
-
-		size := getAppSize(path)
-
-		apps = append(apps, scanner.AppInfo{
-			Name: name,
-			Path: path,
-			Size: size,
-		})
-	}
-
-	return apps
-}
-
-func getAppSize(path string) int64 {
-	cmd := exec.Command("du", "-sk", path)
-	output, err := cmd.Output()
-	if err != nil {
-		return 0
-	}
-
-	fields := strings.Fields(string(output))
-	if len(fields) < 1 {
-		return 0
-	}
-
-	sizeKB, _ := strconv.ParseInt(fields[0], 10, 64)
-	return sizeKB * 1024
-}
-
 func (m *AppUninstallerView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
@@ -121,17 +127,27 @@ func (m *AppUninstallerView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case tea.KeyMsg:
 		if m.confirming {
-			switch msg.String() {
-			case "y", "Y":
-				m.confirming = false
-				return m, m.startUninstall()
-			case "n", "N", "esc":
+			if msg.String() == "t" {
+				m.includeResiduals = !m.includeResiduals
+				return m, nil
+			}
+			if done, confirmed := checkedConfirmKey(msg, m.runningChecking); done {
+				var cmd tea.Cmd
+				if confirmed {
+					cmd = m.startUninstall()
+				}
 				m.confirming = false
+				m.fromDetail = false
+				return m, cmd
 			}
 			return m, nil
 		}
 
-		if m.scanning || m.uninstalling {
+		if m.uninstalling {
+			return m, busyKey(msg, &m.quitArmed)
+		}
+
+		if m.scanning {
 			switch msg.String() {
 			case "q", "ctrl+c":
 				return m, tea.Quit
@@ -145,10 +161,14 @@ func (m *AppUninstallerView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			switch msg.String() {
 			case "esc", "i", "enter":
 				m.showDetail = false
+			case "t":
+				m.includeResiduals = !m.includeResiduals
 			case "d", "u":
 				if len(m.apps) > 0 {
 					m.confirming = true
+					m.fromDetail = true
 					m.showDetail = false
+					return m, m.startRunningCheck()
 				}
 			}
 			return m, nil
@@ -173,18 +193,46 @@ func (m *AppUninstallerView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if len(m.apps) > 0 {
 				m.showDetail = true
 			}
+		case " ":
+			if m.cursor < len(m.apps) {
+				m.selected[m.cursor] = !m.selected[m.cursor]
+			}
+		case "a":
+			allSelected := len(m.apps) > 0
+			for i := range m.apps {
+				if !m.selected[i] {
+					allSelected = false
+					break
+				}
+			}
+			for i := range m.apps {
+				m.selected[i] = !allSelected
+			}
 		case "d", "u":
 			if len(m.apps) > 0 {
 				m.confirming = true
+				m.fromDetail = false
+				return m, m.startRunningCheck()
 			}
+		case "s":
+			m.sortColumn = (m.sortColumn + 1) % 3
+			m.sortApps()
 		case "r":
 			return m, m.startScan()
 		}
 
+	case runningAppsMsg:
+		// A late answer for a dialog already closed is dropped
+		if m.confirming {
+			m.runningChecking = false
+			m.running = msg.names
+		}
+
 	case appScanResult:
 		m.scanning = false
 		m.apps = msg.apps
 		m.err = msg.err
+		m.sortApps()
 		if m.cursor >= len(m.apps) {
 			m.cursor = 0
 		}
@@ -193,8 +241,10 @@ func (m *AppUninstallerView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case uninstallResultMsg:
 		m.uninstalling = false
 		m.err = msg.err
+		m.failures = msg.failures
+		m.cleanedSize = msg.size
 		if msg.size > 0 {
-			details := msg.appName
+			details := strings.Join(msg.removed, ", ")
 			return m, tea.Batch(m.startScan(), RecordSnapshot(0, 0, msg.size, "app_uninstall", details))
 		}
 		return m, m.startScan()
@@ -224,19 +274,107 @@ func (m *AppUninstallerView) updateScrollOffset() {
 	}
 }
 
+// sortApps orders the list by the current sort key, keeping the cursor and
+// the checked apps on the same entries
+func (m *AppUninstallerView) sortApps() {
+	current := ""
+	if m.cursor < len(m.apps) {
+		current = m.apps[m.cursor].Path
+	}
+	checked := make(map[string]bool)
+	for i, app := range m.apps {
+		if m.selected[i] {
+			checked[app.Path] = true
+		}
+	}
+
+	sort.SliceStable(m.apps, func(i, j int) bool {
+		switch m.sortColumn {
+		case appSortName:
+			return strings.ToLower(m.apps[i].Name) < strings.ToLower(m.apps[j].Name)
+		case appSortLastUsed:
+			// Apps Spotlight knows nothing about go last; among the others
+			// a zero time (never opened) sorts before any date
+			if m.apps[i].UsageKnown != m.apps[j].UsageKnown {
+				return m.apps[i].UsageKnown
+			}
+			return m.apps[i].LastUsed.Before(m.apps[j].LastUsed)
+		}
+		return m.apps[i].Size > m.apps[j].Size
+	})
+
+	m.selected = make(map[int]bool)
+	for i, app := range m.apps {
+		if checked[app.Path] {
+			m.selected[i] = true
+		}
+		if app.Path == current {
+			m.cursor = i
+		}
+	}
+	m.updateScrollOffset()
+}
+
+// startRunningCheck clears the last check's result and looks for running
+// copies of the apps about to be confirmed
+func (m *AppUninstallerView) startRunningCheck() tea.Cmd {
+	m.running = nil
+	m.runningChecking = true
+	return checkRunningApps(m.uninstallTargets())
+}
+
+// uninstallTargets returns the checked apps, or the app under the cursor
+// when nothing is checked or the detail view asked
+func (m AppUninstallerView) uninstallTargets() []scanner.AppInfo {
+	if m.fromDetail && m.cursor < len(m.apps) {
+		return []scanner.AppInfo{m.apps[m.cursor]}
+	}
+	var apps []scanner.AppInfo
+	for i, app := range m.apps {
+		if m.selected[i] {
+			apps = append(apps, app)
+		}
+	}
+	if len(apps) == 0 && m.cursor < len(m.apps) {
+		apps = append(apps, m.apps[m.cursor])
+	}
+	return apps
+}
+
+// residualCount returns how many residual locations apps have
+func residualCount(apps []scanner.AppInfo) int {
+	n := 0
+	for _, app := range apps {
+		n += len(app.Residuals)
+	}
+	return n
+}
+
 func (m *AppUninstallerView) startUninstall() tea.Cmd {
 	m.uninstalling = true
+	m.quitArmed = false
+	apps := m.uninstallTargets()
+	includeResiduals := m.includeResiduals
 
 	return func() tea.Msg {
-		c := cleaner.NewCleaner()
-
-		if m.cursor < len(m.apps) {
-			app := m.apps[m.cursor]
-			size, err := c.CleanApp(app, true, nil)
-			return uninstallResultMsg{size: size, err: err, appName: app.Name}
+		if len(apps) == 0 {
+			return uninstallResultMsg{err: fmt.Errorf("no app selected")}
 		}
 
-		return uninstallResultMsg{size: 0, err: fmt.Errorf("no app selected")}
+		c := cleaner.NewCleaner()
+		var msg uninstallResultMsg
+		// One app failing (e.g. it is running or owned by root) must not
+		// stop the rest of the batch
+		for _, app := range apps {
+			size, err := c.CleanApp(app, includeResiduals, nil)
+			msg.size += size
+			if err != nil {
+				msg.failures = append(msg.failures, fmt.Sprintf("%s: %v", app.Name, err))
+				continue
+			}
+			msg.removed = append(msg.removed, app.Name)
+		}
+		return msg
 	}
 }
 
@@ -261,6 +399,7 @@ func (m AppUninstallerView) View() string {
 
 	if m.uninstalling {
 		b.WriteString(fmt.Sprintf("%s Uninstalling...\n", m.spinner.View()))
+		b.WriteString(busyHint(m.quitArmed))
 		return Center(m.width, m.height, b.String())
 	}
 
@@ -269,12 +408,33 @@ func (m AppUninstallerView) View() string {
 		b.WriteString("\n")
 	}
 
+	if m.cleanedSize > 0 {
+		b.WriteString(SuccessStyle.Render(fmt.Sprintf("[ok] Moved %s to Trash", FormatBytes(uint64(m.cleanedSize)))))
+		b.WriteString("\n")
+	}
+	for _, failure := range m.failures {
+		b.WriteString(ErrorStyle.Render("[x] " + failure))
+		b.WriteString("\n")
+	}
+	if m.cleanedSize > 0 || len(m.failures) > 0 {
+		b.WriteString("\n")
+	}
+
 	if len(m.apps) == 0 {
 		b.WriteString("No applications found.\n")
 	} else {
-		b.WriteString(TableHeader([]string{"Application", "Size"}, []int{35, 12}))
+		headers := []string{"  Application", "Size", "Installed", "Last Used"}
+		switch m.sortColumn {
+		case appSortSize:
+			headers[1] += " ↓"
+		case appSortName:
+			headers[0] += " ↑"
+		case appSortLastUsed:
+			headers[3] += " ↑"
+		}
+		b.WriteString(TableHeader(headers, []int{37, 12, 12, 12}))
 		b.WriteString("\n")
-		b.WriteString(Divider(50))
+		b.WriteString(Divider(78))
 		b.WriteString("\n")
 
 		maxDisplay := MaxListItems
@@ -287,11 +447,14 @@ func (m AppUninstallerView) View() string {
 
 		for i := m.scrollOffset; i < m.scrollOffset+maxDisplay && i < len(m.apps); i++ {
 			app := m.apps[i]
+			cb := Checkbox(m.selected[i])
 
-			name := truncate(app.Name, 35)
-			sizeStr := padLeft(humanize.Bytes(uint64(app.Size)), 12)
+			name := padRight(truncate(app.Name, 35), 35)
+			sizeStr := padLeft(FormatBytes(uint64(app.Size)), 12)
+			installed := padLeft(appAge(app.InstallDate, false), 12)
+			lastUsed := padLeft(appAge(app.LastUsed, app.UsageKnown), 12)
 
-			line := fmt.Sprintf("  %s %s", name, sizeStr)
+			line := fmt.Sprintf("  %s %s %s %s %s", cb, name, sizeStr, installed, lastUsed)
 
 			if i == m.cursor {
 				line = SelectedScanItemStyle.Render(line)
@@ -313,35 +476,77 @@ func (m AppUninstallerView) View() string {
 		}
 
 		totalSize := int64(0)
-		for _, app := range m.apps {
+		selectedSize := int64(0)
+		selectedCount := 0
+		for i, app := range m.apps {
 			totalSize += app.Size
+			if m.selected[i] {
+				selectedSize += app.Size
+				selectedCount++
+			}
 		}
 		stats := StatsBar([]string{
-			fmt.Sprintf("Total: %s (%d apps)", humanize.Bytes(uint64(totalSize)), len(m.apps)),
+			fmt.Sprintf("Total: %s (%d apps)", FormatBytes(uint64(totalSize)), len(m.apps)),
+			fmt.Sprintf("Selected: %d (%s)", selectedCount, FormatBytes(uint64(selectedSize))),
 		})
 		b.WriteString(stats)
 	}
 
 	b.WriteString("\n\n")
-	if m.confirming && m.cursor < len(m.apps) {
-		app := m.apps[m.cursor]
-		residualSize := scanner.GetTotalResidualSize(app)
-		totalSize := app.Size + residualSize
+	if apps := m.uninstallTargets(); m.confirming && len(apps) > 0 {
+		totalSize := int64(0)
+		residuals, launchItems := 0, 0
+		for _, app := range apps {
+			totalSize += app.Size
+			for _, r := range app.Residuals {
+				if r.IsLaunchItem() {
+					launchItems++
+				}
+			}
+			if m.includeResiduals {
+				totalSize += scanner.GetTotalResidualSize(app)
+				residuals += len(app.Residuals)
+			}
+		}
+		what := apps[0].Name
+		if len(apps) > 1 {
+			what = fmt.Sprintf("%d apps", len(apps))
+		}
 		residualInfo := ""
-		if len(app.Residuals) > 0 {
-			residualInfo = fmt.Sprintf(" + %d residuals", len(app.Residuals))
+		if residuals > 0 {
+			residualInfo = fmt.Sprintf(" + %d residuals", residuals)
+		} else if !m.includeResiduals {
+			residualInfo = ", keeping residuals"
 		}
-		b.WriteString("  " + WarningStyle.Render(fmt.Sprintf("Uninstall %s (%s%s) to Trash?", app.Name, humanize.Bytes(uint64(totalSize)), residualInfo)))
-		b.WriteString("\n\n")
-		b.WriteString(StyledHelpBar([]KeyHelp{
-			{Key: "y", Desc: "confirm"},
-			{Key: "n/esc", Desc: "cancel"},
-		}))
+		if m.runningChecking {
+			b.WriteString(DimStyle.Render("Checking for running apps... (y waits for it)"))
+			b.WriteString("\n\n")
+		} else if len(m.running) > 0 {
+			b.WriteString(ErrorStyle.Render("! Still running: " + strings.Join(m.running, ", ")))
+			b.WriteString("\n")
+			b.WriteString(WarningStyle.Render("  Quit it first; an app moved to Trash while open stays broken until it is relaunched."))
+			b.WriteString("\n\n")
+		}
+		if residuals > 0 {
+			note := fmt.Sprintf("! Residuals go too: %d locations", residuals)
+			if launchItems > 0 {
+				note += fmt.Sprintf(", %d launch agents or daemons", launchItems)
+			}
+			b.WriteString(WarningStyle.Render(note + " (t keeps them)"))
+			b.WriteString("\n\n")
+		} else if n := residualCount(apps); n > 0 {
+			b.WriteString(DimStyle.Render(fmt.Sprintf("Residuals are kept: %d locations (t removes them too)", n)))
+			b.WriteString("\n\n")
+		}
+		b.WriteString(ConfirmDialog(fmt.Sprintf("Uninstall %s (%s%s) to Trash?", what, FormatBytes(uint64(totalSize)), residualInfo)))
 	} else {
 		b.WriteString(StyledHelpBar([]KeyHelp{
 			{Key: "j/k", Desc: "navigate"},
+			{Key: "space", Desc: "toggle"},
+			{Key: "a", Desc: "all"},
 			{Key: "enter/i", Desc: "info"},
 			{Key: "d", Desc: "uninstall"},
+			{Key: "s", Desc: "sort"},
 			{Key: "r", Desc: "refresh"},
 		}))
 	}
@@ -349,6 +554,29 @@ func (m AppUninstallerView) View() string {
 	return Center(m.width, m.height, b.String())
 }
 
+// appAge describes how long ago t was, e.g. "3w ago". A zero t reads
+// "never" when known says the date was looked up, "-" otherwise.
+func appAge(t time.Time, known bool) string {
+	if t.IsZero() {
+		if known {
+			return "never"
+		}
+		return "-"
+	}
+	days := int(time.Since(t).Hours() / 24)
+	switch {
+	case days <= 0:
+		return "today"
+	case days < 7:
+		return fmt.Sprintf("%dd ago", days)
+	case days < 30:
+		return fmt.Sprintf("%dw ago", days/7)
+	case days < 365:
+		return fmt.Sprintf("%dmo ago", days/30)
+	}
+	return fmt.Sprintf("%dy ago", days/365)
+}
+
 func (m AppUninstallerView) detailView() string {
 	var b strings.Builder
 
@@ -359,27 +587,40 @@ func (m AppUninstallerView) detailView() string {
 		app := m.apps[m.cursor]
 
 		b.WriteString(fmt.Sprintf("  Name: %s\n", app.Name))
-		b.WriteString(fmt.Sprintf("  Path: %s\n", app.Path))
-		b.WriteString(fmt.Sprintf("  Size: %s\n", humanize.Bytes(uint64(app.Size))))
+		b.WriteString(fmt.Sprintf("  Path: %s\n", displayPath(app.Path)))
+		b.WriteString(fmt.Sprintf("  Size: %s\n", FormatBytes(uint64(app.Size))))
 		if app.Version != "" {
 			b.WriteString(fmt.Sprintf("  Version: %s\n", app.Version))
 		}
+		if app.BundleID != "" {
+			b.WriteString(fmt.Sprintf("  Bundle ID: %s\n", app.BundleID))
+		}
+		if !app.InstallDate.IsZero() {
+			b.WriteString(fmt.Sprintf("  Installed: %s\n", app.InstallDate.Format("2006-01-02")))
+		}
+		switch {
+		case !app.LastUsed.IsZero():
+			b.WriteString(fmt.Sprintf("  Last used: %s (%s)\n", app.LastUsed.Format("2006-01-02"), appAge(app.LastUsed, true)))
+		case app.UsageKnown:
+			b.WriteString("  Last used: " + WarningStyle.Render("never opened") + "\n")
+		}
 
 		// Show residual files
 		if len(app.Residuals) > 0 {
 			residualSize := scanner.GetTotalResidualSize(app)
 			b.WriteString("\n")
-			b.WriteString(fmt.Sprintf("  Residual Files (%d locations, %s):\n", len(app.Residuals), humanize.Bytes(uint64(residualSize))))
+			b.WriteString(fmt.Sprintf("  Residual Files (%d locations, %s):\n", len(app.Residuals), FormatBytes(uint64(residualSize))))
 			for i, r := range app.Residuals {
 				if i >= 10 {
 					b.WriteString(fmt.Sprintf("    ... and %d more\n", len(app.Residuals)-10))
 					break
 				}
-				shortPath := r.Path
-				if len(shortPath) > 55 {
-					shortPath = "..." + shortPath[len(shortPath)-52:]
+				shortPath := truncatePathLeft(displayPath(r.Path), max(55, m.width-20))
+				kind := ""
+				if r.IsLaunchItem() {
+					kind = DimStyle.Render(" launch item, unloaded first")
 				}
-				b.WriteString(fmt.Sprintf("    %s (%s)\n", shortPath, humanize.Bytes(uint64(r.Size))))
+				b.WriteString(fmt.Sprintf("    %s (%s)%s\n", shortPath, FormatBytes(uint64(r.Size)), kind))
 			}
 		} else {
 			b.WriteString("\n")
@@ -387,12 +628,18 @@ func (m AppUninstallerView) detailView() string {
 		}
 
 		b.WriteString("\n")
-		b.WriteString("  " + SuccessStyle.Render("[i] App and data will be moved to Trash (recoverable)"))
+		if len(app.Residuals) > 0 && !m.includeResiduals {
+			b.WriteString("  " + WarningStyle.Render("[i] Only the app will be moved to Trash; residual files are kept"))
+		} else {
+			b.WriteString("  " + SuccessStyle.Render("[i] App and data will be moved to Trash (recoverable)"))
+		}
 		b.WriteString("\n\n")
-		b.WriteString(StyledHelpBar([]KeyHelp{
-			{Key: "d/u", Desc: "uninstall"},
-			{Key: "esc", Desc: "back"},
-		}))
+		help := []KeyHelp{{Key: "d/u", Desc: "uninstall"}}
+		if len(app.Residuals) > 0 {
+			help = append(help, KeyHelp{Key: "t", Desc: "toggle residuals"})
+		}
+		help = append(help, KeyHelp{Key: "esc", Desc: "back"})
+		b.WriteString(StyledHelpBar(help))
 	}
 
 	return Center(m.width, m.height, b.String())
@@ -7,7 +7,6 @@ import (
 	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
-	"github.com/dustin/go-humanize"
 
 	"github.com/Tyooughtul/lume/pkg/cleaner"
 	"github.com/Tyooughtul/lume/pkg/scanner"
@@ -19,13 +18,21 @@ type BrowserDataView struct {
 	browserCursor int
 	scanning      bool
 	cleaning      bool
+	quitArmed     bool
 	confirming    bool
 	spinner       spinner.Model
 	width         int
 	height        int
 	resultCh      chan browserScanResult
 	cleanedSize   int64
+	diskBefore    diskReading
+	diskAfter     diskReading
 	err           error
+
+	// Selected item paths with files modified in the last few minutes,
+	// found while the confirmation shows; recentChecking until it returns
+	recentPaths    []string
+	recentChecking bool
 }
 
 type browserScanResult struct {
@@ -76,17 +83,20 @@ func (m *BrowserDataView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case tea.KeyMsg:
 		if m.confirming {
-			switch msg.String() {
-			case "y", "Y":
-				m.confirming = false
-				return m, m.startClean()
-			case "n", "N", "esc":
+			if done, confirmed := checkedConfirmKey(msg, m.recentChecking); done {
 				m.confirming = false
+				if confirmed {
+					return m, m.startClean()
+				}
 			}
 			return m, nil
 		}
 
-		if m.scanning || m.cleaning {
+		if m.cleaning {
+			return m, busyKey(msg, &m.quitArmed)
+		}
+
+		if m.scanning {
 			switch msg.String() {
 			case "q", "ctrl+c":
 				return m, tea.Quit
@@ -100,7 +110,13 @@ func (m *BrowserDataView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "q", "ctrl+c":
 			return m, tea.Quit
 		case "esc":
+			if m.browserCursor >= 0 {
+				m.browserCursor = -1
+				return m, nil
+			}
 			return m, func() tea.Msg { return BackToMenuMsg{} }
+		case "left", "h", "backspace":
+			m.browserCursor = -1
 		case "up", "k":
 			if m.browserCursor >= 0 {
 				if m.browserCursor > 0 {
@@ -135,58 +151,46 @@ func (m *BrowserDataView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					m.browsers[m.cursor].Data[m.browserCursor].Selected = !m.browsers[m.cursor].Data[m.browserCursor].Selected
 				}
 			} else if m.cursor < len(m.browsers) {
-				m.browsers[m.cursor].Selected = !m.browsers[m.cursor].Selected
-				for i := range m.browsers[m.cursor].Data {
-					m.browsers[m.cursor].Data[i].Selected = m.browsers[m.cursor].Selected
-				}
+				toggleBrowser(&m.browsers[m.cursor], !anySelected(m.browsers[m.cursor]))
 			}
 		case "a":
 			if m.browserCursor >= 0 {
 				allSelected := true
 				for _, item := range m.browsers[m.cursor].Data {
-					if !item.Selected {
+					if !item.Selected && item.RiskLevel != scanner.RiskHigh {
 						allSelected = false
 						break
 					}
 				}
-				for i := range m.browsers[m.cursor].Data {
-					m.browsers[m.cursor].Data[i].Selected = !allSelected
-				}
+				selectCaches(&m.browsers[m.cursor], !allSelected)
 			} else {
 				allSelected := true
 				for _, browser := range m.browsers {
-					if !browser.Selected {
+					if !anySelected(browser) {
 						allSelected = false
 						break
 					}
 				}
 				for i := range m.browsers {
-					m.browsers[i].Selected = !allSelected
-					for j := range m.browsers[i].Data {
-						m.browsers[i].Data[j].Selected = !allSelected
-					}
+					toggleBrowser(&m.browsers[i], !allSelected)
 				}
 			}
 		case "r":
 			return m, m.startScan()
 		case "d", "c":
-			hasSelected := false
-			for _, browser := range m.browsers {
-				for _, item := range browser.Data {
-					if item.Selected {
-						hasSelected = true
-						break
-					}
-				}
-				if hasSelected {
-					break
-				}
-			}
-			if hasSelected {
+			if count, _, _ := m.selection(); count > 0 {
 				m.confirming = true
+				return m, m.startRecentCheck()
 			}
 		}
 
+	case recentActivityMsg:
+		// A late answer for a dialog already closed is dropped
+		if m.confirming {
+			m.recentChecking = false
+			m.recentPaths = msg.paths
+		}
+
 	case browserScanResult:
 		m.scanning = false
 		m.browsers = msg.browsers
@@ -199,18 +203,14 @@ func (m *BrowserDataView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.cleaning = false
 		m.err = msg.err
 		if msg.size > 0 {
-			// Count selected browsers
-			browserCount := 0
-			for _, b := range m.browsers {
-				if b.Selected {
-					browserCount++
-				}
-			}
+			browserCount, _, _ := m.selection()
 			details := ""
 			if browserCount > 0 {
 				details = fmt.Sprintf("%d browsers", browserCount)
 			}
-			return m, tea.Batch(m.startScan(), RecordSnapshot(0, 0, msg.size, "browser_data", details))
+			m.cleanedSize = msg.size
+			m.diskBefore, m.diskAfter = msg.before, msg.after
+			return m, tea.Batch(m.startScan(), RecordSnapshot(msg.after.total, msg.after.used, msg.size, "browser_data", details))
 		}
 		return m, m.startScan()
 
@@ -223,13 +223,106 @@ func (m *BrowserDataView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, cmd
 }
 
+// selectCaches sets Selected on a browser's cache items. Cookies, history and
+// local storage are left alone: they log you out and must be picked one by
+// one in the detail list.
+func selectCaches(browser *scanner.BrowserDataInfo, selected bool) {
+	for i := range browser.Data {
+		if browser.Data[i].RiskLevel != scanner.RiskHigh {
+			browser.Data[i].Selected = selected
+		}
+	}
+}
+
+// anySelected reports whether any of a browser's items is selected, which
+// is what its row's checkbox shows: cleaning goes by the items alone
+func anySelected(browser scanner.BrowserDataInfo) bool {
+	for _, item := range browser.Data {
+		if item.Selected {
+			return true
+		}
+	}
+	return false
+}
+
+// toggleBrowser selects a browser's caches, or clears every item of it,
+// cookies and history included
+func toggleBrowser(browser *scanner.BrowserDataInfo, selected bool) {
+	if selected {
+		selectCaches(browser, true)
+		return
+	}
+	for i := range browser.Data {
+		browser.Data[i].Selected = false
+	}
+}
+
+// selection returns how many browsers have selected items, their total size
+// and whether any of them is high risk (cookies, history, local storage)
+func (m *BrowserDataView) selection() (browsers int, size int64, private bool) {
+	for _, browser := range m.browsers {
+		counted := false
+		for _, item := range browser.Data {
+			if !item.Selected {
+				continue
+			}
+			size += item.Size
+			if item.RiskLevel == scanner.RiskHigh {
+				private = true
+			}
+			if !counted {
+				browsers++
+				counted = true
+			}
+		}
+	}
+	return browsers, size, private
+}
+
+// startRecentCheck clears the last check's result and starts a new one for
+// the selected items
+func (m *BrowserDataView) startRecentCheck() tea.Cmd {
+	var paths []string
+	for _, browser := range m.browsers {
+		for _, item := range browser.Data {
+			if item.Selected {
+				paths = append(paths, item.Path)
+			}
+		}
+	}
+	m.recentPaths = nil
+	m.recentChecking = true
+	return checkRecentActivity(paths)
+}
+
+// recentBrowsers names the browsers owning the paths the recent-activity
+// check found, each once
+func (m BrowserDataView) recentBrowsers() []string {
+	inUse := make(map[string]bool)
+	for _, p := range m.recentPaths {
+		inUse[p] = true
+	}
+	var names []string
+	for _, browser := range m.browsers {
+		for _, item := range browser.Data {
+			if item.Selected && inUse[item.Path] {
+				names = append(names, browser.Name)
+				break
+			}
+		}
+	}
+	return names
+}
+
 func (m *BrowserDataView) startClean() tea.Cmd {
 	m.cleaning = true
+	m.quitArmed = false
 
 	return func() tea.Msg {
 		c := cleaner.NewCleaner()
+		before := sampleDisk()
 		size, err := c.CleanBrowserData(m.browsers, nil)
-		return cleanResultMsg{size: size, err: err}
+		return cleanResultMsg{size: size, err: err, before: before, after: sampleDisk()}
 	}
 }
 
@@ -249,6 +342,7 @@ func (m BrowserDataView) View() string {
 
 	if m.cleaning {
 		b.WriteString(fmt.Sprintf("%s Cleaning browser data...\n", m.spinner.View()))
+		b.WriteString(busyHint(m.quitArmed))
 		return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, b.String())
 	}
 
@@ -257,8 +351,18 @@ func (m BrowserDataView) View() string {
 		b.WriteString("\n")
 	}
 
+	if m.cleanedSize > 0 {
+		b.WriteString("  ")
+		b.WriteString(SuccessStyle.Render(fmt.Sprintf("[ok] Cleaned %s", FormatBytes(uint64(m.cleanedSize)))))
+		b.WriteString("\n")
+		b.WriteString(DiskBeforeAfter(m.diskBefore, m.diskAfter, 30))
+		b.WriteString("\n")
+	}
+
 	if len(m.browsers) == 0 {
 		b.WriteString("No browser data found.\n")
+	} else if m.browserCursor >= 0 && m.cursor < len(m.browsers) {
+		b.WriteString(m.renderItems())
 	} else {
 		b.WriteString("  ")
 		b.WriteString(TableHeader([]string{"", "Icon", "Browser", "Size"}, []int{3, 6, 24, 12}))
@@ -268,7 +372,7 @@ func (m BrowserDataView) View() string {
 		b.WriteString("\n")
 
 		for i, browser := range m.browsers {
-			cb := Checkbox(browser.Selected)
+			cb := Checkbox(anySelected(browser))
 
 			icon := padRight(browser.Icon, 6)
 			name := padRight(truncate(browser.Name, 24), 24)
@@ -277,7 +381,7 @@ func (m BrowserDataView) View() string {
 			for _, item := range browser.Data {
 				totalSize += item.Size
 			}
-			sizeStr := padLeft(humanize.Bytes(uint64(totalSize)), 12)
+			sizeStr := padLeft(FormatBytes(uint64(totalSize)), 12)
 
 			line := fmt.Sprintf("  %s %s %s %s", cb, icon, name, sizeStr)
 
@@ -302,8 +406,8 @@ func (m BrowserDataView) View() string {
 		}
 
 		stats := StatsBar([]string{
-			fmt.Sprintf("Total: %s", humanize.Bytes(uint64(totalSize))),
-			fmt.Sprintf("Selected: %s", humanize.Bytes(uint64(selectedSize))),
+			fmt.Sprintf("Total: %s", FormatBytes(uint64(totalSize))),
+			fmt.Sprintf("Selected: %s", FormatBytes(uint64(selectedSize))),
 		})
 		b.WriteString("\n")
 		b.WriteString(stats)
@@ -311,25 +415,20 @@ func (m BrowserDataView) View() string {
 
 	b.WriteString("\n\n")
 	if m.confirming {
-		selectedSize := int64(0)
-		browserCount := 0
-		for _, browser := range m.browsers {
-			hasSel := false
-			for _, item := range browser.Data {
-				if item.Selected {
-					selectedSize += item.Size
-					hasSel = true
-				}
-			}
-			if hasSel {
-				browserCount++
-			}
+		browserCount, selectedSize, private := m.selection()
+		b.WriteString(recentActivityWarning(m.recentBrowsers(), m.recentChecking))
+		if private {
+			b.WriteString(WarningStyle.Render("! Includes cookies, history or site data: you will be signed out of websites"))
+			b.WriteString("\n")
 		}
-		b.WriteString("  " + WarningStyle.Render(fmt.Sprintf("Clean data from %d browsers (%s) to Trash?", browserCount, humanize.Bytes(uint64(selectedSize)))))
-		b.WriteString("\n\n")
+		b.WriteString(ConfirmDialog(fmt.Sprintf("Clean data from %d browsers (%s) to Trash?", browserCount, FormatBytes(uint64(selectedSize)))))
+	} else if m.browserCursor >= 0 {
 		b.WriteString(StyledHelpBar([]KeyHelp{
-			{Key: "y", Desc: "confirm"},
-			{Key: "n/esc", Desc: "cancel"},
+			{Key: "j/k", Desc: "navigate"},
+			{Key: "space", Desc: "toggle"},
+			{Key: "a", Desc: "all caches"},
+			{Key: "d", Desc: "clean"},
+			{Key: "esc", Desc: "back"},
 		}))
 	} else {
 		b.WriteString(StyledHelpBar([]KeyHelp{
@@ -344,3 +443,60 @@ func (m BrowserDataView) View() string {
 
 	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, b.String())
 }
+
+// renderItems lists the data items of the browser under the cursor.
+// High-risk items are flagged since cleaning them logs you out.
+func (m BrowserDataView) renderItems() string {
+	var b strings.Builder
+	browser := m.browsers[m.cursor]
+
+	b.WriteString("  ")
+	b.WriteString(TitleStyle.Render(browser.Name))
+	b.WriteString("\n\n  ")
+	b.WriteString(TableHeader([]string{"", "Data", "Type", "Size"}, []int{3, 36, 14, 10}))
+	b.WriteString("\n  ")
+	b.WriteString(Divider(66))
+	b.WriteString("\n")
+
+	visible := max(m.height-16, 5)
+	start := 0
+	if m.browserCursor >= visible {
+		start = m.browserCursor - visible + 1
+	}
+	end := min(start+visible, len(browser.Data))
+
+	above, below := ScrollIndicator(start, len(browser.Data), end-start)
+	if above != "" {
+		b.WriteString(above)
+		b.WriteString("\n")
+	}
+
+	for i := start; i < end; i++ {
+		item := browser.Data[i]
+		name := padRight(truncate(item.Name, 36), 36)
+		kind := padRight(item.Type, 14)
+		if item.RiskLevel == scanner.RiskHigh {
+			kind = WarningStyle.Render(kind)
+		}
+		size := padLeft(FormatBytes(uint64(item.Size)), 10)
+
+		line := fmt.Sprintf("  %s %s %s %s", Checkbox(item.Selected), name, kind, size)
+		if i == m.browserCursor {
+			line = SelectedScanItemStyle.Render(line)
+		} else {
+			line = ScanItemStyle.Render(line)
+		}
+		b.WriteString(line)
+		b.WriteString("\n")
+	}
+	if below != "" {
+		b.WriteString(below)
+		b.WriteString("\n")
+	}
+
+	if m.browserCursor < len(browser.Data) && browser.Data[m.browserCursor].RiskLevel == scanner.RiskHigh {
+		b.WriteString("\n  ")
+		b.WriteString(WarningStyle.Render("! High risk: signs you out of websites and clears saved site data or history"))
+	}
+	return b.String()
+}
@@ -0,0 +1,114 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/Tyooughtul/lume/pkg/scanner"
+)
+
+// confirmKey interprets a key press while a confirmation is showing.
+// done is true once the user answered; confirmed tells which way.
+// Any other key leaves the dialog open.
+func confirmKey(msg tea.KeyMsg) (done, confirmed bool) {
+	switch msg.String() {
+	case "y", "Y":
+		return true, true
+	case "n", "N", "esc":
+		return true, false
+	}
+	return false, false
+}
+
+// checkedConfirmKey is confirmKey for a dialog that shows the result of a
+// background check, such as recentActivityWarning: y is ignored while the
+// check is still running, so nothing is moved before its warning could show
+func checkedConfirmKey(msg tea.KeyMsg, checking bool) (done, confirmed bool) {
+	done, confirmed = confirmKey(msg)
+	if confirmed && checking {
+		return false, false
+	}
+	return done, confirmed
+}
+
+// ConfirmDialog renders the prompt shown before a destructive action,
+// e.g. "Move 3 files (1.2 GB) to Trash?", followed by its key help
+func ConfirmDialog(question string) string {
+	box := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(WarningColor).
+		Padding(0, 2)
+
+	return box.Render(WarningStyle.Render(question)) + "\n\n" + StyledHelpBar([]KeyHelp{
+		{Key: "y", Desc: "confirm"},
+		{Key: "n/esc", Desc: "cancel"},
+	})
+}
+
+// syncedDataWarning is shown above a confirmation when any of paths lies in
+// iCloud Drive, Mail or a Photos library, whatever their risk level
+func syncedDataWarning(paths []string) string {
+	labels := scanner.SyncedDataLabels(paths)
+	if len(labels) == 0 {
+		return ""
+	}
+	return ErrorStyle.Render("! Includes files in "+strings.Join(labels, ", ")+".") + "\n" +
+		WarningStyle.Render("  They are synced, so trashing them can remove them from your other devices too.") + "\n\n"
+}
+
+// recentActivityMsg carries the paths checkRecentActivity found in use
+type recentActivityMsg struct {
+	paths []string
+}
+
+// checkRecentActivity looks for recent writes under paths in the background,
+// while the confirmation is already showing
+func checkRecentActivity(paths []string) tea.Cmd {
+	return func() tea.Msg {
+		return recentActivityMsg{paths: scanner.RecentlyModified(paths, scanner.RecentActivityWindow)}
+	}
+}
+
+// recentActivityWarning is shown above a confirmation when files in the
+// named items changed within scanner.RecentActivityWindow, a sign that the
+// app owning them is still running. checking shows while the check runs.
+func recentActivityWarning(names []string, checking bool) string {
+	if checking {
+		return DimStyle.Render("Checking for recently modified files... (y waits for it)") + "\n\n"
+	}
+	if len(names) == 0 {
+		return ""
+	}
+	minutes := int(scanner.RecentActivityWindow / time.Minute)
+	return ErrorStyle.Render(fmt.Sprintf("! Modified in the last %d minutes: %s.", minutes, strings.Join(names, ", "))) + "\n" +
+		WarningStyle.Render("  The app using it may still be running; quit it first to avoid losing data.") + "\n\n"
+}
+
+// busyKey handles a key press while a view is moving files to the Trash.
+// Leaving mid-operation could strand a directory half moved, so q and esc
+// only arm a warning; ctrl+c pressed while the warning shows quits anyway.
+func busyKey(msg tea.KeyMsg, quitArmed *bool) tea.Cmd {
+	switch msg.String() {
+	case "ctrl+c":
+		if *quitArmed {
+			return tea.Quit
+		}
+		*quitArmed = true
+	case "q", "esc":
+		*quitArmed = true
+	}
+	return nil
+}
+
+// busyHint is the warning shown once busyKey has been armed
+func busyHint(quitArmed bool) string {
+	if !quitArmed {
+		return ""
+	}
+	return WarningStyle.Render("Still moving files to Trash; leaving now could leave a folder half moved.") +
+		"\n" + DimStyle.Render("Wait for it to finish, or press ctrl+c again to quit anyway.") + "\n"
+}
@@ -0,0 +1,468 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/Tyooughtul/lume/pkg/scanner"
+)
+
+// analyzerDefaultMinSize hides items smaller than this so the tree for a
+// whole home directory stays small
+const analyzerDefaultMinSize = 10 * 1024 * 1024
+
+// analyzerMinSizes are the thresholds +/- step through
+var analyzerMinSizes = []int64{
+	1 * 1024 * 1024,
+	10 * 1024 * 1024,
+	50 * 1024 * 1024,
+	100 * 1024 * 1024,
+	500 * 1024 * 1024,
+	1024 * 1024 * 1024,
+}
+
+// DiskAnalyzerView is an interactive "where did my space go" navigator over
+// the tree built by scanner.DiskAnalyzer
+type DiskAnalyzerView struct {
+	rootPath     string
+	minSize      int64
+	root         *scanner.DiskItem
+	stack        []*scanner.DiskItem // directories entered, root first
+	cursors      []int               // cursor to restore when going back up
+	cursor       int
+	scrollOffset int
+	scanning     bool
+	cancelScan   context.CancelFunc
+	spinner      spinner.Model
+	width        int
+	height       int
+	resultCh     chan analyzerScanResult
+	progressCh   chan string
+	progress     string
+	err          error
+
+	// reopen is the directory path (below the root) to drill back into
+	// after a rescan, so changing the threshold keeps the user's place
+	reopen []string
+
+	// Root input state (g)
+	editingRoot bool
+	rootInput   string
+	rootErr     string
+}
+
+type analyzerScanResult struct {
+	root *scanner.DiskItem
+	err  error
+}
+
+func NewDiskAnalyzerView() *DiskAnalyzerView {
+	s := spinner.New()
+	s.Spinner = spinner.Dot
+	s.Style = lipgloss.NewStyle().Foreground(PrimaryColor)
+
+	return &DiskAnalyzerView{
+		spinner:  s,
+		rootPath: scanner.GetRealHomeDir(),
+		minSize:  analyzerDefaultMinSize,
+		resultCh: make(chan analyzerScanResult, 1),
+	}
+}
+
+func (m *DiskAnalyzerView) Init() tea.Cmd {
+	if m.root != nil {
+		// Keep the tree from the last visit; r rescans
+		return nil
+	}
+	return tea.Batch(
+		m.spinner.Tick,
+		m.startScan(),
+	)
+}
+
+func (m *DiskAnalyzerView) startScan() tea.Cmd {
+	m.scanning = true
+	m.reopen = nil
+	for _, dir := range m.stack {
+		if dir != m.root {
+			m.reopen = append(m.reopen, dir.Path)
+		}
+	}
+	m.root = nil
+	m.stack = nil
+	m.cursors = nil
+	m.cursor = 0
+	m.scrollOffset = 0
+	m.err = nil
+	m.progress = ""
+	m.progressCh = make(chan string, 1)
+
+	root, minSize := m.rootPath, m.minSize
+	progress := progressRelay(m.progressCh)
+	ctx := newScanContext(&m.cancelScan)
+	go func() {
+		da := scanner.NewDiskAnalyzer()
+		da.SetMinSize(minSize)
+		item, err := da.AnalyzePathContext(ctx, root, progress)
+		close(progress)
+		m.resultCh <- analyzerScanResult{root: item, err: err}
+	}()
+
+	return tea.Batch(
+		func() tea.Msg {
+			return <-m.resultCh
+		},
+		waitForProgress(m.progressCh),
+	)
+}
+
+// current returns the directory being listed
+func (m *DiskAnalyzerView) current() *scanner.DiskItem {
+	if len(m.stack) == 0 {
+		return nil
+	}
+	return m.stack[len(m.stack)-1]
+}
+
+// enter descends into the directory under the cursor
+func (m *DiskAnalyzerView) enter() {
+	dir := m.current()
+	if dir == nil || m.cursor >= len(dir.Children) {
+		return
+	}
+	child := &dir.Children[m.cursor]
+	if !child.IsDir || len(child.Children) == 0 {
+		return
+	}
+	m.cursors = append(m.cursors, m.cursor)
+	m.stack = append(m.stack, child)
+	m.cursor = 0
+	m.scrollOffset = 0
+}
+
+// stepMinSize moves the size threshold one step up (dir > 0) or down and
+// reports whether it changed
+func (m *DiskAnalyzerView) stepMinSize(dir int) bool {
+	idx := 0
+	for i, size := range analyzerMinSizes {
+		if size <= m.minSize {
+			idx = i
+		}
+	}
+	idx += dir
+	if idx < 0 || idx >= len(analyzerMinSizes) || analyzerMinSizes[idx] == m.minSize {
+		return false
+	}
+	m.minSize = analyzerMinSizes[idx]
+	return true
+}
+
+// reopenPath drills back into the directories saved in m.reopen that still
+// exist in the new tree
+func (m *DiskAnalyzerView) reopenPath() {
+	for _, path := range m.reopen {
+		dir := m.current()
+		found := false
+		for i := range dir.Children {
+			if dir.Children[i].Path == path {
+				m.cursor = i
+				m.enter()
+				found = true
+				break
+			}
+		}
+		if !found {
+			break
+		}
+	}
+	m.reopen = nil
+}
+
+// leave goes back to the parent directory; it reports false at the root
+func (m *DiskAnalyzerView) leave() bool {
+	if len(m.stack) <= 1 {
+		return false
+	}
+	m.stack = m.stack[:len(m.stack)-1]
+	m.cursor = m.cursors[len(m.cursors)-1]
+	m.cursors = m.cursors[:len(m.cursors)-1]
+	m.scrollOffset = 0
+	m.updateScrollOffset()
+	return true
+}
+
+func (m *DiskAnalyzerView) handleRootKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyCtrlC:
+		return m, tea.Quit
+	case tea.KeyEsc:
+		m.editingRoot = false
+		m.rootErr = ""
+	case tea.KeyEnter:
+		root := expandHome(strings.TrimSpace(m.rootInput))
+		info, err := os.Stat(root)
+		if err != nil {
+			m.rootErr = err.Error()
+			return m, nil
+		}
+		if !info.IsDir() {
+			m.rootErr = "not a directory"
+			return m, nil
+		}
+		m.editingRoot = false
+		m.rootErr = ""
+		m.rootPath = filepath.Clean(root)
+		m.stack = nil
+		return m, m.startScan()
+	case tea.KeyBackspace:
+		if r := []rune(m.rootInput); len(r) > 0 {
+			m.rootInput = string(r[:len(r)-1])
+		}
+	case tea.KeyRunes, tea.KeySpace:
+		m.rootInput += string(msg.Runes)
+	}
+	return m, nil
+}
+
+func (m *DiskAnalyzerView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m.updateScrollOffset()
+
+	case tea.KeyMsg:
+		if m.scanning {
+			switch msg.String() {
+			case "q", "ctrl+c":
+				stopScan(&m.cancelScan)
+				return m, tea.Quit
+			case "esc":
+				stopScan(&m.cancelScan)
+				return m, func() tea.Msg { return BackToMenuMsg{} }
+			}
+			return m, nil
+		}
+
+		if m.editingRoot {
+			return m.handleRootKeys(msg)
+		}
+
+		var count int
+		if dir := m.current(); dir != nil {
+			count = len(dir.Children)
+		}
+
+		switch msg.String() {
+		case "q", "ctrl+c":
+			return m, tea.Quit
+		case "esc", "backspace", "left", "h":
+			if !m.leave() && msg.String() == "esc" {
+				return m, func() tea.Msg { return BackToMenuMsg{} }
+			}
+		case "up", "k":
+			if m.cursor > 0 {
+				m.cursor--
+			}
+			m.updateScrollOffset()
+		case "down", "j":
+			if m.cursor < count-1 {
+				m.cursor++
+			}
+			m.updateScrollOffset()
+		case "enter", "right", "l":
+			m.enter()
+		case "+", "=":
+			if m.stepMinSize(1) {
+				return m, m.startScan()
+			}
+		case "-":
+			if m.stepMinSize(-1) {
+				return m, m.startScan()
+			}
+		case "g":
+			m.editingRoot = true
+			m.rootInput = m.rootPath
+			m.rootErr = ""
+		case "r":
+			return m, m.startScan()
+		}
+
+	case analyzerScanResult:
+		if scanCanceled(msg.err) {
+			// A scan stopped by leaving the view; a newer one may be running
+			return m, nil
+		}
+		m.scanning = false
+		m.err = msg.err
+		m.root = msg.root
+		if m.root != nil {
+			m.stack = []*scanner.DiskItem{m.root}
+			m.reopenPath()
+		}
+
+	case progressMsg:
+		if m.scanning {
+			m.progress = string(msg)
+			return m, waitForProgress(m.progressCh)
+		}
+
+	case BackToMenuMsg:
+		return NewMainMenu(), nil
+	}
+
+	var cmd tea.Cmd
+	m.spinner, cmd = m.spinner.Update(msg)
+	return m, cmd
+}
+
+func (m *DiskAnalyzerView) maxDisplay() int {
+	maxDisplay := MaxListItems
+	if m.height > 20 {
+		maxDisplay = m.height - 14
+	}
+	return maxDisplay
+}
+
+func (m *DiskAnalyzerView) updateScrollOffset() {
+	maxDisplay := m.maxDisplay()
+	if m.cursor < m.scrollOffset {
+		m.scrollOffset = m.cursor
+	}
+	if m.cursor >= m.scrollOffset+maxDisplay {
+		m.scrollOffset = m.cursor - maxDisplay + 1
+	}
+}
+
+func (m DiskAnalyzerView) View() string {
+	if m.width == 0 {
+		return "Loading..."
+	}
+
+	var b strings.Builder
+
+	b.WriteString(PageHeader("", "Disk Analyzer", m.width))
+	b.WriteString("\n")
+	b.WriteString("  ")
+	b.WriteString(DimStyle.Render(fmt.Sprintf("Root: %s (items >= %s)", displayPath(m.rootPath), FormatBytes(uint64(m.minSize)))))
+	b.WriteString("\n\n")
+
+	if m.scanning {
+		b.WriteString(fmt.Sprintf("%s Analyzing...\n", m.spinner.View()))
+		if m.progress != "" {
+			b.WriteString(DimStyle.Render(m.progress))
+			b.WriteString("\n")
+		}
+		return Center(m.width, m.height, b.String())
+	}
+
+	if m.editingRoot {
+		b.WriteString("Folder to analyze:\n\n")
+		b.WriteString(AccentStyle.Render("> "))
+		b.WriteString(m.rootInput + "_")
+		b.WriteString("\n")
+		if m.rootErr != "" {
+			b.WriteString("\n")
+			b.WriteString(ErrorStyle.Render(m.rootErr))
+			b.WriteString("\n")
+		}
+		b.WriteString("\n")
+		b.WriteString(StyledHelpBar([]KeyHelp{
+			{Key: "enter", Desc: "analyze"},
+			{Key: "esc", Desc: "cancel"},
+		}))
+		return Center(m.width, m.height, b.String())
+	}
+
+	if m.err != nil {
+		b.WriteString(ErrorStyle.Render(fmt.Sprintf("Error: %v", m.err)))
+		b.WriteString("\n")
+	}
+
+	if dir := m.current(); dir != nil {
+		b.WriteString("  ")
+		b.WriteString(TitleStyle.Render(displayPath(dir.Path)))
+		b.WriteString("  ")
+		b.WriteString(SubtitleStyle.Render(FormatBytes(uint64(dir.Size))))
+		b.WriteString("\n\n")
+
+		if len(dir.Children) == 0 {
+			b.WriteString(fmt.Sprintf("  No items larger than %s.\n", FormatBytes(uint64(m.minSize))))
+		} else {
+			b.WriteString(TableHeader([]string{"  Name", "Size", "Share"}, []int{40, 12, 28}))
+			b.WriteString("\n")
+			b.WriteString(Divider(82))
+			b.WriteString("\n")
+
+			maxDisplay := m.maxDisplay()
+			for i := m.scrollOffset; i < m.scrollOffset+maxDisplay && i < len(dir.Children); i++ {
+				child := dir.Children[i]
+
+				name := child.Name
+				if child.IsDir {
+					name += "/"
+				}
+				share := 0.0
+				if dir.Size > 0 {
+					share = float64(child.Size) / float64(dir.Size) * 100
+				}
+
+				line := fmt.Sprintf("  %s %s  %s %5.1f%%",
+					padRight(truncate(name, 40), 40),
+					padLeft(FormatBytes(uint64(child.Size)), 12),
+					ProgressBar(share, 20, PrimaryColor, GrayColor),
+					share)
+
+				if i == m.cursor {
+					line = SelectedScanItemStyle.Render(line)
+				} else {
+					line = ScanItemStyle.Render(line)
+				}
+				b.WriteString(line)
+				b.WriteString("\n")
+			}
+
+			above, below := ScrollIndicator(m.scrollOffset, len(dir.Children), maxDisplay)
+			if above != "" {
+				b.WriteString("  ")
+				b.WriteString(above)
+				b.WriteString("\n")
+			}
+			if below != "" {
+				b.WriteString("  ")
+				b.WriteString(below)
+				b.WriteString("\n")
+			}
+
+			// Space held by items below minSize is only known as a total
+			var listed int64
+			for _, child := range dir.Children {
+				listed += child.Size
+			}
+			if rest := dir.Size - listed; rest > 0 {
+				b.WriteString(DimStyle.Render(fmt.Sprintf("  + %s in smaller items", FormatBytes(uint64(rest)))))
+				b.WriteString("\n")
+			}
+		}
+	}
+
+	b.WriteString("\n")
+	b.WriteString(StyledHelpBar([]KeyHelp{
+		{Key: "j/k", Desc: "navigate"},
+		{Key: "enter/l", Desc: "open"},
+		{Key: "esc/h", Desc: "up"},
+		{Key: "+/-", Desc: "min size"},
+		{Key: "g", Desc: "folder"},
+		{Key: "r", Desc: "rescan"},
+		{Key: "q", Desc: "quit"},
+	}))
+
+	return Center(m.width, m.height, b.String())
+}
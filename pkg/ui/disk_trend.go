@@ -2,12 +2,14 @@ package ui
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
-	"github.com/dustin/go-humanize"
 	"github.com/Tyooughtul/lume/pkg/scanner"
 )
 
@@ -18,24 +20,45 @@ type DiskTrend struct {
 	snapshots     []scanner.DiskSnapshot
 	trendData     *scanner.TrendData
 	stats         *scanner.HistoryStatistics
+	categories    map[string]int64
 	selectedRange int
 	ranges        []string
+	rangeDays     []int // days covered by each entry of ranges
 	loading       bool
 	err           error
 	cursor        int // For scrolling log
+	exportPath    string
+	exportErr     error
+
+	// Compare mode: pick two log rows to see what changed between them
+	comparing     bool
+	compareCursor int   // log row under the cursor, newest first like the log
+	compareMarks  []int // picked snapshots, as indices into snapshots
 }
 
+// compareMaxEvents is how many cleanups the comparison lists by name
+const compareMaxEvents = 5
+
 type trendLoadedMsg struct {
-	snapshots []scanner.DiskSnapshot
-	trendData *scanner.TrendData
-	stats     *scanner.HistoryStatistics
-	err       error
+	snapshots  []scanner.DiskSnapshot
+	trendData  *scanner.TrendData
+	stats      *scanner.HistoryStatistics
+	categories map[string]int64
+	err        error
 }
 
 func NewDiskTrend() *DiskTrend {
-	return &DiskTrend{
-		ranges: []string{"7 Days", "14 Days", "30 Days", "90 Days"},
+	days := []int{7, 14, 30, 90}
+	// A longer history (historyDays in the config) gets a range of its own
+	if retention := scanner.LoadConfig().HistoryRetentionDays(); retention > days[len(days)-1] {
+		days = append(days, retention)
+	}
+
+	d := &DiskTrend{rangeDays: days}
+	for _, n := range days {
+		d.ranges = append(d.ranges, fmt.Sprintf("%d Days", n))
 	}
+	return d
 }
 
 func (d *DiskTrend) Init() tea.Cmd {
@@ -50,7 +73,7 @@ func (d *DiskTrend) loadTrendData() tea.Cmd {
 			return trendLoadedMsg{err: err}
 		}
 
-		days := []int{7, 14, 30, 90}[min(d.selectedRange, 3)]
+		days := d.rangeDays[min(d.selectedRange, len(d.rangeDays)-1)]
 
 		snapshots, err := hm.GetRecentSnapshots(days)
 		if err != nil {
@@ -67,10 +90,16 @@ func (d *DiskTrend) loadTrendData() tea.Cmd {
 			return trendLoadedMsg{err: err}
 		}
 
+		categories, err := hm.GetCategoryTotals(days)
+		if err != nil {
+			return trendLoadedMsg{err: err}
+		}
+
 		return trendLoadedMsg{
-			snapshots: snapshots,
-			trendData: trendData,
-			stats:     stats,
+			snapshots:  snapshots,
+			trendData:  trendData,
+			stats:      stats,
+			categories: categories,
 		}
 	}
 }
@@ -89,9 +118,18 @@ func (d *DiskTrend) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		d.height = msg.Height
 
 	case tea.KeyMsg:
+		if d.comparing {
+			return d.handleCompareKeys(msg)
+		}
 		switch msg.String() {
 		case "q", "esc":
 			return d, func() tea.Msg { return BackToMenuMsg{} }
+		case "c":
+			if len(d.snapshots) >= 2 {
+				d.comparing = true
+				d.compareCursor = d.cursor
+				d.compareMarks = nil
+			}
 		case "left", "h":
 			if d.selectedRange > 0 {
 				d.selectedRange--
@@ -116,6 +154,10 @@ func (d *DiskTrend) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		case "r":
 			return d, d.loadTrendData()
+		case "x":
+			if len(d.snapshots) > 0 {
+				d.exportPath, d.exportErr = d.exportCSV()
+			}
 		}
 
 	case trendLoadedMsg:
@@ -124,16 +166,130 @@ func (d *DiskTrend) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		d.snapshots = msg.snapshots
 		d.trendData = msg.trendData
 		d.stats = msg.stats
+		d.categories = msg.categories
 		d.cursor = 0
+		d.exportPath, d.exportErr = "", nil
+		d.comparing = false
+		d.compareMarks = nil
 	}
 
 	return d, nil
 }
 
+func (d *DiskTrend) handleCompareKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "q":
+		return d, func() tea.Msg { return BackToMenuMsg{} }
+	case "esc", "c":
+		d.comparing = false
+		d.compareMarks = nil
+	case "up", "k":
+		if d.compareCursor > 0 {
+			d.compareCursor--
+		}
+		if d.compareCursor < d.cursor {
+			d.cursor = d.compareCursor
+		}
+	case "down", "j":
+		if d.compareCursor < len(d.snapshots)-1 {
+			d.compareCursor++
+		}
+		d.followCompareCursor()
+	case " ", "enter":
+		idx := len(d.snapshots) - 1 - d.compareCursor
+		for i, m := range d.compareMarks {
+			if m == idx {
+				d.compareMarks = append(d.compareMarks[:i], d.compareMarks[i+1:]...)
+				return d, nil
+			}
+		}
+		if len(d.compareMarks) == 2 {
+			d.compareMarks = nil // Start a new pair
+		}
+		d.compareMarks = append(d.compareMarks, idx)
+		d.followCompareCursor() // The comparison takes room from the log
+	}
+	return d, nil
+}
+
+// followCompareCursor scrolls the log so the compare cursor stays in view
+func (d *DiskTrend) followCompareCursor() {
+	if visible := d.getVisibleLines(); visible > 0 && d.compareCursor >= d.cursor+visible {
+		d.cursor = d.compareCursor - visible + 1
+	}
+}
+
+// compareMarker is the two-character prefix of log row idx (an index into
+// snapshots) in compare mode: the pick letter, and > for the cursor
+func (d *DiskTrend) compareMarker(idx int, row int) string {
+	mark := " "
+	for i, m := range d.compareMarks {
+		if m == idx {
+			mark = AccentStyle.Render(string(rune('A' + i)))
+		}
+	}
+	if row == d.compareCursor {
+		return lipgloss.NewStyle().Foreground(PrimaryColor).Bold(true).Render(">") + mark
+	}
+	return " " + mark
+}
+
+// renderComparison shows what changed between the two picked snapshots
+func (d *DiskTrend) renderComparison() string {
+	c := scanner.CompareSnapshots(d.snapshots, d.compareMarks[0], d.compareMarks[1])
+	const layout = "01/02 15:04"
+
+	var b strings.Builder
+	b.WriteString(lipgloss.NewStyle().Bold(true).Foreground(PrimaryColor).Render(
+		fmt.Sprintf("> %s -> %s", c.From.Timestamp.Format(layout), c.To.Timestamp.Format(layout))))
+	if days := int(c.To.Timestamp.Sub(c.From.Timestamp).Hours() / 24); days > 0 {
+		b.WriteString(DimStyle.Render(fmt.Sprintf("  (%d days)", days)))
+	}
+	b.WriteString("\n")
+
+	if c.HasDisk {
+		delta := DimStyle.Render("no change")
+		switch {
+		case c.UsedDelta >= usageDeltaMin:
+			delta = WarningStyle.Render("+" + FormatBytes(uint64(c.UsedDelta)) + " used")
+		case c.UsedDelta <= -usageDeltaMin:
+			delta = SuccessStyle.Render("-" + FormatBytes(uint64(-c.UsedDelta)) + " freed")
+		}
+		b.WriteString(fmt.Sprintf("  Used: %s -> %s (%s) | Free: %s -> %s\n",
+			FormatBytes(c.From.UsedBytes), FormatBytes(c.To.UsedBytes), delta,
+			FormatBytes(c.From.FreeBytes), FormatBytes(c.To.FreeBytes)))
+	} else {
+		b.WriteString(DimStyle.Render("  Disk usage unavailable for one of the two points"))
+		b.WriteString("\n")
+	}
+
+	if len(c.Events) == 0 {
+		b.WriteString(DimStyle.Render("  No cleanups in between"))
+		return b.String()
+	}
+	b.WriteString(fmt.Sprintf("  %d cleanups in between reclaimed %s:", len(c.Events), FormatBytes(uint64(c.Cleaned))))
+	for i, ev := range c.Events {
+		if i == compareMaxEvents {
+			b.WriteString(DimStyle.Render(fmt.Sprintf("\n    ... and %d more", len(c.Events)-compareMaxEvents)))
+			break
+		}
+		b.WriteString("\n  ")
+		b.WriteString(d.formatLogEntry(ev))
+	}
+	return b.String()
+}
+
 func (d *DiskTrend) getVisibleLines() int {
 	// Calculate how many log lines fit on screen
 	// Header takes ~8 lines, help takes 2, margins take 4
-	return d.height - 14
+	lines := d.height - 14
+	if len(d.categories) > 0 {
+		lines -= len(d.categories) + 3
+	}
+	if len(d.compareMarks) == 2 {
+		lines -= compareMaxEvents + 6
+	}
+	return lines
 }
 
 func (d *DiskTrend) View() string {
@@ -157,7 +313,7 @@ func (d *DiskTrend) View() string {
 		summary := fmt.Sprintf("  Total: %d scans | %d cleanups | %s reclaimed",
 			d.stats.TotalScans,
 			d.stats.TotalCleanups,
-			humanize.Bytes(uint64(d.stats.TotalCleaned)))
+			FormatBytes(uint64(d.stats.TotalCleaned)))
 		b.WriteString(DimStyle.Render(summary))
 		b.WriteString("\n\n")
 	}
@@ -175,17 +331,53 @@ func (d *DiskTrend) View() string {
 			b.WriteString(chart)
 			b.WriteString("\n\n")
 		}
+		// Reclaimed space per category
+		if len(d.categories) > 0 {
+			b.WriteString(d.renderCategories())
+			b.WriteString("\n\n")
+		}
 		// Activity log
 		logContent := d.renderActivityLog()
 		b.WriteString(logContent)
+		if d.comparing && len(d.compareMarks) == 2 {
+			b.WriteString("\n\n")
+			b.WriteString(d.renderComparison())
+		}
+	}
+
+	if d.exportErr != nil {
+		b.WriteString("\n")
+		b.WriteString(ErrorStyle.Render(fmt.Sprintf("  Export failed: %v", d.exportErr)))
+		b.WriteString("\n")
+	} else if d.exportPath != "" {
+		b.WriteString("\n")
+		b.WriteString(SuccessStyle.Render("  [ok] Exported to " + displayPath(d.exportPath)))
+		b.WriteString("\n")
 	}
 
 	b.WriteString("\n")
+	if d.comparing {
+		pick := "pick A"
+		switch len(d.compareMarks) {
+		case 1:
+			pick = "pick B"
+		case 2:
+			pick = "pick again"
+		}
+		b.WriteString(StyledHelpBar([]KeyHelp{
+			{Key: "j/k", Desc: "move"},
+			{Key: "space", Desc: pick},
+			{Key: "esc", Desc: "done comparing"},
+		}))
+		return lipgloss.Place(d.width, d.height, lipgloss.Center, lipgloss.Center, b.String())
+	}
 	b.WriteString(StyledHelpBar([]KeyHelp{
 		{Key: "k", Desc: "scroll up"},
 		{Key: "j", Desc: "scroll down"},
 		{Key: "h", Desc: "prev"},
 		{Key: "l", Desc: "next"},
+		{Key: "c", Desc: "compare"},
+		{Key: "x", Desc: "export"},
 		{Key: "r", Desc: "refresh"},
 		{Key: "esc", Desc: "back"},
 	}))
@@ -194,6 +386,23 @@ func (d *DiskTrend) View() string {
 	return lipgloss.Place(d.width, d.height, lipgloss.Center, lipgloss.Center, content)
 }
 
+// exportCSV writes the snapshots of the selected range to
+// ~/lume-history-<timestamp>.csv
+func (d *DiskTrend) exportCSV() (string, error) {
+	name := fmt.Sprintf("lume-history-%s.csv", time.Now().Format("20060102-150405"))
+	path := filepath.Join(scanner.GetRealHomeDir(), name)
+
+	f, err := os.Create(path)
+	if err != nil {
+		return "", err
+	}
+	if err := scanner.WriteSnapshotsCSV(f, d.snapshots); err != nil {
+		f.Close()
+		return "", err
+	}
+	return path, f.Close()
+}
+
 func (d *DiskTrend) renderRangeTabs() string {
 	var tabs []string
 	for i, r := range d.ranges {
@@ -251,7 +460,11 @@ func (d *DiskTrend) renderActivityLog() string {
 	// Log entries
 	for i := startIdx; i < endIdx; i++ {
 		s := displaySnapshots[i]
-		line := d.formatLogEntry(s)
+		idx := len(d.snapshots) - 1 - i
+		line := d.formatLogEntry(s) + d.formatUsageDelta(idx)
+		if d.comparing {
+			line = d.compareMarker(idx, i) + strings.TrimPrefix(line, "  ")
+		}
 		lines = append(lines, line)
 	}
 
@@ -320,7 +533,7 @@ func (d *DiskTrend) renderChart() string {
 	var chartLines []string
 	for row := 0; row < chartHeight; row++ {
 		line := strings.Join(chart[row], "")
-		yAxis := fmt.Sprintf("%5s ", humanize.Bytes(uint64(float64(maxUsed)*float64(chartHeight-row)/float64(chartHeight))))
+		yAxis := fmt.Sprintf("%5s ", FormatBytes(uint64(float64(maxUsed)*float64(chartHeight-row)/float64(chartHeight))))
 		chartLines = append(chartLines, lipgloss.NewStyle().Foreground(GrayColor).Render(yAxis)+lipgloss.NewStyle().Foreground(PrimaryColor).Render(line))
 	}
 
@@ -341,6 +554,63 @@ func (d *DiskTrend) renderChart() string {
 	return strings.Join(chartLines, "\n")
 }
 
+// categoryNames maps snapshot triggers to display names
+var categoryNames = map[string]string{
+	"system_junk":   "System Junk",
+	"duplicates":    "Duplicates",
+	"large_files":   "Large Files",
+	"browser_data":  "Browser Data",
+	"app_uninstall": "App Uninstall",
+	"zombie_hunter": "Zombie Files",
+	"system_data":   "System Data",
+}
+
+// renderCategories draws one bar per cleanup category, largest first, scaled
+// to the category that reclaimed the most space in the selected range
+func (d *DiskTrend) renderCategories() string {
+	type entry struct {
+		name string
+		size int64
+	}
+	var entries []entry
+	for category, size := range d.categories {
+		name, ok := categoryNames[category]
+		if !ok {
+			name = category
+		}
+		entries = append(entries, entry{name, size})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].size != entries[j].size {
+			return entries[i].size > entries[j].size
+		}
+		return entries[i].name < entries[j].name
+	})
+
+	barWidth := min(d.width-40, 40)
+	if barWidth < 10 {
+		barWidth = 10
+	}
+	top := entries[0].size
+	if top <= 0 {
+		top = 1
+	}
+
+	var b strings.Builder
+	b.WriteString(lipgloss.NewStyle().Bold(true).Render("> Reclaimed by Category"))
+	b.WriteString("\n")
+	for i, e := range entries {
+		filled := int(float64(barWidth) * float64(e.size) / float64(top))
+		bar := lipgloss.NewStyle().Foreground(SecondaryColor).Render(strings.Repeat("#", filled)) +
+			DimStyle.Render(strings.Repeat("-", barWidth-filled))
+		b.WriteString(fmt.Sprintf("  %-14s %s %s", e.name, bar, FormatBytes(uint64(e.size))))
+		if i < len(entries)-1 {
+			b.WriteString("\n")
+		}
+	}
+	return b.String()
+}
+
 func (d *DiskTrend) formatLogEntry(s scanner.DiskSnapshot) string {
 	timeStr := s.Timestamp.Format("01/02 15:04")
 
@@ -348,7 +618,7 @@ func (d *DiskTrend) formatLogEntry(s scanner.DiskSnapshot) string {
 
 	if s.CleanedSize > 0 {
 		action = lipgloss.NewStyle().Foreground(SecondaryColor).Render("[CLEAN]")
-		sizeStr := humanize.Bytes(uint64(s.CleanedSize))
+		sizeStr := FormatBytes(uint64(s.CleanedSize))
 		if s.Details != "" {
 			details = fmt.Sprintf("%s: %s", s.Details, sizeStr)
 		} else {
@@ -356,10 +626,13 @@ func (d *DiskTrend) formatLogEntry(s scanner.DiskSnapshot) string {
 		}
 	} else {
 		action = lipgloss.NewStyle().Foreground(PrimaryColor).Render("[SCAN]")
-		// Use IBytes for binary units (GiB) to match df output
-		used := humanize.IBytes(s.UsedBytes)
-		free := humanize.IBytes(s.FreeBytes)
-		details = fmt.Sprintf("Used: %s | Free: %s", used, free)
+		if s.TotalBytes == 0 {
+			details = "Disk usage unavailable"
+		} else {
+			used := FormatBytes(s.UsedBytes)
+			free := FormatBytes(s.FreeBytes)
+			details = fmt.Sprintf("Used: %s | Free: %s", used, free)
+		}
 	}
 
 	return fmt.Sprintf("  %-19s | %s | %s",
@@ -368,6 +641,34 @@ func (d *DiskTrend) formatLogEntry(s scanner.DiskSnapshot) string {
 		details)
 }
 
+// formatUsageDelta shows how used space changed between snapshot i and
+// the one before it that has disk figures, or "" when there is none
+func (d *DiskTrend) formatUsageDelta(i int) string {
+	cur := d.snapshots[i]
+	if cur.TotalBytes == 0 {
+		return ""
+	}
+	for j := i - 1; j >= 0; j-- {
+		prev := d.snapshots[j]
+		if prev.TotalBytes == 0 {
+			continue
+		}
+		delta := int64(cur.UsedBytes) - int64(prev.UsedBytes)
+		switch {
+		case delta >= usageDeltaMin:
+			return " | " + WarningStyle.Render("+"+FormatBytes(uint64(delta))+" used")
+		case delta <= -usageDeltaMin:
+			return " | " + SuccessStyle.Render("-"+FormatBytes(uint64(-delta))+" freed")
+		}
+		return " | " + DimStyle.Render("no change")
+	}
+	return ""
+}
+
+// usageDeltaMin is the smallest change in used space the activity log
+// reports; df's own churn between two scans is below it
+const usageDeltaMin = 1 << 20
+
 func (d *DiskTrend) renderStatistics() string {
 	if d.stats == nil {
 		return ""
@@ -397,7 +698,7 @@ func (d *DiskTrend) renderStatistics() string {
 
 	statsContent.WriteString(fmt.Sprintf("  Total Scans:     %d\n", d.stats.TotalScans))
 	statsContent.WriteString(fmt.Sprintf("  Total Cleanups:  %d\n", d.stats.TotalCleanups))
-	statsContent.WriteString(fmt.Sprintf("  Space Reclaimed: %s\n", humanize.Bytes(uint64(d.stats.TotalCleaned))))
+	statsContent.WriteString(fmt.Sprintf("  Space Reclaimed: %s\n", FormatBytes(uint64(d.stats.TotalCleaned))))
 
 	if !d.stats.FirstScan.IsZero() {
 		statsContent.WriteString(fmt.Sprintf("  First Scan:      %s\n", d.stats.FirstScan.Format("2006-01-02 15:04")))
@@ -432,8 +733,8 @@ func (d *DiskTrend) renderDiskBar() string {
 	bar := usedBar + freeBar
 
 	info := fmt.Sprintf("Used: %s / %s (%.1f%%)",
-		humanize.Bytes(snapshot.UsedBytes),
-		humanize.Bytes(snapshot.TotalBytes),
+		FormatBytes(snapshot.UsedBytes),
+		FormatBytes(snapshot.TotalBytes),
 		usedPercent)
 
 	var b strings.Builder
@@ -467,7 +768,12 @@ func RecordSnapshot(total, used uint64, cleanedSize int64, trigger, details stri
 	return func() tea.Msg {
 		// Get current disk usage if not provided
 		if total == 0 || used == 0 {
-			total, used = getCurrentDiskUsage()
+			var ok bool
+			total, used, ok = getCurrentDiskUsage()
+			if !ok && cleanedSize <= 0 {
+				// A scan snapshot without disk numbers carries no information
+				return nil
+			}
 		}
 
 		hm, err := scanner.NewHistoryManager()
@@ -479,9 +785,15 @@ func RecordSnapshot(total, used uint64, cleanedSize int64, trigger, details stri
 	}
 }
 
-func getCurrentDiskUsage() (uint64, uint64) {
-	// Default fallback values
-	return 500 * 1024 * 1024 * 1024, 300 * 1024 * 1024 * 1024
+// getCurrentDiskUsage reads total and used bytes from df. ok is false if df
+// could not be read; the zero values are then recorded as "unknown" rather
+// than made up.
+func getCurrentDiskUsage() (total, used uint64, ok bool) {
+	d, err := readDisk()
+	if err != nil || d.total == 0 {
+		return 0, 0, false
+	}
+	return d.total, d.used, true
 }
 
 func GetQuickStats() (string, error) {
@@ -502,7 +814,7 @@ func GetQuickStats() (string, error) {
 	var b strings.Builder
 	b.WriteString(fmt.Sprintf("[#] %d scans recorded", stats.TotalScans))
 	if stats.TotalCleanups > 0 {
-		b.WriteString(fmt.Sprintf(" | %s reclaimed", humanize.Bytes(uint64(stats.TotalCleaned))))
+		b.WriteString(fmt.Sprintf(" | %s reclaimed", FormatBytes(uint64(stats.TotalCleaned))))
 	}
 	if !stats.LastScan.IsZero() {
 		duration := time.Since(stats.LastScan)
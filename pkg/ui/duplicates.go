@@ -1,7 +1,10 @@
 package ui
 
 import (
+	"context"
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/spinner"
@@ -19,24 +22,76 @@ type DuplicatesView struct {
 	scrollOffset int
 	scanning     bool
 	cleaning     bool
+	quitArmed    bool
 	confirming   bool
 	showDetail   bool
+	detailCursor int // file under the cursor in the detail view
 	spinner      spinner.Model
 	width        int
 	height       int
+	list         *listArea // where View last drew the list, for mouse clicks
 	rootPath     string
-	keepNewest   bool
+	maxDepth     int
+	keepPolicy   scanner.KeepPolicy // from the config; t flips Newest
 	resultCh     chan dupScanResult
+	groupCh      chan scanner.DuplicateGroup // groups confirmed so far by the running scan
+	cancelScan   context.CancelFunc
+	progressCh   chan string
+	progress     string
+	stage        dupStage // the scanner pass the progress lines last reported
 	cleanedSize  int64
+	diskBefore   diskReading
+	diskAfter    diskReading
 	selected     map[int]bool
+	onlySelected bool
 	err          error
+
+	// Root path input state
+	editingRoot bool
+	rootInput   string
+	rootErr     string
 }
 
+// dupDefaultMaxDepth keeps deep trees like node_modules from dominating a scan
+const dupDefaultMaxDepth = 10
+
 type dupScanResult struct {
 	groups []scanner.DuplicateGroup
 	err    error
 }
 
+// dupGroupsMsg carries groups the running scan has confirmed. ch tells apart
+// a scan that has since been replaced.
+type dupGroupsMsg struct {
+	ch     chan scanner.DuplicateGroup
+	groups []scanner.DuplicateGroup
+}
+
+// waitForGroups reads the next group from ch along with any others already
+// waiting, so thousands of groups do not mean thousands of redraws. It
+// returns nil once ch is closed.
+func waitForGroups(ch chan scanner.DuplicateGroup) tea.Cmd {
+	return func() tea.Msg {
+		g, ok := <-ch
+		if !ok {
+			return nil
+		}
+		groups := []scanner.DuplicateGroup{g}
+		for len(groups) < cap(ch) {
+			select {
+			case g, ok := <-ch:
+				if !ok {
+					return dupGroupsMsg{ch: ch, groups: groups}
+				}
+				groups = append(groups, g)
+			default:
+				return dupGroupsMsg{ch: ch, groups: groups}
+			}
+		}
+		return dupGroupsMsg{ch: ch, groups: groups}
+	}
+}
+
 func NewDuplicatesView() *DuplicatesView {
 	s := spinner.New()
 	s.Spinner = spinner.Dot
@@ -47,8 +102,10 @@ func NewDuplicatesView() *DuplicatesView {
 	return &DuplicatesView{
 		spinner:    s,
 		rootPath:   homeDir,
-		keepNewest: true,
+		maxDepth:   dupDefaultMaxDepth,
+		keepPolicy: scanner.LoadConfig().KeepPolicy(true),
 		resultCh:   make(chan dupScanResult, 1),
+		list:       &listArea{},
 		selected:   make(map[int]bool),
 	}
 }
@@ -64,16 +121,77 @@ func (m *DuplicatesView) startScan() tea.Cmd {
 	m.scanning = true
 	m.groups = []scanner.DuplicateGroup{}
 	m.selected = make(map[int]bool)
-
+	m.onlySelected = false
+	m.progress = ""
+	m.stage = dupStage{}
+	m.progressCh = make(chan string, 1)
+	m.groupCh = make(chan scanner.DuplicateGroup, 256)
+	m.cursor = 0
+	m.scrollOffset = 0
+
+	root, maxDepth := m.rootPath, m.maxDepth
+	progress := progressRelay(m.progressCh)
+	found := m.groupCh
+	ctx := newScanContext(&m.cancelScan)
 	go func() {
-		s := scanner.NewDuplicateScanner(m.rootPath)
-		groups, err := s.Scan(nil)
+		s := scanner.NewDuplicateScanner(root)
+		s.SetMaxDepth(maxDepth)
+		groups, err := s.ScanStream(ctx, progress, found)
+		close(progress)
+		close(found)
 		m.resultCh <- dupScanResult{groups: groups, err: err}
 	}()
 
-	return func() tea.Msg {
-		return <-m.resultCh
+	return tea.Batch(
+		func() tea.Msg {
+			return <-m.resultCh
+		},
+		waitForProgress(m.progressCh),
+		waitForGroups(m.groupCh),
+	)
+}
+
+func (m *DuplicatesView) handleRootKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyCtrlC:
+		return m, tea.Quit
+	case tea.KeyEsc:
+		m.editingRoot = false
+		m.rootErr = ""
+	case tea.KeyEnter:
+		root := expandHome(strings.TrimSpace(m.rootInput))
+		info, err := os.Stat(root)
+		if err != nil {
+			m.rootErr = err.Error()
+			return m, nil
+		}
+		if !info.IsDir() {
+			m.rootErr = "not a directory"
+			return m, nil
+		}
+		m.editingRoot = false
+		m.rootErr = ""
+		m.rootPath = filepath.Clean(root)
+		return m, m.startScan()
+	case tea.KeyBackspace:
+		if r := []rune(m.rootInput); len(r) > 0 {
+			m.rootInput = string(r[:len(r)-1])
+		}
+	case tea.KeyRunes, tea.KeySpace:
+		m.rootInput += string(msg.Runes)
 	}
+	return m, nil
+}
+
+// expandHome replaces a leading ~ with the user's home directory
+func expandHome(path string) string {
+	if path == "~" {
+		return scanner.GetRealHomeDir()
+	}
+	if strings.HasPrefix(path, "~/") {
+		return filepath.Join(scanner.GetRealHomeDir(), path[2:])
+	}
+	return path
 }
 
 func (m *DuplicatesView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -83,36 +201,49 @@ func (m *DuplicatesView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.height = msg.Height
 		m.updateScrollOffset()
 
+	case tea.MouseMsg:
+		return m.handleMouse(msg)
+
 	case tea.KeyMsg:
 		if m.confirming {
-			switch msg.String() {
-			case "y", "Y":
-				m.confirming = false
-				return m, m.startClean()
-			case "n", "N", "esc":
+			if done, confirmed := confirmKey(msg); done {
 				m.confirming = false
+				if confirmed {
+					return m, m.startClean()
+				}
 			}
 			return m, nil
 		}
 
-		if m.scanning || m.cleaning {
+		if m.cleaning {
+			return m, busyKey(msg, &m.quitArmed)
+		}
+
+		if m.scanning {
 			switch msg.String() {
 			case "q", "ctrl+c":
+				stopScan(&m.cancelScan)
 				return m, tea.Quit
 			case "esc":
+				stopScan(&m.cancelScan)
 				return m, func() tea.Msg { return BackToMenuMsg{} }
+			case "up", "k", "down", "j":
+				// Groups found so far can be browsed while the scan runs
+			default:
+				return m, nil
 			}
-			return m, nil
+		}
+
+		if m.editingRoot {
+			return m.handleRootKeys(msg)
 		}
 
 		if m.showDetail {
-			switch msg.String() {
-			case "esc", "i", "enter":
-				m.showDetail = false
-			}
-			return m, nil
+			return m.handleDetailKeys(msg)
 		}
 
+		visible := m.visibleGroups()
+
 		switch msg.String() {
 		case "q", "ctrl+c":
 			return m, tea.Quit
@@ -124,28 +255,67 @@ func (m *DuplicatesView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			m.updateScrollOffset()
 		case "down", "j":
-			if m.cursor < len(m.groups)-1 {
+			if m.cursor < len(visible)-1 {
 				m.cursor++
 			}
 			m.updateScrollOffset()
 		case " ", "enter":
-			if len(m.groups) > 0 && m.cursor < len(m.groups) {
-				m.selected[m.cursor] = !m.selected[m.cursor]
+			if m.cursor < len(visible) {
+				idx := visible[m.cursor]
+				m.selected[idx] = !m.selected[idx]
+				m.clampCursor()
 			}
 		case "a":
-			allSelected := len(m.selected) == len(m.groups)
-			m.selected = make(map[int]bool)
-			if !allSelected {
-				for i := range m.groups {
-					m.selected[i] = true
+			allSelected := true
+			for _, idx := range visible {
+				if !m.selected[idx] {
+					allSelected = false
+					break
 				}
 			}
+			for _, idx := range visible {
+				m.selected[idx] = !allSelected
+			}
+			m.clampCursor()
+		case "v":
+			current := -1
+			if m.cursor < len(visible) {
+				current = visible[m.cursor]
+			}
+			m.onlySelected = !m.onlySelected
+			m.cursor = 0
+			for i, idx := range m.visibleGroups() {
+				if idx == current {
+					m.cursor = i
+					break
+				}
+			}
+			m.updateScrollOffset()
 		case "i":
-			if len(m.groups) > 0 {
+			if len(visible) > 0 {
 				m.showDetail = true
+				m.detailCursor = 0
+			}
+		case "o":
+			if m.cursor < len(visible) {
+				group := m.groups[visible[m.cursor]]
+				return m, revealInFinder(group.Files[group.KeepIndex(m.keepPolicy)].Path)
+			}
+		case "p":
+			if m.cursor < len(visible) {
+				// Every copy, to compare them
+				var paths []string
+				for _, f := range m.groups[visible[m.cursor]].Files {
+					paths = append(paths, f.Path)
+				}
+				return m, quickLook(paths...)
 			}
+		case "g":
+			m.editingRoot = true
+			m.rootInput = m.rootPath
+			m.rootErr = ""
 		case "t":
-			m.keepNewest = !m.keepNewest
+			m.keepPolicy.Newest = !m.keepPolicy.Newest
 		case "r":
 			return m, m.startScan()
 		case "d", "c":
@@ -162,22 +332,55 @@ func (m *DuplicatesView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 	case dupScanResult:
+		if scanCanceled(msg.err) {
+			// A scan stopped by leaving the view; a newer one may be running
+			return m, nil
+		}
 		m.scanning = false
+		// The final list is sorted; keep the cursor on the group it was on
+		current := ""
+		if m.cursor < len(m.groups) {
+			current = m.groups[m.cursor].Hash
+		}
 		m.groups = msg.groups
 		m.err = msg.err
-		if m.cursor >= len(m.groups) {
-			m.cursor = 0
+		m.cursor = 0
+		for i, g := range m.groups {
+			if g.Hash == current {
+				m.cursor = i
+				break
+			}
 		}
-		m.scrollOffset = 0
+		m.updateScrollOffset()
+
+	case dupGroupsMsg:
+		if msg.ch == m.groupCh && m.scanning {
+			m.groups = append(m.groups, msg.groups...)
+			return m, waitForGroups(m.groupCh)
+		}
+		return m, nil
 
 	case cleanResultMsg:
 		m.cleaning = false
 		m.err = msg.err
 		if msg.size > 0 {
-			return m, tea.Batch(m.startScan(), RecordSnapshot(0, 0, msg.size, "duplicates", msg.details))
+			m.cleanedSize = msg.size
+			m.diskBefore, m.diskAfter = msg.before, msg.after
+			return m, tea.Batch(m.startScan(), RecordSnapshot(msg.after.total, msg.after.used, msg.size, "duplicates", msg.details))
 		}
 		return m, m.startScan()
 
+	case progressMsg:
+		if m.scanning {
+			m.progress = string(msg)
+			m.stage.update(m.progress)
+			return m, waitForProgress(m.progressCh)
+		}
+
+	case finderResultMsg:
+		m.err = msg.err
+		return m, nil
+
 	case BackToMenuMsg:
 		return NewMainMenu(), nil
 	}
@@ -187,13 +390,163 @@ func (m *DuplicatesView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, cmd
 }
 
-func (m *DuplicatesView) updateScrollOffset() {
-	maxDisplay := MaxListItems
-	if m.height > 20 {
-		maxDisplay = m.height - 12
+// handleDetailKeys moves through the copies of one group and picks the one
+// to keep, overriding the keep strategy for that group only
+func (m *DuplicatesView) handleDetailKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	visible := m.visibleGroups()
+	if m.cursor >= len(visible) {
+		m.showDetail = false
+		return m, nil
 	}
-	if len(m.groups) < maxDisplay {
-		maxDisplay = len(m.groups)
+	group := &m.groups[visible[m.cursor]]
+
+	switch msg.String() {
+	case "q", "ctrl+c":
+		return m, tea.Quit
+	case "esc", "i", "enter":
+		m.showDetail = false
+	case "up", "k":
+		if m.detailCursor > 0 {
+			m.detailCursor--
+		}
+	case "down", "j":
+		if m.detailCursor < len(group.Files)-1 {
+			m.detailCursor++
+		}
+	case " ":
+		// Picking the current pick again hands the group back to the strategy
+		path := group.Files[m.detailCursor].Path
+		if group.Keep == path {
+			group.Keep = ""
+		} else {
+			group.Keep = path
+		}
+	case "o":
+		return m, revealInFinder(group.Files[m.detailCursor].Path)
+	case "p":
+		return m, quickLook(group.Files[m.detailCursor].Path)
+	case "t":
+		m.keepPolicy.Newest = !m.keepPolicy.Newest
+	}
+	return m, nil
+}
+
+// dupStageNames names the duplicate scanner's three passes, in order
+var dupStageNames = []string{"Group by size", "Quick hash (head + tail)", "Full SHA-256"}
+
+// dupStage follows the duplicate scanner through its passes from the
+// progress lines it sends
+type dupStage struct {
+	n     int // 1-3, 0 before the first line
+	done  int // files (stage 2) or groups (stage 3) hashed so far
+	total int // 0 when the line did not say
+}
+
+// update reads one progress line from DuplicateScanner.ScanStream
+func (s *dupStage) update(line string) {
+	var done, total int
+	switch {
+	case strings.HasPrefix(line, "Stage 1:"), strings.HasPrefix(line, "Collecting"):
+		*s = dupStage{n: 1}
+	case strings.HasPrefix(line, "Stage 2:"):
+		fmt.Sscanf(line, "Stage 2: Quick hash %d", &total)
+		*s = dupStage{n: 2, total: total}
+	case strings.HasPrefix(line, "Quick hashing:"):
+		fmt.Sscanf(line, "Quick hashing: %d / %d", &done, &total)
+		*s = dupStage{n: 2, done: done, total: total}
+	case strings.HasPrefix(line, "Stage 3:"):
+		fmt.Sscanf(line, "Stage 3: Full hash %d", &total)
+		*s = dupStage{n: 3, total: total}
+	case strings.HasPrefix(line, "Full hashing:"):
+		fmt.Sscanf(line, "Full hashing: %d / %d", &done, &total)
+		*s = dupStage{n: 3, done: done, total: total}
+	}
+}
+
+// count is "done / total" for the stage, or "" when the scanner gave none
+func (s dupStage) count() string {
+	if s.total == 0 {
+		return ""
+	}
+	unit := "files"
+	if s.n == 3 {
+		unit = "groups"
+	}
+	return fmt.Sprintf("%s / %s %s", humanize.Comma(int64(s.done)), humanize.Comma(int64(s.total)), unit)
+}
+
+// summary is the one-line form, e.g. "Stage 3/3 Full SHA-256 120 / 800 groups"
+func (s dupStage) summary() string {
+	line := fmt.Sprintf("Stage %d/%d %s", s.n, len(dupStageNames), dupStageNames[s.n-1])
+	if c := s.count(); c != "" {
+		line += " " + c
+	}
+	return line
+}
+
+// view lists the three passes with the current one marked and, when the
+// scanner reports counts, a bar for it
+func (s dupStage) view() string {
+	var b strings.Builder
+	for i, name := range dupStageNames {
+		stage := i + 1
+		switch {
+		case stage < s.n:
+			b.WriteString(SuccessStyle.Render(fmt.Sprintf("  [x] %d. %s", stage, name)))
+		case stage == s.n:
+			if s.total == 0 {
+				b.WriteString(AccentStyle.Render(fmt.Sprintf("  [>] %d. %s", stage, name)))
+			} else {
+				b.WriteString(AccentStyle.Render(fmt.Sprintf("  [>] %d. %s", stage, padRight(name, 24))))
+				percent := float64(s.done) / float64(s.total) * 100
+				b.WriteString(" ")
+				b.WriteString(ProgressBar(percent, 20, PrimaryColor, DimColor))
+				b.WriteString(" ")
+				b.WriteString(DimStyle.Render(s.count()))
+			}
+		default:
+			b.WriteString(DimStyle.Render(fmt.Sprintf("  [ ] %d. %s", stage, name)))
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// visibleGroups returns indices into m.groups that are currently listed
+func (m DuplicatesView) visibleGroups() []int {
+	visible := make([]int, 0, len(m.groups))
+	for i := range m.groups {
+		if !m.onlySelected || m.selected[i] {
+			visible = append(visible, i)
+		}
+	}
+	return visible
+}
+
+// clampCursor keeps the cursor inside the visible list after it shrinks
+func (m *DuplicatesView) clampCursor() {
+	if n := len(m.visibleGroups()); m.cursor >= n {
+		m.cursor = max(n-1, 0)
+	}
+	m.updateScrollOffset()
+}
+
+// maxRows is how many groups fit on screen
+func (m DuplicatesView) maxRows() int {
+	if m.height <= 20 {
+		return MaxListItems
+	}
+	if m.scanning {
+		// Room for the progress line above the list
+		return m.height - 14
+	}
+	return m.height - 12
+}
+
+func (m *DuplicatesView) updateScrollOffset() {
+	maxDisplay := m.maxRows()
+	if n := len(m.visibleGroups()); n < maxDisplay {
+		maxDisplay = n
 	}
 	if m.cursor < m.scrollOffset {
 		m.scrollOffset = m.cursor
@@ -203,11 +556,32 @@ func (m *DuplicatesView) updateScrollOffset() {
 	}
 }
 
+// handleMouse scrolls with the wheel and moves the cursor to a clicked row;
+// clicking the row already under the cursor toggles it
+func (m *DuplicatesView) handleMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
+	if key, ok := wheelKey(msg); ok {
+		return m.Update(key)
+	}
+	if m.confirming || m.cleaning || m.scanning {
+		return m, nil
+	}
+	if row := clickedRow(msg, m.list); row >= 0 {
+		i := m.scrollOffset + row
+		if i == m.cursor {
+			return m.Update(spaceKey)
+		}
+		m.cursor = i
+	}
+	return m, nil
+}
+
 func (m *DuplicatesView) startClean() tea.Cmd {
 	m.cleaning = true
+	m.quitArmed = false
 
 	return func() tea.Msg {
 		c := cleaner.NewCleaner()
+		before := sampleDisk()
 
 		var selected []scanner.DuplicateGroup
 		groupCount := 0
@@ -218,12 +592,12 @@ func (m *DuplicatesView) startClean() tea.Cmd {
 			}
 		}
 
-		size, err := c.CleanDuplicateFiles(selected, m.keepNewest, nil)
+		size, err := c.CleanDuplicateFiles(selected, m.keepPolicy, nil)
 		details := ""
 		if groupCount > 0 {
 			details = fmt.Sprintf("%d duplicate groups", groupCount)
 		}
-		return cleanResultMsg{size: size, err: err, details: details}
+		return cleanResultMsg{size: size, err: err, details: details, before: before, after: sampleDisk()}
 	}
 }
 
@@ -231,6 +605,7 @@ func (m DuplicatesView) View() string {
 	if m.width == 0 {
 		return "Loading..."
 	}
+	m.list.rows = 0
 
 	if m.showDetail {
 		return m.detailView()
@@ -240,16 +615,50 @@ func (m DuplicatesView) View() string {
 
 	b.WriteString(PageHeader("", "Duplicate Files", m.width))
 	b.WriteString("\n")
-	b.WriteString(DimStyle.Render(fmt.Sprintf("  Scanning: %s", m.rootPath)))
+	b.WriteString(DimStyle.Render(fmt.Sprintf("  Scanning: %s (depth <= %d)", displayPath(m.rootPath), m.maxDepth)))
 	b.WriteString("\n\n")
 
+	if m.scanning && len(m.groups) == 0 {
+		b.WriteString(fmt.Sprintf("%s Scanning...\n\n", m.spinner.View()))
+		b.WriteString(m.stage.view())
+		if m.progress != "" && m.stage.total == 0 {
+			b.WriteString("\n")
+			b.WriteString(DimStyle.Render(m.progress))
+			b.WriteString("\n")
+		}
+		b.WriteString("\n")
+		b.WriteString(DimStyle.Render("esc to stop"))
+		return Center(m.width, m.height, b.String())
+	}
 	if m.scanning {
-		b.WriteString(fmt.Sprintf("%s Scanning...\n", m.spinner.View()))
+		b.WriteString(fmt.Sprintf("%s %d groups so far", m.spinner.View(), len(m.groups)))
+		if m.stage.n > 0 {
+			b.WriteString(DimStyle.Render(" · " + m.stage.summary()))
+		}
+		b.WriteString("\n\n")
+	}
+
+	if m.editingRoot {
+		b.WriteString("Folder to scan:\n\n")
+		b.WriteString(AccentStyle.Render("> "))
+		b.WriteString(m.rootInput + "_")
+		b.WriteString("\n")
+		if m.rootErr != "" {
+			b.WriteString("\n")
+			b.WriteString(ErrorStyle.Render(m.rootErr))
+			b.WriteString("\n")
+		}
+		b.WriteString("\n")
+		b.WriteString(StyledHelpBar([]KeyHelp{
+			{Key: "enter", Desc: "scan"},
+			{Key: "esc", Desc: "cancel"},
+		}))
 		return Center(m.width, m.height, b.String())
 	}
 
 	if m.cleaning {
 		b.WriteString(fmt.Sprintf("%s Deleting...\n", m.spinner.View()))
+		b.WriteString(busyHint(m.quitArmed))
 		return Center(m.width, m.height, b.String())
 	}
 
@@ -258,29 +667,45 @@ func (m DuplicatesView) View() string {
 		b.WriteString("\n")
 	}
 
+	if m.cleanedSize > 0 {
+		b.WriteString("  ")
+		b.WriteString(SuccessStyle.Render(fmt.Sprintf("[ok] Cleaned %s", FormatBytes(uint64(m.cleanedSize)))))
+		b.WriteString("\n")
+		b.WriteString(DiskBeforeAfter(m.diskBefore, m.diskAfter, 30))
+		b.WriteString("\n")
+	}
+
+	visible := m.visibleGroups()
+
+	if m.onlySelected {
+		b.WriteString(AccentStyle.Render("Showing selected items only"))
+		b.WriteString(DimStyle.Render(" (v to show all)"))
+		b.WriteString("\n\n")
+	}
+
 	if len(m.groups) == 0 {
 		b.WriteString("No duplicate files found.\n")
+	} else if len(visible) == 0 {
+		b.WriteString("Nothing selected.\n")
 	} else {
 		b.WriteString(TableHeader([]string{"", "#", "Size", "Reclaimable", "Filename"}, []int{3, 5, 10, 12, 30}))
 		b.WriteString("\n")
 		b.WriteString(Divider(65))
 		b.WriteString("\n")
 
-		maxDisplay := MaxListItems
-		if m.height > 20 {
-			maxDisplay = m.height - 12
-		}
-		if len(m.groups) < maxDisplay {
-			maxDisplay = len(m.groups)
+		maxDisplay := m.maxRows()
+		if len(visible) < maxDisplay {
+			maxDisplay = len(visible)
 		}
 
-		for i := m.scrollOffset; i < m.scrollOffset+maxDisplay && i < len(m.groups); i++ {
-			group := m.groups[i]
-			cb := Checkbox(m.selected[i])
+		m.list.markRows(&b, min(maxDisplay, len(visible)-m.scrollOffset))
+		for i := m.scrollOffset; i < m.scrollOffset+maxDisplay && i < len(visible); i++ {
+			group := m.groups[visible[i]]
+			cb := Checkbox(m.selected[visible[i]])
 
 			dupCount := padLeft(fmt.Sprintf("%d", len(group.Files)), 5)
-			fileSize := padLeft(humanize.Bytes(uint64(group.Size)), 10)
-			reclaimSize := padLeft(humanize.Bytes(uint64(int64(len(group.Files)-1)*group.Size)), 12)
+			fileSize := padLeft(FormatBytes(uint64(group.Size)), 10)
+			reclaimSize := padLeft(FormatBytes(uint64(group.Reclaimable())), 12)
 
 			name := truncate(group.Files[0].Name, 30)
 
@@ -295,7 +720,7 @@ func (m DuplicatesView) View() string {
 			b.WriteString("\n")
 		}
 
-		above, below := ScrollIndicator(m.scrollOffset, len(m.groups), maxDisplay)
+		above, below := ScrollIndicator(m.scrollOffset, len(visible), maxDisplay)
 		if above != "" {
 			b.WriteString(above)
 			b.WriteString("\n")
@@ -308,53 +733,70 @@ func (m DuplicatesView) View() string {
 		totalReclaim := int64(0)
 		selectedReclaim := int64(0)
 		for i := range m.groups {
-			totalReclaim += int64(len(m.groups[i].Files)-1) * m.groups[i].Size
+			totalReclaim += m.groups[i].Reclaimable()
 			if m.selected[i] {
-				selectedReclaim += int64(len(m.groups[i].Files)-1) * m.groups[i].Size
+				selectedReclaim += m.groups[i].Reclaimable()
 			}
 		}
 
-		keepStrategy := "keep newest"
-		if !m.keepNewest {
-			keepStrategy = "keep oldest"
+		keepStrategy := m.strategy()
+		picked := 0
+		for _, g := range m.groups {
+			if g.Keep != "" {
+				picked++
+			}
+		}
+		if picked > 0 {
+			keepStrategy += fmt.Sprintf(", %d picked", picked)
 		}
 
 		stats := StatsBar([]string{
-			fmt.Sprintf("Total: %s", humanize.Bytes(uint64(totalReclaim))),
-			fmt.Sprintf("Selected: %s", humanize.Bytes(uint64(selectedReclaim))),
+			fmt.Sprintf("Total: %s", FormatBytes(uint64(totalReclaim))),
+			fmt.Sprintf("Selected: %s", FormatBytes(uint64(selectedReclaim))),
 			fmt.Sprintf("Strategy: %s", keepStrategy),
 		})
 		b.WriteString(stats)
 	}
 
 	b.WriteString("\n\n")
-	if m.confirming {
+	if m.scanning {
+		b.WriteString(StyledHelpBar([]KeyHelp{
+			{Key: "j/k", Desc: "navigate"},
+			{Key: "esc", Desc: "stop"},
+		}))
+		b.WriteString("\n")
+		b.WriteString(DimStyle.Render("Selecting and deleting open up once the scan finishes."))
+	} else if m.confirming {
 		selectedReclaim := int64(0)
 		selectedCount := 0
+		var paths []string
 		for i := range m.groups {
 			if m.selected[i] {
-				selectedReclaim += int64(len(m.groups[i].Files)-1) * m.groups[i].Size
+				selectedReclaim += m.groups[i].Reclaimable()
 				selectedCount++
+				for _, f := range m.groups[i].Files {
+					paths = append(paths, f.Path)
+				}
 			}
 		}
-		b.WriteString("  " + WarningStyle.Render(fmt.Sprintf("Move duplicates from %d groups (%s) to Trash?", selectedCount, humanize.Bytes(uint64(selectedReclaim)))))
-		b.WriteString("\n\n")
-		b.WriteString(StyledHelpBar([]KeyHelp{
-			{Key: "y", Desc: "confirm"},
-			{Key: "n/esc", Desc: "cancel"},
-		}))
+		b.WriteString(syncedDataWarning(paths))
+		b.WriteString(ConfirmDialog(fmt.Sprintf("Move duplicates from %d groups (%s) to Trash?", selectedCount, FormatBytes(uint64(selectedReclaim)))))
 	} else {
 		b.WriteString(StyledHelpBar([]KeyHelp{
 			{Key: "j/k", Desc: "navigate"},
 			{Key: "space", Desc: "toggle"},
 			{Key: "a", Desc: "all"},
 			{Key: "i", Desc: "info"},
+			{Key: "o", Desc: "reveal"},
+			{Key: "p", Desc: "quick look"},
+			{Key: "v", Desc: "selected"},
 			{Key: "t", Desc: "strategy"},
+			{Key: "g", Desc: "folder"},
 			{Key: "d", Desc: "delete"},
 		}))
 	}
 
-	return Center(m.width, m.height, b.String())
+	return m.list.center(m.width, m.height, b.String())
 }
 
 func (m DuplicatesView) detailView() string {
@@ -363,37 +805,74 @@ func (m DuplicatesView) detailView() string {
 	b.WriteString(PageHeader("", "Duplicate Details", m.width))
 	b.WriteString("\n\n")
 
-	if m.cursor < len(m.groups) {
-		group := m.groups[m.cursor]
+	if visible := m.visibleGroups(); m.cursor < len(visible) {
+		group := m.groups[visible[m.cursor]]
 
 		b.WriteString(fmt.Sprintf("File: %s\n", group.Files[0].Name))
-		b.WriteString(fmt.Sprintf("Size: %s\n", humanize.Bytes(uint64(group.Size))))
+		b.WriteString(fmt.Sprintf("Size: %s\n", FormatBytes(uint64(group.Size))))
 		b.WriteString(fmt.Sprintf("Duplicates: %d\n", len(group.Files)))
-		b.WriteString(fmt.Sprintf("Reclaimable: %s\n", humanize.Bytes(uint64(int64(len(group.Files)-1)*group.Size))))
+		b.WriteString(fmt.Sprintf("Reclaimable: %s\n", FormatBytes(uint64(group.Reclaimable()))))
 		b.WriteString("\n")
 
-		b.WriteString("Locations:\n")
+		b.WriteString("Locations (* is kept):\n")
+		keep := group.KeepIndex(m.keepPolicy)
 		for i, file := range group.Files {
 			marker := "  "
-			if m.keepNewest && i == 0 {
+			if i == keep {
 				marker = "* "
 			}
-			shortPath := file.Path
-			if len(shortPath) > 50 {
-				shortPath = "..." + shortPath[len(shortPath)-47:]
+			shortPath := truncatePathLeft(displayPath(file.Path), max(50, m.width-8))
+			line := fmt.Sprintf("%s%s", marker, shortPath)
+			if file.Links > 1 {
+				// Deleting it only removes one name; the data stays
+				line += fmt.Sprintf(" (%d hard links, frees nothing)", file.Links)
+			}
+			if i == m.detailCursor {
+				line = SelectedScanItemStyle.Render(line)
 			}
-			b.WriteString(fmt.Sprintf("%s%s\n", marker, shortPath))
+			b.WriteString(line)
+			b.WriteString("\n")
 		}
 
-		strategy := "keep newest"
-		if !m.keepNewest {
-			strategy = "keep oldest"
-		}
+		strategy := m.strategy()
 		b.WriteString("\n")
-		b.WriteString(InfoBoxStyle.Render(fmt.Sprintf("Strategy: %s (press 't' to toggle)", strategy)))
+		if group.Keep != "" && group.Files[keep].Path == group.Keep {
+			b.WriteString(InfoBoxStyle.Render(fmt.Sprintf("Keeping your pick in this group (space on it again to %s)", strategy)))
+		} else {
+			b.WriteString(InfoBoxStyle.Render(fmt.Sprintf("Strategy: %s (press 't' to toggle)", strategy)))
+		}
 		b.WriteString("\n\n")
 		b.WriteString(SuccessStyle.Render("[i] Files will be moved to Trash (recoverable)"))
+		b.WriteString("\n\n")
+		b.WriteString(StyledHelpBar([]KeyHelp{
+			{Key: "j/k", Desc: "navigate"},
+			{Key: "space", Desc: "keep this copy"},
+			{Key: "o", Desc: "reveal"},
+			{Key: "p", Desc: "quick look"},
+			{Key: "t", Desc: "strategy"},
+			{Key: "esc/enter", Desc: "back"},
+		}))
 	}
 
 	return Center(m.width, m.height, b.String())
 }
+
+// strategy describes how cleaning picks the copy to keep, e.g.
+// "keep in ~/Documents, then newest"
+func (m DuplicatesView) strategy() string {
+	var rules []string
+	for _, dir := range m.keepPolicy.PreferPaths {
+		rules = append(rules, displayPath(dir))
+	}
+	if m.keepPolicy.ShortestPath {
+		rules = append(rules, "shortest path")
+	}
+	age := "newest"
+	if !m.keepPolicy.Newest {
+		age = "oldest"
+	}
+	if len(rules) == 0 {
+		return "keep " + age
+	}
+	return fmt.Sprintf("keep %s, then %s", strings.Join(rules, ", "), age)
+}
@@ -0,0 +1,59 @@
+package ui
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// finderResultMsg reports that handing a path to Finder or Quick Look failed
+type finderResultMsg struct {
+	err error
+}
+
+// revealInFinder selects path in a Finder window (open -R), so an item can be
+// looked at before deciding whether to clean it
+func revealInFinder(path string) tea.Cmd {
+	return func() tea.Msg {
+		out, err := exec.Command("open", "-R", path).CombinedOutput()
+		if err == nil {
+			return nil
+		}
+		if msg := strings.TrimSpace(string(out)); msg != "" {
+			err = fmt.Errorf("reveal %s: %s", displayPath(path), msg)
+		}
+		return finderResultMsg{err: err}
+	}
+}
+
+// fullDiskAccessURL opens System Settings at Privacy & Security → Full Disk Access
+const fullDiskAccessURL = "x-apple.systempreferences:com.apple.preference.security?Privacy_AllDiskAccess"
+
+// openFullDiskAccessSettings takes the user straight to the pane where the
+// terminal can be granted Full Disk Access
+func openFullDiskAccessSettings() tea.Cmd {
+	return func() tea.Msg {
+		if err := exec.Command("open", fullDiskAccessURL).Run(); err != nil {
+			return finderResultMsg{err: fmt.Errorf("open Full Disk Access settings: %w", err)}
+		}
+		return nil
+	}
+}
+
+// quickLook opens paths in a Quick Look preview (qlmanage -p), to check what
+// an image, video or PDF is before deleting it; several paths can be paged
+// through side by side. qlmanage stays running until
+// the preview is closed and prints to its output, so it is started detached
+// from the terminal and not waited on here.
+func quickLook(paths ...string) tea.Cmd {
+	return func() tea.Msg {
+		cmd := exec.Command("qlmanage", append([]string{"-p"}, paths...)...)
+		if err := cmd.Start(); err != nil {
+			return finderResultMsg{err: fmt.Errorf("quick look: %w", err)}
+		}
+		go cmd.Wait()
+		return nil
+	}
+}
@@ -0,0 +1,210 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// viewHelp is the full key reference of each view, shown by ? on top of
+// it; the views' own help bars only have room for the common keys
+var viewHelp = map[ViewType]struct {
+	title string
+	keys  []KeyHelp
+}{
+	ViewMainMenu: {"Main Menu", []KeyHelp{
+		{"j/k ↑/↓", "move"},
+		{"enter/space", "open the selected tool"},
+		{"t", "next theme"},
+		{"e", "edit theme"},
+		{"u", "switch between GB and GiB"},
+		{"v", "show the next mounted disk"},
+		{"f", "open Full Disk Access settings (when missing)"},
+	}},
+	ViewSystemJunk: {"System Junk", []KeyHelp{
+		{"j/k ↑/↓", "move"},
+		{"space/enter", "toggle target"},
+		{"a", "select all / none / recommended (large regenerable)"},
+		{"m", "smart select: regenerable caches only"},
+		{"/", "filter by name"},
+		{"v", "show selected only"},
+		{"l", "hide or show small targets"},
+		{"s / S", "sort by size, name or risk / reverse"},
+		{"e", "look inside a target, clean single entries"},
+		{"p", "preview files"},
+		{"o", "reveal in Finder"},
+		{"w", "warnings (inaccessible folders)"},
+		{"d/c", "move selected to Trash"},
+		{"D", "clean, then empty the Trash"},
+		{"r", "rescan"},
+		{"R", "rescan only the target under the cursor"},
+		{"L", "lock or unlock the target: locked ones are never selected"},
+	}},
+	ViewLargeFiles: {"Large Files", []KeyHelp{
+		{"j/k ↑/↓", "move"},
+		{"space/enter", "toggle file"},
+		{"a", "select all / none"},
+		{"f", "filter by file type"},
+		{"v", "show selected only"},
+		{"p", "Quick Look"},
+		{"o", "reveal in Finder"},
+		{"d/c", "move selected to Trash"},
+		{"r", "rescan"},
+	}},
+	ViewZombieHunter: {"Zombie Hunter", []KeyHelp{
+		{"j/k ↑/↓", "move"},
+		{"space", "toggle file"},
+		{"a", "select all / none"},
+		{"tab/h/l", "switch between age ranges and files"},
+		{"1-4", "jump to an age range"},
+		{"m", "age by access or modification time"},
+		{"x", "export to CSV"},
+		{"d/c", "move selected to Trash"},
+		{"r", "rescan"},
+	}},
+	ViewAppUninstaller: {"App Uninstaller", []KeyHelp{
+		{"j/k ↑/↓", "move"},
+		{"space", "check app"},
+		{"a", "check all / none"},
+		{"enter/i", "details and residual files"},
+		{"t", "keep or remove residuals (details, confirm)"},
+		{"s", "sort by size, name or last used"},
+		{"d/u", "uninstall checked apps, or the one under the cursor"},
+		{"r", "rescan"},
+	}},
+	ViewDuplicates: {"Duplicate Files", []KeyHelp{
+		{"j/k ↑/↓", "move"},
+		{"space/enter", "toggle group"},
+		{"space", "keep this copy (in details)"},
+		{"a", "select all / none"},
+		{"i", "group details"},
+		{"t", "keep strategy: oldest or newest"},
+		{"v", "show selected only"},
+		{"g", "choose folder to scan"},
+		{"p", "Quick Look"},
+		{"o", "reveal in Finder"},
+		{"d/c", "move extra copies to Trash"},
+		{"r", "rescan"},
+	}},
+	ViewBrowserData: {"Browser Data", []KeyHelp{
+		{"j/k ↑/↓", "move"},
+		{"enter", "browser details"},
+		{"h/←", "back to browsers"},
+		{"space", "toggle item"},
+		{"a", "select all caches"},
+		{"d/c", "clean selected"},
+		{"r", "rescan"},
+	}},
+	ViewDiskTrend: {"Disk Trend", []KeyHelp{
+		{"j/k", "scroll the log"},
+		{"h/l", "previous / next chart range"},
+		{"c", "compare two points in time"},
+		{"space/enter", "pick a point (comparing)"},
+		{"x", "export to CSV"},
+		{"r", "refresh"},
+	}},
+	ViewDiskAnalyzer: {"Disk Analyzer", []KeyHelp{
+		{"j/k ↑/↓", "move"},
+		{"enter/l/→", "open folder"},
+		{"esc/h/←", "up a level"},
+		{"+ / -", "raise / lower the minimum size"},
+		{"g", "choose folder to analyze"},
+		{"r", "rescan"},
+	}},
+	ViewSystemData: {"System Data", []KeyHelp{
+		{"j/k ↑/↓", "move"},
+		{"space/enter", "toggle item"},
+		{"a", "select all cleanable"},
+		{"d/c", "move selected to Trash"},
+		{"D", "delete Time Machine local snapshots"},
+		{"w", "locations that could not be measured"},
+		{"f", "open Full Disk Access settings (in warnings)"},
+		{"r", "rescan"},
+	}},
+	ViewThemeEditor: {"Theme Editor", []KeyHelp{
+		{"j/k ↑/↓", "move"},
+		{"h/l", "change base theme"},
+		{"enter/space", "edit color"},
+		{"s", "save theme"},
+	}},
+	ViewQuickScan: {"Space Overview", []KeyHelp{
+		{"j/k ↑/↓", "move"},
+		{"r", "rescan"},
+	}},
+	ViewEmptyTrash: {"Empty Trash", []KeyHelp{
+		{"d/c/enter", "empty the Trash (asks first)"},
+		{"f", "open Full Disk Access settings (Trash unreadable)"},
+		{"r", "measure again"},
+	}},
+}
+
+// helpOverlay renders the key reference for view, with any extra bindings
+// from keys.json
+func helpOverlay(view ViewType, width, height int) string {
+	help, ok := viewHelp[view]
+	if !ok {
+		return ""
+	}
+
+	keyStyle := lipgloss.NewStyle().Foreground(PrimaryColor).Bold(true)
+	row := func(key, desc string) string {
+		return "  " + keyStyle.Render(padRight(key, 14)) + " " + desc + "\n"
+	}
+
+	var b strings.Builder
+	b.WriteString(TitleStyle.Render(help.title + " keys"))
+	b.WriteString("\n\n")
+	for _, k := range help.keys {
+		b.WriteString(row(k.Key, k.Desc))
+	}
+
+	b.WriteString("\n")
+	b.WriteString(SubtitleStyle.Render("Everywhere"))
+	b.WriteString("\n")
+	b.WriteString(row("?", "this help"))
+	b.WriteString(row("esc", "back, cancel"))
+	b.WriteString(row("q/ctrl+c", "quit"))
+
+	if aliases := boundKeys(); len(aliases) > 0 {
+		b.WriteString("\n")
+		b.WriteString(SubtitleStyle.Render("Your key bindings (keys.json)"))
+		b.WriteString("\n")
+		for _, a := range aliases {
+			b.WriteString(row(a.Key, a.Desc))
+		}
+	}
+
+	box := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(PrimaryColor).
+		Padding(1, 2)
+	return Center(width, height, box.Render(strings.TrimRight(b.String(), "\n"))+"\n\n"+DimStyle.Render("Press any key to close"))
+}
+
+// boundKeys lists the extra keys from keys.json with the action each one
+// triggers, sorted by key
+func boundKeys() []KeyHelp {
+	desc := make(map[string]string)
+	for _, a := range keyActions {
+		desc[a.Key] = fmt.Sprintf("%s (like %s)", a.Desc, keyName(a.Key))
+	}
+
+	var keys []KeyHelp
+	for key, target := range keyAliases {
+		keys = append(keys, KeyHelp{Key: key, Desc: desc[target]})
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].Key < keys[j].Key
+	})
+	return keys
+}
+
+// keyName is how a built-in key is written in help text
+func keyName(key string) string {
+	if key == " " {
+		return "space"
+	}
+	return key
+}
@@ -0,0 +1,137 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/Tyooughtul/lume/pkg/scanner"
+)
+
+// keyAction is a user-facing action and the built-in key the views
+// handle it by. Extra keys from keys.json are translated to that key
+// before a view sees them, so the views keep matching their own keys.
+type keyAction struct {
+	Name string
+	Key  string
+	Desc string
+}
+
+// keyActions are the actions keys.json can bind, in help order
+var keyActions = []keyAction{
+	{"up", "k", "move up"},
+	{"down", "j", "move down"},
+	{"select", "enter", "open / confirm"},
+	{"toggle", " ", "toggle selection"},
+	{"selectAll", "a", "select all / none"},
+	{"clean", "d", "clean selected"},
+	{"detail", "e", "look inside"},
+	{"preview", "p", "preview"},
+	{"reveal", "o", "reveal in Finder"},
+	{"filter", "/", "filter"},
+	{"sort", "s", "sort"},
+	{"export", "x", "export CSV"},
+	{"refresh", "r", "refresh"},
+	{"back", "esc", "back"},
+	{"quit", "q", "quit"},
+}
+
+// builtinKeys are the keys some view already handles, y and n answering
+// confirmations among them. keys.json may not rebind them: the key would
+// change meaning in every view, not just where its action lives.
+var builtinKeys = map[string]bool{
+	"up": true, "down": true, "left": true, "right": true,
+	"enter": true, "esc": true, " ": true, "tab": true, "shift+tab": true,
+	"backspace": true, "ctrl+c": true, "?": true,
+	"a": true, "c": true, "d": true, "e": true, "f": true, "g": true,
+	"h": true, "i": true, "j": true, "k": true, "l": true, "m": true,
+	"n": true, "o": true, "p": true, "q": true, "r": true, "s": true,
+	"t": true, "u": true, "v": true, "w": true, "x": true, "y": true,
+	"D": true, "L": true, "N": true, "R": true, "S": true, "Y": true,
+	"/": true, "+": true, "-": true, "=": true,
+	"1": true, "2": true, "3": true, "4": true,
+}
+
+// keyAliases maps each extra key from keys.json to the built-in key it
+// stands for
+var keyAliases map[string]string
+
+// keymapWarnings lists problems with keys.json, shown on the main menu
+var keymapWarnings []string
+
+// LoadKeymap reads the extra key bindings from ~/.config/lume/keys.json.
+// Bindings add keys; the built-in ones keep working. An unknown action, a
+// key some view already uses, or a key given to two actions, is skipped
+// with a warning.
+func LoadKeymap() {
+	bindings, err := scanner.LoadKeyBindings()
+	if err != nil {
+		keymapWarnings = []string{fmt.Sprintf("key bindings ignored: %v", err)}
+		return
+	}
+	keyAliases, keymapWarnings = buildKeyAliases(bindings)
+}
+
+func buildKeyAliases(bindings map[string][]string) (map[string]string, []string) {
+	byName := make(map[string]keyAction)
+	for _, a := range keyActions {
+		byName[a.Name] = a
+	}
+
+	// Sorted so which of two clashing bindings wins does not vary
+	names := make([]string, 0, len(bindings))
+	for name := range bindings {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	aliases := make(map[string]string)
+	boundTo := make(map[string]string)
+	var warnings []string
+	for _, name := range names {
+		action, ok := byName[name]
+		if !ok {
+			warnings = append(warnings, fmt.Sprintf("keys.json: unknown action %q", name))
+			continue
+		}
+		for _, key := range bindings[name] {
+			if key == "" || key == action.Key {
+				continue
+			}
+			if builtinKeys[key] {
+				warnings = append(warnings, fmt.Sprintf("keys.json: %q is a built-in key; not binding it to %s", keyName(key), name))
+				continue
+			}
+			if other, taken := boundTo[key]; taken {
+				warnings = append(warnings, fmt.Sprintf("keys.json: %q is bound to both %s and %s; keeping %s", key, other, name, other))
+				continue
+			}
+			boundTo[key] = name
+			aliases[key] = action.Key
+		}
+	}
+	return aliases, warnings
+}
+
+// translateKey turns a key bound in keys.json into the built-in key of its
+// action; every other key is returned as is
+func translateKey(msg tea.KeyMsg) tea.KeyMsg {
+	if target, ok := keyAliases[msg.String()]; ok {
+		return keyMsgFor(target)
+	}
+	return msg
+}
+
+// keyMsgFor builds the key press the views expect for a built-in key
+func keyMsgFor(key string) tea.KeyMsg {
+	switch key {
+	case "enter":
+		return tea.KeyMsg{Type: tea.KeyEnter}
+	case "esc":
+		return tea.KeyMsg{Type: tea.KeyEsc}
+	case " ":
+		return tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}}
+	}
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)}
+}
@@ -1,17 +1,16 @@
 package ui
 
 import (
+	"context"
 	"fmt"
-	"os/exec"
+	"os"
 	"path/filepath"
 	"sort"
-	"strconv"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
-	"github.com/dustin/go-humanize"
 
 	"github.com/Tyooughtul/lume/pkg/cleaner"
 	"github.com/Tyooughtul/lume/pkg/scanner"
@@ -23,15 +22,23 @@ type LargeFilesView struct {
 	scrollOffset int
 	scanning     bool
 	cleaning     bool
+	quitArmed    bool
 	confirming   bool
 	spinner      spinner.Model
 	width        int
 	height       int
+	list         *listArea // where View last drew the list, for mouse clicks
 	rootPath     string
 	minSize      int64
 	cleanedSize  int64
+	diskBefore   diskReading
+	diskAfter    diskReading
 	resultCh     chan largeScanResult
+	cancelScan   context.CancelFunc
 	selected     map[int]bool
+	onlySelected bool
+	extGroups    []scanner.ExtensionGroup // the scan result by file type, largest first
+	extGroup     int                      // index into extGroups being shown, -1 for all types
 	err          error
 }
 
@@ -52,7 +59,9 @@ func NewLargeFilesView() *LargeFilesView {
 		rootPath: homeDir,
 		minSize:  50 * 1024 * 1024,
 		resultCh: make(chan largeScanResult, 1),
+		list:     &listArea{},
 		selected: make(map[int]bool),
+		extGroup: -1,
 	}
 }
 
@@ -67,10 +76,14 @@ func (m *LargeFilesView) startScan() tea.Cmd {
 	m.scanning = true
 	m.files = []scanner.FileInfo{}
 	m.selected = make(map[int]bool)
+	m.onlySelected = false
+	m.extGroups = nil
+	m.extGroup = -1
+	ctx := newScanContext(&m.cancelScan)
 
 	go func() {
-		files := m.scanWithFind()
-		m.resultCh <- largeScanResult{files: files, err: nil}
+		files := m.scanWithFind(ctx)
+		m.resultCh <- largeScanResult{files: files, err: ctx.Err()}
 	}()
 
 	return func() tea.Msg {
@@ -78,11 +91,14 @@ func (m *LargeFilesView) startScan() tea.Cmd {
 	}
 }
 
-func (m *LargeFilesView) scanWithFind() []scanner.FileInfo {
+func (m *LargeFilesView) scanWithFind(ctx context.Context) []scanner.FileInfo {
 	var results []scanner.FileInfo
 
+	// -print0 keeps names with spaces or newlines in one piece; sizes come
+	// from Lstat rather than parsing ls output
 	sizeArg := fmt.Sprintf("+%dc", m.minSize)
-	cmd := exec.Command("find", m.rootPath, "-not", "-path", "*/.Trash/*", "-type", "f", "-size", sizeArg, "-exec", "ls", "-ln", "{}", "+")
+	trash := scanner.CurrentPlatform.TrashDir(scanner.GetRealHomeDir())
+	cmd := scanner.ScanCommand(ctx, "find", scanner.FindRoot(m.rootPath), "-not", "-path", "*/.Trash/*", "-not", "-path", trash+"/*", "-type", "f", "-size", sizeArg, "-print0")
 	output, err := cmd.Output()
 	if err != nil {
 		if len(output) == 0 {
@@ -91,23 +107,21 @@ func (m *LargeFilesView) scanWithFind() []scanner.FileInfo {
 		// Partial results from permission errors, continue
 	}
 
-	lines := strings.Split(string(output), "\n")
-	for _, line := range lines {
-		fields := strings.Fields(line)
-		if len(fields) < 9 {
+	for _, path := range strings.Split(string(output), "\x00") {
+		if path == "" || ctx.Err() != nil {
 			continue
 		}
 
-		size, _ := strconv.ParseInt(fields[4], 10, 64)
-		if size < m.minSize {
+		info, err := os.Lstat(path)
+		if err != nil || !info.Mode().IsRegular() || info.Size() < m.minSize {
 			continue
 		}
 
-		path := strings.Join(fields[8:], " ")
 		results = append(results, scanner.FileInfo{
-			Path: path,
-			Name: filepath.Base(path),
-			Size: size,
+			Path:     path,
+			Name:     filepath.Base(path),
+			Size:     info.Size(),
+			Modified: info.ModTime(),
 		})
 	}
 
@@ -125,28 +139,38 @@ func (m *LargeFilesView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.height = msg.Height
 		m.updateScrollOffset()
 
+	case tea.MouseMsg:
+		return m.handleMouse(msg)
+
 	case tea.KeyMsg:
 		if m.confirming {
-			switch msg.String() {
-			case "y", "Y":
-				m.confirming = false
-				return m, m.startClean()
-			case "n", "N", "esc":
+			if done, confirmed := confirmKey(msg); done {
 				m.confirming = false
+				if confirmed {
+					return m, m.startClean()
+				}
 			}
 			return m, nil
 		}
 
-		if m.scanning || m.cleaning {
+		if m.cleaning {
+			return m, busyKey(msg, &m.quitArmed)
+		}
+
+		if m.scanning {
 			switch msg.String() {
 			case "q", "ctrl+c":
+				stopScan(&m.cancelScan)
 				return m, tea.Quit
 			case "esc":
+				stopScan(&m.cancelScan)
 				return m, func() tea.Msg { return BackToMenuMsg{} }
 			}
 			return m, nil
 		}
 
+		visible := m.visibleFiles()
+
 		switch msg.String() {
 		case "q", "ctrl+c":
 			return m, tea.Quit
@@ -158,21 +182,58 @@ func (m *LargeFilesView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			m.updateScrollOffset()
 		case "down", "j":
-			if m.cursor < len(m.files)-1 {
+			if m.cursor < len(visible)-1 {
 				m.cursor++
 			}
 			m.updateScrollOffset()
 		case " ", "enter":
-			if len(m.files) > 0 && m.cursor < len(m.files) {
-				m.selected[m.cursor] = !m.selected[m.cursor]
+			if m.cursor < len(visible) {
+				idx := visible[m.cursor]
+				m.selected[idx] = !m.selected[idx]
+				m.clampCursor()
 			}
 		case "a":
-			allSelected := len(m.selected) == len(m.files)
-			m.selected = make(map[int]bool)
-			if !allSelected {
-				for i := range m.files {
-					m.selected[i] = true
+			allSelected := true
+			for _, idx := range visible {
+				if !m.selected[idx] {
+					allSelected = false
+					break
+				}
+			}
+			for _, idx := range visible {
+				m.selected[idx] = !allSelected
+			}
+			m.clampCursor()
+		case "v":
+			current := -1
+			if m.cursor < len(visible) {
+				current = visible[m.cursor]
+			}
+			m.onlySelected = !m.onlySelected
+			m.cursor = 0
+			for i, idx := range m.visibleFiles() {
+				if idx == current {
+					m.cursor = i
+					break
+				}
+			}
+			m.updateScrollOffset()
+		case "o":
+			if m.cursor < len(visible) {
+				return m, revealInFinder(m.files[visible[m.cursor]].Path)
+			}
+		case "p":
+			if m.cursor < len(visible) {
+				return m, quickLook(m.files[visible[m.cursor]].Path)
+			}
+		case "f":
+			if len(m.extGroups) > 0 {
+				m.extGroup++
+				if m.extGroup >= len(m.extGroups) {
+					m.extGroup = -1
 				}
+				m.cursor = 0
+				m.scrollOffset = 0
 			}
 		case "d", "c":
 			hasSelected := false
@@ -190,8 +251,13 @@ func (m *LargeFilesView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 	case largeScanResult:
+		if scanCanceled(msg.err) {
+			// A scan stopped by leaving the view; a newer one may be running
+			return m, nil
+		}
 		m.scanning = false
 		m.files = msg.files
+		m.extGroups = scanner.GroupByExtension(msg.files)
 		m.err = msg.err
 		if m.cursor >= len(m.files) {
 			m.cursor = 0
@@ -203,10 +269,15 @@ func (m *LargeFilesView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.err = msg.err
 		if msg.size > 0 {
 			m.cleanedSize = msg.size
-			return m, tea.Batch(m.startScan(), RecordSnapshot(0, 0, msg.size, "large_files", msg.details))
+			m.diskBefore, m.diskAfter = msg.before, msg.after
+			return m, tea.Batch(m.startScan(), RecordSnapshot(msg.after.total, msg.after.used, msg.size, "large_files", msg.details))
 		}
 		return m, m.startScan()
 
+	case finderResultMsg:
+		m.err = msg.err
+		return m, nil
+
 	case BackToMenuMsg:
 		return NewMainMenu(), nil
 	}
@@ -216,13 +287,36 @@ func (m *LargeFilesView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, cmd
 }
 
+// visibleFiles returns indices into m.files that are currently listed
+func (m LargeFilesView) visibleFiles() []int {
+	visible := make([]int, 0, len(m.files))
+	for i := range m.files {
+		if m.onlySelected && !m.selected[i] {
+			continue
+		}
+		if m.extGroup >= 0 && scanner.FileExtension(m.files[i].Name) != m.extGroups[m.extGroup].Ext {
+			continue
+		}
+		visible = append(visible, i)
+	}
+	return visible
+}
+
+// clampCursor keeps the cursor inside the visible list after it shrinks
+func (m *LargeFilesView) clampCursor() {
+	if n := len(m.visibleFiles()); m.cursor >= n {
+		m.cursor = max(n-1, 0)
+	}
+	m.updateScrollOffset()
+}
+
 func (m *LargeFilesView) updateScrollOffset() {
 	maxDisplay := MaxListItems
 	if m.height > 20 {
-		maxDisplay = m.height - 12
+		maxDisplay = m.height - 14
 	}
-	if len(m.files) < maxDisplay {
-		maxDisplay = len(m.files)
+	if n := len(m.visibleFiles()); n < maxDisplay {
+		maxDisplay = n
 	}
 	if m.cursor < m.scrollOffset {
 		m.scrollOffset = m.cursor
@@ -232,11 +326,32 @@ func (m *LargeFilesView) updateScrollOffset() {
 	}
 }
 
+// handleMouse scrolls with the wheel and moves the cursor to a clicked row;
+// clicking the row already under the cursor toggles it
+func (m *LargeFilesView) handleMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
+	if key, ok := wheelKey(msg); ok {
+		return m.Update(key)
+	}
+	if m.confirming || m.cleaning || m.scanning {
+		return m, nil
+	}
+	if row := clickedRow(msg, m.list); row >= 0 {
+		i := m.scrollOffset + row
+		if i == m.cursor {
+			return m.Update(spaceKey)
+		}
+		m.cursor = i
+	}
+	return m, nil
+}
+
 func (m *LargeFilesView) startClean() tea.Cmd {
 	m.cleaning = true
+	m.quitArmed = false
 
 	return func() tea.Msg {
 		c := cleaner.NewCleaner()
+		before := sampleDisk()
 
 		var selected []scanner.FileInfo
 		count := 0
@@ -252,7 +367,7 @@ func (m *LargeFilesView) startClean() tea.Cmd {
 		if count > 0 {
 			details = fmt.Sprintf("%d large files", count)
 		}
-		return cleanResultMsg{size: size, err: err, details: details}
+		return cleanResultMsg{size: size, err: err, details: details, before: before, after: sampleDisk()}
 	}
 }
 
@@ -260,13 +375,14 @@ func (m LargeFilesView) View() string {
 	if m.width == 0 {
 		return "Loading..."
 	}
+	m.list.rows = 0
 
 	var b strings.Builder
 
 	b.WriteString(PageHeader("", "Large Files", m.width))
 	b.WriteString("\n")
 	b.WriteString("  ")
-	b.WriteString(DimStyle.Render(fmt.Sprintf("Scanning: %s (>%s)", m.rootPath, humanize.Bytes(uint64(m.minSize)))))
+	b.WriteString(DimStyle.Render(fmt.Sprintf("Scanning: %s (>%s)", displayPath(m.rootPath), FormatBytes(uint64(m.minSize)))))
 	b.WriteString("\n\n")
 
 	if m.scanning {
@@ -280,6 +396,7 @@ func (m LargeFilesView) View() string {
 		b.WriteString(fmt.Sprintf("  %s Deleting selected files...\n", m.spinner.View()))
 		b.WriteString("\n")
 		b.WriteString("  Moving files to Trash...\n")
+		b.WriteString(busyHint(m.quitArmed))
 		return Center(m.width, m.height, b.String())
 	}
 
@@ -289,9 +406,41 @@ func (m LargeFilesView) View() string {
 		b.WriteString("\n")
 	}
 
+	if m.cleanedSize > 0 {
+		b.WriteString("  ")
+		b.WriteString(SuccessStyle.Render(fmt.Sprintf("[ok] Cleaned %s", FormatBytes(uint64(m.cleanedSize)))))
+		b.WriteString("\n")
+		b.WriteString(DiskBeforeAfter(m.diskBefore, m.diskAfter, 30))
+		b.WriteString("\n")
+	}
+
+	visible := m.visibleFiles()
+
+	if len(m.extGroups) > 0 {
+		b.WriteString(m.typeSummary())
+		b.WriteString("\n\n")
+	}
+
+	if m.extGroup >= 0 {
+		g := m.extGroups[m.extGroup]
+		b.WriteString("  ")
+		b.WriteString(AccentStyle.Render(fmt.Sprintf("Showing %s only: %d files, %s", extLabel(g.Ext), g.Count, FormatBytes(uint64(g.Size)))))
+		b.WriteString(DimStyle.Render(" (f for the next type)"))
+		b.WriteString("\n\n")
+	}
+
+	if m.onlySelected {
+		b.WriteString("  ")
+		b.WriteString(AccentStyle.Render("Showing selected items only"))
+		b.WriteString(DimStyle.Render(" (v to show all)"))
+		b.WriteString("\n\n")
+	}
+
 	if len(m.files) == 0 {
-		b.WriteString(fmt.Sprintf("  No files larger than %s found.\n", humanize.Bytes(uint64(m.minSize))))
+		b.WriteString(fmt.Sprintf("  No files larger than %s found.\n", FormatBytes(uint64(m.minSize))))
 		b.WriteString("\n  Your home directory is clean!\n")
+	} else if len(visible) == 0 {
+		b.WriteString("  Nothing selected.\n")
 	} else {
 		b.WriteString("  ")
 		b.WriteString(TableHeader([]string{"", "Filename", "Size"}, []int{3, 36, 12}))
@@ -302,18 +451,19 @@ func (m LargeFilesView) View() string {
 
 		maxDisplay := MaxListItems
 		if m.height > 20 {
-			maxDisplay = m.height - 12
+			maxDisplay = m.height - 14
 		}
-		if len(m.files) < maxDisplay {
-			maxDisplay = len(m.files)
+		if len(visible) < maxDisplay {
+			maxDisplay = len(visible)
 		}
 
-		for i := m.scrollOffset; i < m.scrollOffset+maxDisplay && i < len(m.files); i++ {
-			file := m.files[i]
-			cb := Checkbox(m.selected[i])
+		m.list.markRows(&b, min(maxDisplay, len(visible)-m.scrollOffset))
+		for i := m.scrollOffset; i < m.scrollOffset+maxDisplay && i < len(visible); i++ {
+			file := m.files[visible[i]]
+			cb := Checkbox(m.selected[visible[i]])
 
 			name := padRight(truncate(file.Name, 36), 36)
-			sizeStr := padLeft(humanize.Bytes(uint64(file.Size)), 12)
+			sizeStr := padLeft(FormatBytes(uint64(file.Size)), 12)
 
 			line := fmt.Sprintf("  %s %s %s", cb, name, sizeStr)
 
@@ -326,7 +476,7 @@ func (m LargeFilesView) View() string {
 			b.WriteString("\n")
 		}
 
-		above, below := ScrollIndicator(m.scrollOffset, len(m.files), maxDisplay)
+		above, below := ScrollIndicator(m.scrollOffset, len(visible), maxDisplay)
 		if above != "" {
 			b.WriteString("  ")
 			b.WriteString(above)
@@ -350,7 +500,7 @@ func (m LargeFilesView) View() string {
 		b.WriteString("\n")
 		stats := StatsBar([]string{
 			fmt.Sprintf("Total: %d files", len(m.files)),
-			fmt.Sprintf("Selected: %s (%d)", humanize.Bytes(uint64(selectedSize)), selectedCount),
+			fmt.Sprintf("Selected: %s (%d)", FormatBytes(uint64(selectedSize)), selectedCount),
 		})
 		b.WriteString(stats)
 	}
@@ -359,27 +509,63 @@ func (m LargeFilesView) View() string {
 	if m.confirming {
 		selectedSize := int64(0)
 		selectedCount := 0
+		var paths []string
 		for i, file := range m.files {
 			if m.selected[i] {
 				selectedSize += file.Size
 				selectedCount++
+				paths = append(paths, file.Path)
 			}
 		}
-		b.WriteString("  " + WarningStyle.Render(fmt.Sprintf("Move %d files (%s) to Trash?", selectedCount, humanize.Bytes(uint64(selectedSize)))))
-		b.WriteString("\n\n")
-		b.WriteString(StyledHelpBar([]KeyHelp{
-			{Key: "y", Desc: "confirm"},
-			{Key: "n/esc", Desc: "cancel"},
-		}))
+		b.WriteString(syncedDataWarning(paths))
+		b.WriteString(ConfirmDialog(fmt.Sprintf("Move %d files (%s) to Trash?", selectedCount, FormatBytes(uint64(selectedSize)))))
 	} else {
 		b.WriteString(StyledHelpBar([]KeyHelp{
 			{Key: "j/k", Desc: "navigate"},
 			{Key: "space", Desc: "toggle"},
 			{Key: "a", Desc: "all"},
+			{Key: "v", Desc: "selected"},
+			{Key: "f", Desc: "file type"},
+			{Key: "o", Desc: "reveal"},
+			{Key: "p", Desc: "quick look"},
 			{Key: "d", Desc: "delete"},
 			{Key: "r", Desc: "refresh"},
 		}))
 	}
 
-	return Center(m.width, m.height, b.String())
+	return m.list.center(m.width, m.height, b.String())
+}
+
+// maxTypeSummary is how many file types the summary line names
+const maxTypeSummary = 5
+
+// typeSummary is the line totalling the scan by file type, e.g.
+// ".mov 12 files 40 GB", with the type being shown highlighted
+func (m LargeFilesView) typeSummary() string {
+	parts := make([]string, 0, maxTypeSummary+1)
+	for i, g := range m.extGroups {
+		if i == maxTypeSummary {
+			parts = append(parts, DimStyle.Render(fmt.Sprintf("+%d more", len(m.extGroups)-i)))
+			break
+		}
+		files := "files"
+		if g.Count == 1 {
+			files = "file"
+		}
+		part := fmt.Sprintf("%s %d %s %s", extLabel(g.Ext), g.Count, files, FormatBytes(uint64(g.Size)))
+		if i == m.extGroup {
+			parts = append(parts, AccentStyle.Render(part))
+		} else {
+			parts = append(parts, DimStyle.Render(part))
+		}
+	}
+	return "  " + strings.Join(parts, DimStyle.Render("  ·  "))
+}
+
+// extLabel names an extension for display
+func extLabel(ext string) string {
+	if ext == "" {
+		return "(no extension)"
+	}
+	return ext
 }
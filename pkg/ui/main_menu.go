@@ -1,6 +1,7 @@
 package ui
 
 import (
+	"context"
 	"fmt"
 	"os/exec"
 	"strconv"
@@ -10,7 +11,9 @@ import (
 	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
-	"github.com/dustin/go-humanize"
+
+	"github.com/Tyooughtul/lume/pkg/cleaner"
+	"github.com/Tyooughtul/lume/pkg/scanner"
 )
 
 // GarbageTruckTickMsg 垃圾车动画 tick
@@ -41,6 +44,11 @@ const (
 	ViewBrowserData
 	ViewDiskTrend
 	ViewZombieHunter
+	ViewDiskAnalyzer
+	ViewSystemData
+	ViewThemeEditor
+	ViewQuickScan
+	ViewEmptyTrash
 )
 
 type MainMenu struct {
@@ -49,11 +57,21 @@ type MainMenu struct {
 	spinner    spinner.Model
 	diskTotal  uint64
 	diskUsed   uint64
+	trashSize  int64 // du of ~/.Trash, -1 until measured or when unreadable
+	junkSize   int64 // Reclaimable System Junk, -1 until measured
+	zombieSize int64 // Large zombie files, -1 until measured
+	fda        fullDiskAccessMsg
+	volumes    []scanner.Volume // mounted disks, the startup disk first
+	volume     int              // index into volumes the disk bar shows
 	width      int
 	height     int
 	err        error
 	ThemeNotif string // transient theme-switch notification
 	
+	// The health score's scans while they run, to cancel or not start twice
+	cancelJunk   context.CancelFunc
+	cancelZombie context.CancelFunc
+
 	// 垃圾车 idle 动画
 	garbageTruck *GarbageTruckAnimation
 }
@@ -67,25 +85,56 @@ func NewMainMenu() *MainMenu {
 		items: []MenuItem{
 			{Name: "System Junk", Description: "Clean system cache and logs", Icon: "*", View: ViewSystemJunk},
 			{Name: "Large Files", Description: "Find large files", Icon: "*", View: ViewLargeFiles},
+			{Name: "Disk Analyzer", Description: "Explore where space goes", Icon: "*", View: ViewDiskAnalyzer},
+			{Name: "Space Overview", Description: "What's using my space right now", Icon: "*", View: ViewQuickScan},
+			{Name: "System Data", Description: "Inspect hidden system storage", Icon: "*", View: ViewSystemData},
 			{Name: "Zombie Hunter", Description: "Find cold files", Icon: "*", View: ViewZombieHunter},
 			{Name: "App Uninstaller", Description: "Uninstall apps completely", Icon: "*", View: ViewAppUninstaller},
 			{Name: "Duplicate Files", Description: "Find duplicate files", Icon: "*", View: ViewDuplicates},
 			{Name: "Browser Data", Description: "Clean browser cache", Icon: "*", View: ViewBrowserData},
 			{Name: "Disk Trend", Description: "View disk usage history", Icon: "*", View: ViewDiskTrend},
+			{Name: "Empty Trash", Description: "Reclaim the space cleaned files still use", Icon: "*", View: ViewEmptyTrash},
 		},
 		spinner:      s,
+		trashSize:    -1,
+		junkSize:     -1,
+		zombieSize:   -1,
 		garbageTruck: NewGarbageTruckAnimation(),
 	}
 }
 
-func (m MainMenu) Init() tea.Cmd {
+func (m *MainMenu) Init() tea.Cmd {
 	return tea.Batch(
 		m.spinner.Tick,
 		getDiskInfo(),
+		getVolumes(),
+		getTrashUsage(),
+		m.healthScans(),
+		checkFullDiskAccess(),
 		GarbageTruckTick(),
 	)
 }
 
+// healthScans starts the health score's junk and zombie scans for the
+// figures still missing, skipping any scan that is already running
+func (m *MainMenu) healthScans() tea.Cmd {
+	var cmds []tea.Cmd
+	if m.junkSize < 0 && m.cancelJunk == nil {
+		cmds = append(cmds, getJunkSize(newScanContext(&m.cancelJunk)))
+	}
+	if m.zombieSize < 0 && m.cancelZombie == nil {
+		cmds = append(cmds, getZombieSize(newScanContext(&m.cancelZombie)))
+	}
+	return tea.Batch(cmds...)
+}
+
+// stopHealthScans cancels the health score's running scans, killing their
+// du and find processes
+func (m *MainMenu) stopHealthScans() {
+	stopScan(&m.cancelJunk)
+	stopScan(&m.cancelZombie)
+}
+
 func (m *MainMenu) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
@@ -95,6 +144,7 @@ func (m *MainMenu) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.KeyMsg:
 		switch msg.String() {
 		case "q", "ctrl+c":
+			m.stopHealthScans()
 			return m, tea.Quit
 		case "up", "k":
 			if m.cursor > 0 {
@@ -108,11 +158,49 @@ func (m *MainMenu) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, func() tea.Msg {
 				return MenuSelectedMsg{View: m.items[m.cursor].View}
 			}
+		case "e":
+			return m, func() tea.Msg {
+				return MenuSelectedMsg{View: ViewThemeEditor}
+			}
+		case "u":
+			// For this session; binaryUnits in the config sets the default
+			SetBinaryUnits(!binaryUnits)
+		case "v":
+			if len(m.volumes) > 1 {
+				m.volume = (m.volume + 1) % len(m.volumes)
+			}
+		case "f":
+			if m.fda == fdaMissing {
+				return m, openFullDiskAccessSettings()
+			}
 		}
 
 	case diskInfoMsg:
 		m.diskTotal = msg.total
 		m.diskUsed = msg.used
+
+	case volumesMsg:
+		m.volumes = msg
+		if m.volume >= len(m.volumes) {
+			m.volume = 0
+		}
+
+	case trashUsageMsg:
+		m.trashSize = int64(msg)
+
+	case junkSizeMsg:
+		m.junkSize = int64(msg)
+		stopScan(&m.cancelJunk)
+
+	case zombieSizeMsg:
+		m.zombieSize = int64(msg)
+		stopScan(&m.cancelZombie)
+
+	case fullDiskAccessMsg:
+		m.fda = msg
+
+	case finderResultMsg:
+		m.err = msg.err
 	
 	case GarbageTruckTickMsg:
 		m.garbageTruck.Update()
@@ -126,6 +214,9 @@ func (m *MainMenu) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 // getMenuItemColors returns colors based on current theme
 func getMenuItemColors() []lipgloss.Color {
+	if noColor {
+		return []lipgloss.Color{""}
+	}
 	if GlobalThemeManager == nil {
 		return []lipgloss.Color{
 			lipgloss.Color("#ff5f87"),
@@ -171,7 +262,12 @@ func (m MainMenu) View() string {
 			// Selected: highlighted name + > cursor
 			coloredName := lipgloss.NewStyle().Foreground(colors[ci]).Bold(true).Render(name)
 			line := " >  " + coloredName + "  " + desc
-			b.WriteString(SelectedScanItemStyle.Render(padRightAnsi(line, ContentWidth)))
+			if noColor {
+				// The > is all the highlight there is
+				b.WriteString(line)
+			} else {
+				b.WriteString(SelectedScanItemStyle.Render(padRightAnsi(line, ContentWidth)))
+			}
 		} else {
 			// Unselected: colored name
 			coloredName := lipgloss.NewStyle().Foreground(colors[ci]).Render(name)
@@ -183,14 +279,28 @@ func (m MainMenu) View() string {
 
 	b.WriteString("\n")
 
-	// Disk usage
-	if m.diskTotal > 0 {
+	// Disk usage; health and Trash figures are about the startup disk, so
+	// another volume only gets its bar
+	if v, ok := m.otherVolume(); ok {
+		b.WriteString(m.renderVolumeBar(v))
+		b.WriteString("\n")
+	} else if m.diskTotal > 0 {
 		b.WriteString(m.renderDiskBar())
 		b.WriteString("\n")
+		b.WriteString(m.renderHealth())
+		b.WriteString("\n")
+	}
+	if fda := m.renderFullDiskAccess(); fda != "" {
+		b.WriteString(fda)
+		b.WriteString("\n")
+	}
+	if m.err != nil {
+		b.WriteString("   " + ErrorStyle.Render(m.err.Error()))
+		b.WriteString("\n")
 	}
 
 	// 垃圾车 idle 动画
-	if m.width >= 60 {
+	if m.width >= 60 && !noColor {
 		b.WriteString("\n")
 		truckAnim := m.garbageTruck.Draw(m.width - 4)
 		if truckAnim != "" {
@@ -200,12 +310,38 @@ func (m MainMenu) View() string {
 	}
 
 	b.WriteString("\n")
-	b.WriteString(StyledHelpBar([]KeyHelp{
+	help := []KeyHelp{
 		{"j/k", "navigate"},
 		{"enter", "select"},
 		{"t", "theme"},
-		{"q", "quit"},
-	}))
+		{"e", "edit theme"},
+		{"u", "units: " + unitsName()},
+	}
+	if len(m.volumes) > 1 {
+		help = append(help, KeyHelp{"v", fmt.Sprintf("disk %d/%d", m.volume+1, len(m.volumes))})
+	}
+	if m.fda == fdaMissing {
+		help = append(help, KeyHelp{"f", "grant access"})
+	}
+	help = append(help, KeyHelp{"?", "all keys"}, KeyHelp{"q", "quit"})
+	b.WriteString(StyledHelpBar(help))
+
+	if len(keymapWarnings) > 0 {
+		b.WriteString("\n\n")
+		b.WriteString(WarningStyle.Render("! " + truncate(keymapWarnings[0], ContentWidth-2)))
+		if len(keymapWarnings) > 1 {
+			b.WriteString(DimStyle.Render(fmt.Sprintf("\n  and %d more key binding warnings", len(keymapWarnings)-1)))
+		}
+	}
+
+	if GlobalThemeManager != nil && len(GlobalThemeManager.Warnings) > 0 {
+		warnings := GlobalThemeManager.Warnings
+		b.WriteString("\n\n")
+		b.WriteString(WarningStyle.Render("! " + truncate(warnings[0], ContentWidth-2)))
+		if len(warnings) > 1 {
+			b.WriteString(DimStyle.Render(fmt.Sprintf("\n  and %d more theme warnings", len(warnings)-1)))
+		}
+	}
 
 	if m.ThemeNotif != "" {
 		notifColor := AccentColor
@@ -230,16 +366,177 @@ func (m MainMenu) renderDiskBar() string {
 	bar := ProgressBar(usedPercent, barWidth, DangerColor, SecondaryColor)
 	pct := fmt.Sprintf(" %.1f%%", usedPercent)
 
-	usedStr := humanize.Bytes(m.diskUsed)
-	totalStr := humanize.Bytes(m.diskTotal)
-	freeStr := humanize.Bytes(m.diskTotal - m.diskUsed)
+	usedStr := FormatBytes(m.diskUsed)
+	totalStr := FormatBytes(m.diskTotal)
+	freeStr := FormatBytes(m.diskTotal - m.diskUsed)
 
-	info := StatsLine([]string{
+	stats := []string{
 		fmt.Sprintf("Disk: %s / %s", usedStr, totalStr),
 		fmt.Sprintf("Free: %s", freeStr),
-	})
+	}
+	if m.trashSize >= 0 {
+		stats = append(stats, fmt.Sprintf("Trash: %s", FormatBytes(uint64(m.trashSize))))
+	}
+
+	out := "   " + bar + pct + "\n   " + StatsLine(stats)
+	if m.trashSize >= trashWarnSize {
+		out += "\n   " + WarningStyle.Render(fmt.Sprintf("! The Trash holds %s that still counts as used", FormatBytes(uint64(m.trashSize)))) +
+			DimStyle.Render(" (Empty Trash frees it)")
+	}
+	return out
+}
+
+// otherVolume returns the volume the disk bar shows when it is not the
+// startup disk
+func (m MainMenu) otherVolume() (scanner.Volume, bool) {
+	if m.volume > 0 && m.volume < len(m.volumes) {
+		return m.volumes[m.volume], true
+	}
+	return scanner.Volume{}, false
+}
+
+// renderVolumeBar is renderDiskBar for a volume other than the startup disk
+func (m MainMenu) renderVolumeBar(v scanner.Volume) string {
+	usedPercent := float64(v.Used) / float64(v.Total) * 100
+	bar := ProgressBar(usedPercent, 40, DangerColor, SecondaryColor)
+	pct := fmt.Sprintf(" %.1f%%", usedPercent)
 
-	return "   " + bar + pct + "\n   " + info
+	stats := []string{
+		fmt.Sprintf("%s: %s / %s", v.Name, FormatBytes(v.Used), FormatBytes(v.Total)),
+		fmt.Sprintf("Free: %s", FormatBytes(v.Total-v.Used)),
+	}
+	return "   " + bar + pct + "\n   " + StatsLine(stats) + "\n   " + DimStyle.Render(v.MountPoint)
+}
+
+// renderHealth shows the disk health score once the junk and zombie scans
+// behind it are done
+func (m MainMenu) renderHealth() string {
+	if m.junkSize < 0 || m.zombieSize < 0 {
+		return "   " + DimStyle.Render(m.spinner.View()+" Disk health: measuring junk and old files...")
+	}
+
+	health := scanner.DiskHealth{
+		Total:   m.diskTotal,
+		Used:    m.diskUsed,
+		Junk:    m.junkSize,
+		Zombies: m.zombieSize,
+		Trash:   m.trashSize,
+	}
+	score := health.Score()
+	style := SuccessStyle
+	switch {
+	case score < 50:
+		style = ErrorStyle
+	case score < 80:
+		style = WarningStyle
+	}
+
+	line := "   Disk health: " + style.Bold(true).Render(fmt.Sprintf("%d/100 %s", score, scanner.HealthLabel(score)))
+	if r := health.Reclaimable(); r > 0 {
+		line += DimStyle.Render(fmt.Sprintf("  about %s could be reclaimed", FormatBytes(uint64(r))))
+	}
+	return line
+}
+
+// renderFullDiskAccess says whether lume can read protected folders. Without
+// Full Disk Access scans quietly skip Mail, Safari, the Trash and more, so
+// their totals come out low. Shown on macOS only.
+func (m MainMenu) renderFullDiskAccess() string {
+	switch m.fda {
+	case fdaGranted:
+		return "   " + SuccessStyle.Render("[ok] Full Disk Access") + DimStyle.Render("  scans can read protected folders")
+	case fdaMissing:
+		return "   " + WarningStyle.Render("! No Full Disk Access") + DimStyle.Render("  scans skip protected folders and report less (f to grant)")
+	}
+	return ""
+}
+
+// fullDiskAccessMsg carries the Full Disk Access state for the main menu
+type fullDiskAccessMsg int
+
+const (
+	fdaUnknown fullDiskAccessMsg = iota // not checked yet, or not macOS
+	fdaGranted
+	fdaMissing
+)
+
+func checkFullDiskAccess() tea.Cmd {
+	return func() tea.Msg {
+		if scanner.CurrentPlatform.Name() != "macOS" {
+			return fdaUnknown
+		}
+		if scanner.HasFullDiskAccess() {
+			return fdaGranted
+		}
+		return fdaMissing
+	}
+}
+
+// junkSizeMsg carries the reclaimable System Junk size for the health
+// score, 0 if the scan failed. A canceled scan sends nothing.
+type junkSizeMsg int64
+
+func getJunkSize(ctx context.Context) tea.Cmd {
+	return func() tea.Msg {
+		targets, err := scanner.NewEnhancedJunkScanner().ScanContext(ctx, nil)
+		if scanCanceled(err) {
+			return nil
+		}
+		if err != nil {
+			return junkSizeMsg(0)
+		}
+		var total int64
+		for _, t := range targets {
+			total += t.Reclaimable()
+		}
+		return junkSizeMsg(total)
+	}
+}
+
+// healthZombieMinSize keeps the health score's zombie scan quick by only
+// looking at the largest files
+const healthZombieMinSize = 100 * 1024 * 1024
+
+// zombieSizeMsg carries the size of large zombie files for the health
+// score, 0 if the scan failed. A canceled scan sends nothing.
+type zombieSizeMsg int64
+
+func getZombieSize(ctx context.Context) tea.Cmd {
+	return func() tea.Msg {
+		s := scanner.NewZombieHunterScanner("")
+		s.SetMinSize(healthZombieMinSize)
+		result, err := s.ScanContext(ctx, nil)
+		if scanCanceled(err) {
+			return nil
+		}
+		if err != nil {
+			return zombieSizeMsg(0)
+		}
+		return zombieSizeMsg(result.GetZombieSize())
+	}
+}
+
+// trashWarnSize is the Trash size from which the main menu suggests
+// emptying it
+const trashWarnSize = 1e9
+
+// trashUsageMsg carries the du size of the Trash, -1 if it could not be read
+type trashUsageMsg int64
+
+func getTrashUsage() tea.Cmd {
+	return func() tea.Msg {
+		return trashUsageMsg(cleaner.NewCleaner().TrashDiskUsage())
+	}
+}
+
+// volumesMsg carries the mounted disks for the main menu's disk bar
+type volumesMsg []scanner.Volume
+
+func getVolumes() tea.Cmd {
+	return func() tea.Msg {
+		volumes, _ := scanner.ListVolumes()
+		return volumesMsg(volumes)
+	}
 }
 
 type MenuSelectedMsg struct {
@@ -251,32 +548,63 @@ type diskInfoMsg struct {
 	used  uint64
 }
 
+// diskReading is one df sample of the data volume
+type diskReading struct {
+	total uint64
+	used  uint64
+}
+
 func getDiskInfo() tea.Cmd {
 	return func() tea.Msg {
-		cmd := exec.Command("df", "-k", "/System/Volumes/Data")
-		output, err := cmd.Output()
-
+		d, err := readDisk()
 		if err != nil {
-			cmd = exec.Command("df", "-k", "/")
-			output, err = cmd.Output()
-			if err != nil {
-				return err
-			}
+			return err
 		}
+		return diskInfoMsg{total: d.total, used: d.used}
+	}
+}
 
-		lines := strings.Split(string(output), "\n")
-		if len(lines) < 2 {
-			return fmt.Errorf("cannot parse disk info")
-		}
+// readDisk runs df on the data volume, falling back to /
+func readDisk() (diskReading, error) {
+	cmd := exec.Command("df", "-k", "/System/Volumes/Data")
+	output, err := cmd.Output()
 
-		fields := strings.Fields(lines[1])
-		if len(fields) < 4 {
-			return fmt.Errorf("cannot parse disk info")
+	if err != nil {
+		cmd = exec.Command("df", "-k", "/")
+		output, err = cmd.Output()
+		if err != nil {
+			return diskReading{}, err
 		}
+	}
+
+	lines := strings.Split(string(output), "\n")
+	if len(lines) < 2 {
+		return diskReading{}, fmt.Errorf("cannot parse disk info")
+	}
+
+	fields := strings.Fields(lines[1])
+	if len(fields) < 4 {
+		return diskReading{}, fmt.Errorf("cannot parse disk info")
+	}
 
-		total, _ := strconv.ParseUint(fields[1], 10, 64)
-		used, _ := strconv.ParseUint(fields[2], 10, 64)
+	total, _ := strconv.ParseUint(fields[1], 10, 64)
+	used, _ := strconv.ParseUint(fields[2], 10, 64)
 
-		return diskInfoMsg{total: total * 1024, used: used * 1024}
+	return diskReading{total: total * 1024, used: used * 1024}, nil
+}
+
+// diskFreed is how much free space df gained between two readings, 0 when
+// either is missing or usage did not drop
+func diskFreed(before, after diskReading) uint64 {
+	if before.total == 0 || after.total == 0 || after.used >= before.used {
+		return 0
 	}
+	return before.used - after.used
+}
+
+// sampleDisk is readDisk for callers that only want a best-effort reading;
+// a zero reading means df was unavailable
+func sampleDisk() diskReading {
+	d, _ := readDisk()
+	return d
 }
@@ -0,0 +1,67 @@
+package ui
+
+import (
+	"math"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// listArea records where a view last drew its list, so a click can be mapped
+// back to a row. Views hold it by pointer: View has a value receiver in some
+// of them, and the record has to survive the copy.
+type listArea struct {
+	top   int // screen line the centered content starts on
+	first int // content line holding the first row drawn
+	rows  int // rows drawn, 0 when no list is on screen
+}
+
+// markRows notes that the next line written to b is the first of rows list
+// rows
+func (a *listArea) markRows(b *strings.Builder, rows int) {
+	a.first = strings.Count(b.String(), "\n")
+	a.rows = rows
+}
+
+// center is Center that also records where the content lands on screen
+func (a *listArea) center(width, height int, content string) string {
+	// Same arithmetic as lipgloss.PlaceVertical
+	a.top = 0
+	if gap := height - (strings.Count(content, "\n") + 1); gap > 0 {
+		a.top = gap - int(math.Round(float64(gap)*0.5))
+	}
+	return Center(width, height, content)
+}
+
+// rowAt returns the row under screen line y, counted from the first row
+// drawn, or -1 when y is outside the list
+func (a *listArea) rowAt(y int) int {
+	row := y - a.top - a.first
+	if row < 0 || row >= a.rows {
+		return -1
+	}
+	return row
+}
+
+// wheelKey turns a mouse wheel event into the matching arrow key, so
+// scrolling goes through the same handling as the keyboard
+func wheelKey(msg tea.MouseMsg) (tea.KeyMsg, bool) {
+	switch msg.Button {
+	case tea.MouseButtonWheelUp:
+		return tea.KeyMsg{Type: tea.KeyUp}, true
+	case tea.MouseButtonWheelDown:
+		return tea.KeyMsg{Type: tea.KeyDown}, true
+	}
+	return tea.KeyMsg{}, false
+}
+
+// clickedRow returns the list row a left click landed on, or -1
+func clickedRow(msg tea.MouseMsg, area *listArea) int {
+	if msg.Button != tea.MouseButtonLeft || msg.Action != tea.MouseActionPress {
+		return -1
+	}
+	return area.rowAt(msg.Y)
+}
+
+// spaceKey is the key that toggles the row under the cursor
+var spaceKey = tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}}
@@ -0,0 +1,32 @@
+package ui
+
+import (
+	"sync"
+
+	"github.com/Tyooughtul/lume/pkg/scanner"
+)
+
+var (
+	displayHomeOnce sync.Once
+	displayHome     string // "" when abbreviation is turned off
+)
+
+// displayPath returns path as it should be shown to the user, with the home
+// directory abbreviated to "~" unless the config asks for full paths
+func displayPath(path string) string {
+	displayHomeOnce.Do(func() {
+		if !scanner.LoadConfig().FullPaths {
+			displayHome = scanner.GetRealHomeDir()
+		}
+	})
+	return scanner.AbbreviateHome(path, displayHome)
+}
+
+// truncatePathLeft shortens path to at most width cells by cutting from the
+// left, keeping the file name visible
+func truncatePathLeft(path string, width int) string {
+	if width <= 3 || len(path) <= width {
+		return path
+	}
+	return "..." + path[len(path)-(width-3):]
+}
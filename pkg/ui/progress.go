@@ -0,0 +1,64 @@
+package ui
+
+import (
+	"context"
+	"errors"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// progressMsg carries one progress line from a running scan
+type progressMsg string
+
+// progressRelay returns a channel to hand to a scanner as its progress
+// channel. Lines are forwarded to out without ever blocking the scanner: if
+// the view is not reading (it was left mid-scan, or is busy), lines are
+// dropped. Close the returned channel when the scan ends; out is closed after.
+func progressRelay(out chan string) chan<- string {
+	in := make(chan string, 16)
+	go func() {
+		for line := range in {
+			select {
+			case out <- line:
+			default:
+			}
+		}
+		close(out)
+	}()
+	return in
+}
+
+// waitForProgress reads the next line from ch. It returns nil once ch is
+// closed, so re-issuing it after every progressMsg stops when the scan ends.
+func waitForProgress(ch <-chan string) tea.Cmd {
+	return func() tea.Msg {
+		line, ok := <-ch
+		if !ok {
+			return nil
+		}
+		return progressMsg(line)
+	}
+}
+
+// newScanContext cancels the scan tracked by cancel, if any, and returns the
+// context for the next one
+func newScanContext(cancel *context.CancelFunc) context.Context {
+	stopScan(cancel)
+	ctx, c := context.WithCancel(context.Background())
+	*cancel = c
+	return ctx
+}
+
+// stopScan cancels the scan tracked by cancel, if any, so leaving a view
+// mid-scan stops the work instead of letting it finish in the background
+func stopScan(cancel *context.CancelFunc) {
+	if *cancel != nil {
+		(*cancel)()
+		*cancel = nil
+	}
+}
+
+// scanCanceled reports whether a scan ended only because it was stopped
+func scanCanceled(err error) bool {
+	return errors.Is(err, context.Canceled)
+}
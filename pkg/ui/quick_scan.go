@@ -0,0 +1,194 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/Tyooughtul/lume/pkg/scanner"
+)
+
+// QuickScanView answers "what's using my space right now": it measures the
+// usual heavy folders in the home directory with QuickScanLargeDirs and shows
+// them as a bar chart, biggest first. It only reads, nothing can be cleaned
+// from here.
+type QuickScanView struct {
+	dirs       []scanner.ScanTarget
+	cursor     int
+	scanning   bool
+	scanID     int // ignores results from a scan started before the current one
+	current    string
+	progressCh chan string
+	spinner    spinner.Model
+	width      int
+	height     int
+}
+
+type quickScanResult struct {
+	id   int
+	dirs []scanner.ScanTarget
+}
+
+func NewQuickScanView() *QuickScanView {
+	s := spinner.New()
+	s.Spinner = spinner.Dot
+	s.Style = lipgloss.NewStyle().Foreground(PrimaryColor)
+
+	return &QuickScanView{spinner: s}
+}
+
+func (m *QuickScanView) Init() tea.Cmd {
+	return tea.Batch(
+		m.spinner.Tick,
+		m.startScan(),
+	)
+}
+
+func (m *QuickScanView) startScan() tea.Cmd {
+	m.scanning = true
+	m.scanID++
+	m.dirs = nil
+	m.cursor = 0
+	m.current = ""
+	m.progressCh = make(chan string, 1)
+	progress := progressRelay(m.progressCh)
+
+	id := m.scanID
+	resultCh := make(chan quickScanResult, 1)
+	go func() {
+		dirs := scanner.QuickScanLargeDirs(progress)
+		close(progress)
+		sort.SliceStable(dirs, func(i, j int) bool {
+			return dirs[i].Size > dirs[j].Size
+		})
+		resultCh <- quickScanResult{id: id, dirs: dirs}
+	}()
+
+	return tea.Batch(
+		func() tea.Msg {
+			return <-resultCh
+		},
+		waitForProgress(m.progressCh),
+	)
+}
+
+func (m *QuickScanView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "q", "ctrl+c":
+			return m, tea.Quit
+		case "esc":
+			return m, func() tea.Msg { return BackToMenuMsg{} }
+		}
+		if m.scanning {
+			return m, nil
+		}
+
+		switch msg.String() {
+		case "up", "k":
+			if m.cursor > 0 {
+				m.cursor--
+			}
+		case "down", "j":
+			if m.cursor < len(m.dirs)-1 {
+				m.cursor++
+			}
+		case "r":
+			return m, tea.Batch(m.spinner.Tick, m.startScan())
+		}
+
+	case quickScanResult:
+		if msg.id == m.scanID {
+			m.scanning = false
+			m.dirs = msg.dirs
+		}
+		return m, nil
+
+	case progressMsg:
+		if m.scanning {
+			m.current = strings.TrimPrefix(string(msg), "Analyzing: ")
+			return m, waitForProgress(m.progressCh)
+		}
+	}
+
+	var cmd tea.Cmd
+	m.spinner, cmd = m.spinner.Update(msg)
+	return m, cmd
+}
+
+func (m *QuickScanView) View() string {
+	if m.width == 0 {
+		return "Loading..."
+	}
+
+	var b strings.Builder
+	b.WriteString(PageHeader("", "Space Overview", m.width))
+	b.WriteString("\n\n")
+
+	if m.scanning {
+		b.WriteString(fmt.Sprintf("  %s Measuring the biggest folders...\n", m.spinner.View()))
+		b.WriteString("\n  ")
+		if m.current != "" {
+			b.WriteString(DimStyle.Render(truncate(m.current, 50)))
+		} else {
+			b.WriteString("This may take a moment...")
+		}
+		b.WriteString("\n")
+		return Center(m.width, m.height, b.String())
+	}
+
+	if len(m.dirs) == 0 {
+		b.WriteString("  Nothing to show.\n")
+	} else {
+		barWidth := min(max(m.width-60, 10), 40)
+		largest := m.dirs[0].Size
+
+		for i, d := range m.dirs {
+			percent := 0.0
+			if largest > 0 {
+				percent = float64(d.Size) / float64(largest) * 100
+			}
+			name := padRight(truncate(d.Name, 22), 22)
+			bar := ProgressBar(percent, barWidth, PrimaryColor, DimColor)
+			size := padLeft(FormatBytes(uint64(d.Size)), 10)
+
+			if i == m.cursor {
+				b.WriteString(SelectedScanItemStyle.Render(fmt.Sprintf("  %s", name)))
+			} else {
+				b.WriteString(ScanItemStyle.Render(fmt.Sprintf("  %s", name)))
+			}
+			b.WriteString(" ")
+			b.WriteString(bar)
+			b.WriteString(" ")
+			b.WriteString(size)
+			b.WriteString("\n")
+		}
+
+		if m.cursor < len(m.dirs) {
+			b.WriteString("\n  ")
+			b.WriteString(DimStyle.Render(truncatePathLeft(displayPath(m.dirs[m.cursor].Path), max(m.width-6, 40))))
+			b.WriteString("\n")
+		}
+		b.WriteString("\n  ")
+		b.WriteString(DimStyle.Render("Library includes the Library folders listed separately."))
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString(StyledHelpBar([]KeyHelp{
+		{Key: "j/k", Desc: "navigate"},
+		{Key: "r", Desc: "rescan"},
+		{Key: "esc", Desc: "back"},
+	}))
+
+	return Center(m.width, m.height, b.String())
+}
@@ -197,6 +197,30 @@ func ProgressBar(percent float64, width int, usedColor, freeColor lipgloss.Color
 	return used + free
 }
 
+// DiskBeforeAfter renders the disk bar before and after a cleanup, one above
+// the other. Returns "" unless both readings are available.
+func DiskBeforeAfter(before, after diskReading, width int) string {
+	if before.total == 0 || after.total == 0 {
+		return ""
+	}
+
+	line := func(label string, d diskReading) string {
+		percent := float64(d.used) / float64(d.total) * 100
+		bar := ProgressBar(percent, width, DangerColor, SecondaryColor)
+		return fmt.Sprintf("  %s %s %5.1f%%", DimStyle.Render(padRight(label, 6)), bar, percent)
+	}
+
+	var delta string
+	if after.used < before.used {
+		delta = SuccessStyle.Render(fmt.Sprintf("+%s free", FormatBytes(before.used-after.used)))
+	} else {
+		// Moving to Trash doesn't release space until the Trash is emptied
+		delta = DimStyle.Render("no change yet (Empty Trash on the main menu to reclaim)")
+	}
+
+	return line("Before", before) + "\n" + line("After", after) + "  " + delta + "\n"
+}
+
 // StatsLine renders inline statistics separated by dim pipes
 func StatsLine(stats []string) string {
 	sep := DimStyle.Render(" | ")
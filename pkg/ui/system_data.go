@@ -0,0 +1,524 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/Tyooughtul/lume/pkg/cleaner"
+	"github.com/Tyooughtul/lume/pkg/scanner"
+)
+
+// SystemDataView lists what macOS lumps together as "System Data". Only
+// items the scanner marks CanClean can be selected; they go to the Trash like
+// everything else. Time Machine local snapshots have their own, typed
+// confirmation because deleting them is permanent.
+type SystemDataView struct {
+	items         []scanner.SystemDataItem
+	errors        []string
+	cursor        int
+	scrollOffset  int
+	selected      map[int]bool
+	scanning      bool
+	cancelScan    context.CancelFunc
+	cleaning      bool
+	quitArmed     bool
+	confirming    bool
+	snapshotInput string // typed confirmation, active when deletingSnaps
+	deletingSnaps bool
+	showErrors    bool
+	spinner       spinner.Model
+	width         int
+	height        int
+	resultCh      chan systemDataResult
+	cleanedSize   int64
+	snapsDeleted  int
+	diskBefore    diskReading
+	diskAfter     diskReading
+	err           error
+}
+
+type systemDataResult struct {
+	items  []scanner.SystemDataItem
+	errors []string
+	err    error
+}
+
+// snapshotsDeletedMsg reports the result of DeleteLocalSnapshots
+type snapshotsDeletedMsg struct {
+	deleted int
+	err     error
+}
+
+func NewSystemDataView() *SystemDataView {
+	s := spinner.New()
+	s.Spinner = spinner.Dot
+	s.Style = lipgloss.NewStyle().Foreground(PrimaryColor)
+
+	return &SystemDataView{
+		spinner:  s,
+		selected: make(map[int]bool),
+		resultCh: make(chan systemDataResult, 1),
+	}
+}
+
+func (m *SystemDataView) Init() tea.Cmd {
+	return tea.Batch(
+		m.spinner.Tick,
+		m.startScan(),
+	)
+}
+
+func (m *SystemDataView) startScan() tea.Cmd {
+	m.scanning = true
+	m.items = nil
+	m.errors = nil
+	m.selected = make(map[int]bool)
+	m.showErrors = false
+
+	ctx := newScanContext(&m.cancelScan)
+	go func() {
+		s := scanner.NewSystemDataScanner()
+		items, err := s.ScanContext(ctx)
+		m.resultCh <- systemDataResult{items: items, errors: s.GetErrors(), err: err}
+	}()
+
+	return func() tea.Msg {
+		return <-m.resultCh
+	}
+}
+
+func (m *SystemDataView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m.updateScrollOffset()
+
+	case tea.KeyMsg:
+		if m.confirming {
+			if done, confirmed := confirmKey(msg); done {
+				m.confirming = false
+				if confirmed {
+					return m, m.startClean()
+				}
+			}
+			return m, nil
+		}
+
+		if m.deletingSnaps {
+			switch msg.Type {
+			case tea.KeyEsc:
+				m.deletingSnaps = false
+			case tea.KeyEnter:
+				m.deletingSnaps = false
+				if m.snapshotInput == "delete" {
+					return m, m.startDeleteSnapshots()
+				}
+			case tea.KeyBackspace:
+				if len(m.snapshotInput) > 0 {
+					m.snapshotInput = m.snapshotInput[:len(m.snapshotInput)-1]
+				}
+			case tea.KeyRunes:
+				m.snapshotInput += string(msg.Runes)
+			}
+			return m, nil
+		}
+
+		if m.cleaning {
+			return m, busyKey(msg, &m.quitArmed)
+		}
+
+		if m.scanning {
+			switch msg.String() {
+			case "q", "ctrl+c":
+				stopScan(&m.cancelScan)
+				return m, tea.Quit
+			case "esc":
+				stopScan(&m.cancelScan)
+				return m, func() tea.Msg { return BackToMenuMsg{} }
+			}
+			return m, nil
+		}
+
+		if m.showErrors {
+			switch msg.String() {
+			case "q", "ctrl+c":
+				return m, tea.Quit
+			case "esc", "w":
+				m.showErrors = false
+			case "f":
+				return m, openFullDiskAccessSettings()
+			}
+			return m, nil
+		}
+
+		switch msg.String() {
+		case "q", "ctrl+c":
+			return m, tea.Quit
+		case "esc":
+			return m, func() tea.Msg { return BackToMenuMsg{} }
+		case "up", "k":
+			if m.cursor > 0 {
+				m.cursor--
+			}
+			m.updateScrollOffset()
+		case "down", "j":
+			if m.cursor < len(m.items)-1 {
+				m.cursor++
+			}
+			m.updateScrollOffset()
+		case " ", "enter":
+			if m.cursor < len(m.items) && m.items[m.cursor].CanClean {
+				m.selected[m.cursor] = !m.selected[m.cursor]
+			}
+		case "a":
+			allSelected := true
+			for i, item := range m.items {
+				if item.CanClean && !m.selected[i] {
+					allSelected = false
+					break
+				}
+			}
+			for i, item := range m.items {
+				if item.CanClean {
+					m.selected[i] = !allSelected
+				}
+			}
+		case "d", "c":
+			if _, count := m.selection(); count > 0 {
+				m.confirming = true
+			}
+		case "D":
+			if m.cursor < len(m.items) && len(m.items[m.cursor].Snapshots) > 0 {
+				m.deletingSnaps = true
+				m.snapshotInput = ""
+			}
+		case "w":
+			if len(m.errors) > 0 {
+				m.showErrors = true
+			}
+		case "r":
+			return m, m.startScan()
+		}
+
+	case systemDataResult:
+		if scanCanceled(msg.err) {
+			// A scan stopped by leaving the view; a newer one may be running
+			return m, nil
+		}
+		m.scanning = false
+		m.items = msg.items
+		m.errors = msg.errors
+		m.err = msg.err
+		if m.cursor >= len(m.items) {
+			m.cursor = 0
+		}
+		m.scrollOffset = 0
+		m.updateScrollOffset()
+
+	case cleanResultMsg:
+		m.cleaning = false
+		m.err = msg.err
+		if msg.size > 0 {
+			m.cleanedSize = msg.size
+			m.diskBefore, m.diskAfter = msg.before, msg.after
+			return m, tea.Batch(m.startScan(), RecordSnapshot(msg.after.total, msg.after.used, msg.size, "system_data", msg.details))
+		}
+		return m, m.startScan()
+
+	case snapshotsDeletedMsg:
+		m.cleaning = false
+		m.err = msg.err
+		m.snapsDeleted = msg.deleted
+		return m, m.startScan()
+
+	case finderResultMsg:
+		m.err = msg.err
+		m.showErrors = false
+		return m, nil
+
+	case BackToMenuMsg:
+		return NewMainMenu(), nil
+	}
+
+	var cmd tea.Cmd
+	m.spinner, cmd = m.spinner.Update(msg)
+	return m, cmd
+}
+
+// selection returns the size and number of selected items
+func (m *SystemDataView) selection() (size int64, count int) {
+	for i, item := range m.items {
+		if m.selected[i] && item.CanClean {
+			size += item.Size
+			count++
+		}
+	}
+	return size, count
+}
+
+func (m *SystemDataView) maxDisplay() int {
+	maxDisplay := MaxListItems
+	if m.height > 24 {
+		maxDisplay = m.height - 16
+	}
+	return maxDisplay
+}
+
+func (m *SystemDataView) updateScrollOffset() {
+	maxDisplay := min(m.maxDisplay(), len(m.items))
+	if m.cursor < m.scrollOffset {
+		m.scrollOffset = m.cursor
+	}
+	if m.cursor >= m.scrollOffset+maxDisplay {
+		m.scrollOffset = m.cursor - maxDisplay + 1
+	}
+}
+
+func (m *SystemDataView) startClean() tea.Cmd {
+	m.cleaning = true
+	m.quitArmed = false
+	m.snapsDeleted = 0
+
+	var targets []scanner.ScanTarget
+	var names []string
+	for i, item := range m.items {
+		if m.selected[i] && item.CanClean {
+			targets = append(targets, scanner.ScanTarget{
+				Name:      item.Name,
+				Path:      item.Path,
+				Size:      item.Size,
+				RiskLevel: item.RiskLevel,
+				Selected:  true,
+				Exclude:   item.Exclude,
+			})
+			names = append(names, item.Name)
+		}
+	}
+
+	return func() tea.Msg {
+		before := sampleDisk()
+		size, err := cleaner.NewCleaner().CleanScanTargets(targets, nil)
+		details := strings.Join(names, ", ")
+		if len(names) > 3 {
+			details = fmt.Sprintf("%s, %s and %d more", names[0], names[1], len(names)-2)
+		}
+		return cleanResultMsg{size: size, err: err, details: details, before: before, after: sampleDisk()}
+	}
+}
+
+func (m *SystemDataView) startDeleteSnapshots() tea.Cmd {
+	m.cleaning = true
+	m.quitArmed = false
+	m.cleanedSize = 0
+	names := m.items[m.cursor].Snapshots
+
+	return func() tea.Msg {
+		deleted, err := cleaner.NewCleaner().DeleteLocalSnapshots(names, nil)
+		return snapshotsDeletedMsg{deleted: deleted, err: err}
+	}
+}
+
+func (m SystemDataView) View() string {
+	if m.width == 0 {
+		return "Loading..."
+	}
+
+	var b strings.Builder
+
+	b.WriteString(PageHeader("", "System Data", m.width))
+	b.WriteString("\n\n")
+
+	if m.scanning {
+		b.WriteString(fmt.Sprintf("  %s Measuring system data...\n", m.spinner.View()))
+		b.WriteString("\n")
+		b.WriteString("  This runs du on many system folders and may take a while.\n")
+		return Center(m.width, m.height, b.String())
+	}
+
+	if m.cleaning {
+		b.WriteString(fmt.Sprintf("  %s Cleaning...\n", m.spinner.View()))
+		b.WriteString(busyHint(m.quitArmed))
+		return Center(m.width, m.height, b.String())
+	}
+
+	if m.showErrors {
+		b.WriteString("  ")
+		b.WriteString(WarningStyle.Render(fmt.Sprintf("%d locations could not be measured", len(m.errors))))
+		b.WriteString("\n\n")
+		for i, e := range m.errors {
+			if i >= m.maxDisplay() {
+				b.WriteString(DimStyle.Render(fmt.Sprintf("  ... and %d more", len(m.errors)-i)))
+				b.WriteString("\n")
+				break
+			}
+			b.WriteString("  ")
+			b.WriteString(truncate(e, max(m.width-6, 40)))
+			b.WriteString("\n")
+		}
+		b.WriteString("\n")
+		b.WriteString(DimStyle.Render("  Most of these need Full Disk Access for your terminal."))
+		b.WriteString("\n\n")
+		b.WriteString(StyledHelpBar([]KeyHelp{
+			{Key: "f", Desc: "open Full Disk Access settings"},
+			{Key: "esc", Desc: "back"},
+		}))
+		return Center(m.width, m.height, b.String())
+	}
+
+	if m.err != nil {
+		b.WriteString("  ")
+		b.WriteString(ErrorStyle.Render(fmt.Sprintf("Error: %v", m.err)))
+		b.WriteString("\n")
+	}
+	if m.snapsDeleted > 0 {
+		b.WriteString("  ")
+		b.WriteString(SuccessStyle.Render(fmt.Sprintf("[ok] Deleted %d local snapshots", m.snapsDeleted)))
+		b.WriteString("\n")
+	}
+	if m.cleanedSize > 0 {
+		b.WriteString("  ")
+		b.WriteString(SuccessStyle.Render(fmt.Sprintf("[ok] Cleaned %s", FormatBytes(uint64(m.cleanedSize)))))
+		b.WriteString("\n")
+		b.WriteString(DiskBeforeAfter(m.diskBefore, m.diskAfter, 30))
+		b.WriteString("\n")
+	}
+
+	if len(m.items) == 0 {
+		b.WriteString("  No system data found.\n")
+	} else {
+		b.WriteString("  ")
+		b.WriteString(TableHeader([]string{"", "Item", "Risk", "Size"}, []int{3, 36, 8, 12}))
+		b.WriteString("\n  ")
+		b.WriteString(Divider(63))
+		b.WriteString("\n")
+
+		maxDisplay := min(m.maxDisplay(), len(m.items))
+		for i := m.scrollOffset; i < m.scrollOffset+maxDisplay && i < len(m.items); i++ {
+			item := m.items[i]
+
+			// Items that cannot be cleaned get no checkbox
+			cb := "   "
+			if item.CanClean {
+				cb = Checkbox(m.selected[i])
+			}
+			name := padRight(truncate(item.Name, 36), 36)
+			sizeStr := FormatBytes(uint64(item.Size))
+			if item.SizeUnknown {
+				sizeStr = "unknown"
+			}
+
+			line := fmt.Sprintf("  %s %s %s %s", cb, name, GetRiskLabel(item.RiskLevel), padLeft(sizeStr, 12))
+			if i == m.cursor {
+				line = SelectedScanItemStyle.Render(line)
+			} else {
+				line = ScanItemStyle.Render(line)
+			}
+			b.WriteString(line)
+			b.WriteString("\n")
+		}
+
+		above, below := ScrollIndicator(m.scrollOffset, len(m.items), maxDisplay)
+		if above != "" {
+			b.WriteString("  ")
+			b.WriteString(above)
+			b.WriteString("\n")
+		}
+		if below != "" {
+			b.WriteString("  ")
+			b.WriteString(below)
+			b.WriteString("\n")
+		}
+
+		if m.cursor < len(m.items) {
+			item := m.items[m.cursor]
+			b.WriteString("\n  ")
+			b.WriteString(DimStyle.Render(truncatePathLeft(displayPath(item.Path), max(m.width-6, 40))))
+			b.WriteString("\n  ")
+			b.WriteString(DimStyle.Render(item.Description))
+			if !item.CanClean && len(item.Snapshots) == 0 {
+				b.WriteString(DimStyle.Render(" (managed by macOS, not cleanable)"))
+			}
+			b.WriteString("\n")
+		}
+
+		var total, cleanable int64
+		for _, item := range m.items {
+			total += item.Size
+			if item.CanClean {
+				cleanable += item.Size
+			}
+		}
+		selectedSize, selectedCount := m.selection()
+		stats := []string{
+			fmt.Sprintf("Total: %s", FormatBytes(uint64(total))),
+			fmt.Sprintf("Cleanable: %s", FormatBytes(uint64(cleanable))),
+			fmt.Sprintf("Selected: %s (%d)", FormatBytes(uint64(selectedSize)), selectedCount),
+		}
+		if len(m.errors) > 0 {
+			stats = append(stats, fmt.Sprintf("%d not measured (w)", len(m.errors)))
+		}
+		b.WriteString("\n")
+		b.WriteString(StatsBar(stats))
+	}
+
+	b.WriteString("\n\n")
+	switch {
+	case m.confirming:
+		size, count := m.selection()
+		var paths []string
+		for i, item := range m.items {
+			if m.selected[i] && item.CanClean {
+				paths = append(paths, item.Path)
+			}
+		}
+		b.WriteString(syncedDataWarning(paths))
+		b.WriteString(ConfirmDialog(fmt.Sprintf("Move %d items (%s) to Trash?", count, FormatBytes(uint64(size)))))
+	case m.deletingSnaps:
+		// D removes every snapshot on the row; there is no per-snapshot pick,
+		// so the dialog names them instead of only counting them
+		names := m.items[m.cursor].Snapshots
+		b.WriteString(WarningStyle.Render(fmt.Sprintf("! Permanently delete ALL %d local snapshots? They do not go to the Trash", len(names))))
+		b.WriteString("\n")
+		b.WriteString(WarningStyle.Render("  and Time Machine can no longer restore from them."))
+		b.WriteString("\n\n")
+		shown := names
+		if len(shown) > 5 {
+			shown = shown[:5]
+		}
+		for _, name := range shown {
+			b.WriteString(DimStyle.Render("    " + name))
+			b.WriteString("\n")
+		}
+		if len(names) > len(shown) {
+			b.WriteString(DimStyle.Render(fmt.Sprintf("    ... and %d more", len(names)-len(shown))))
+			b.WriteString("\n")
+		}
+		b.WriteString("\n  Type delete and press enter: ")
+		b.WriteString(AccentStyle.Render(m.snapshotInput + "_"))
+		b.WriteString("\n\n")
+		b.WriteString(StyledHelpBar([]KeyHelp{{Key: "esc", Desc: "cancel"}}))
+	default:
+		help := []KeyHelp{
+			{Key: "j/k", Desc: "navigate"},
+			{Key: "space", Desc: "toggle"},
+			{Key: "a", Desc: "all cleanable"},
+			{Key: "d", Desc: "clean"},
+		}
+		if m.cursor < len(m.items) && len(m.items[m.cursor].Snapshots) > 0 {
+			help = append(help, KeyHelp{Key: "D", Desc: "delete all snapshots"})
+		}
+		if len(m.errors) > 0 {
+			help = append(help, KeyHelp{Key: "w", Desc: "warnings"})
+		}
+		help = append(help, KeyHelp{Key: "r", Desc: "rescan"}, KeyHelp{Key: "esc", Desc: "back"})
+		b.WriteString(StyledHelpBar(help))
+	}
+
+	return Center(m.width, m.height, b.String())
+}
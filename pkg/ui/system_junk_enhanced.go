@@ -1,7 +1,10 @@
 package ui
 
 import (
+	"context"
 	"fmt"
+	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/spinner"
@@ -19,6 +22,7 @@ type SystemJunkViewEnhanced struct {
 	scrollOffset int
 	scanning     bool
 	cleaning     bool
+	quitArmed    bool
 	confirming   bool
 	showPreview  bool
 	showErrors   bool
@@ -26,13 +30,43 @@ type SystemJunkViewEnhanced struct {
 	spinner      spinner.Model
 	width        int
 	height       int
+	list         *listArea // where View last drew the list, for mouse clicks
 	scanner      *scanner.EnhancedJunkScanner
 	resultCh     chan scanResultEnhanced
+	cancelScan   context.CancelFunc
+	progressCh   chan string
+	scanDone     int // targets started so far, from "Scanning X/Y" progress
+	scanTotal    int
+	scanCurrent  string // name of the target last reported
 	cleanResult  string
 	cleanedSize  int64
+	junkBefore   int64 // junk total when the last clean started, 0 for none
+	junkAfter    int64 // junk total the rescan after it found, -1 until it finishes
+	diskBefore   diskReading
+	diskAfter    diskReading
+	disk         diskReading // df when the last scan finished, for the free space projection
 	errors       []string
 	err          error
 
+	// Full Disk Access as last checked from the warnings view;
+	// fdaRechecked is set once the user asked to re-check it
+	hasFDA       bool
+	fdaRechecked bool
+
+	// Filter state: targets whose Name contains filter (case-insensitive),
+	// optionally narrowed to selected targets only
+	filter       string
+	filtering    bool
+	onlySelected bool
+
+	// Targets smaller than minDisplaySize are hidden while largeOnly is set
+	minDisplaySize int64
+	largeOnly      bool
+
+	sortColumn junkSortColumn
+	sortDesc   bool
+	selectMode junkSelectMode // what a last applied
+
 	// Detail view state
 	showDetail       bool
 	detailScanning   bool
@@ -42,20 +76,98 @@ type SystemJunkViewEnhanced struct {
 	detailScroll     int
 	detailErr        error
 	detailResultCh   chan detailResultMsg
+	detailSelected   map[int]bool // entries picked for cleaning on their own
+	detailConfirming bool
+
+	// Selected paths with files modified in the last few minutes, found
+	// while a confirmation shows; recentChecking until the check returns
+	recentPaths    []string
+	recentChecking bool
+
+	// emptyAfterClean empties the Trash once the confirmed clean is done
+	// (D), so the result shows the space really freed according to df
+	emptyAfterClean bool
+	trashEmptied    bool // the last clean emptied the Trash too
+
+	// R measures the target under the cursor again on its own: rescanning
+	// is its path until the result is in
+	rescanning string
+
+	// notice is one line of feedback on the last R or L, or on why a
+	// locked target cannot be selected
+	notice string
+}
+
+// junkSortColumn is the column the System Junk list is sorted by
+type junkSortColumn int
+
+const (
+	junkSortSize junkSortColumn = iota
+	junkSortName
+	junkSortRisk
+)
+
+// junkSelectMode is a bulk selection applied with a. Pressing a moves to
+// the next mode in order: all, none, recommended, all again.
+type junkSelectMode int
+
+const (
+	junkSelectCustom      junkSelectMode = iota // as scanned or picked by hand
+	junkSelectAll                               // every visible target
+	junkSelectNone                              // no visible target
+	junkSelectRecommended                       // visible targets recommended() picks
+	junkSelectSmart                             // regenerable caches only, set with m
+)
+
+func (s junkSelectMode) next() junkSelectMode {
+	switch s {
+	case junkSelectAll:
+		return junkSelectNone
+	case junkSelectNone:
+		return junkSelectRecommended
+	}
+	return junkSelectAll
 }
 
+func (s junkSelectMode) String() string {
+	switch s {
+	case junkSelectAll:
+		return "all"
+	case junkSelectNone:
+		return "none"
+	case junkSelectRecommended:
+		return "recommended"
+	case junkSelectSmart:
+		return "smart"
+	}
+	return "custom"
+}
 
 type scanResultEnhanced struct {
 	targets []scanner.ScanTarget
 	errors  []string
 	err     error
+	disk    diskReading
 }
 
 // cleanResultMsg represents a cleanup result message
 type cleanResultMsg struct {
-	size    int64
-	err     error
-	details string
+	size     int64
+	err      error
+	details  string
+	before   diskReading // df readings taken around the cleanup
+	after    diskReading
+	emptied  bool  // the Trash was emptied after cleaning, so after is final
+	emptyErr error // emptying the Trash after cleaning failed
+}
+
+// targetRescanMsg is the new measurement of the target at path; ok is
+// false when it no longer belongs in the list
+type targetRescanMsg struct {
+	path   string
+	target scanner.ScanTarget
+	ok     bool
+	err    error
 }
 
 // detailResultMsg represents the result of scanning a target's contents
@@ -64,16 +176,83 @@ type detailResultMsg struct {
 	err     error
 }
 
+// recommended reports whether the recommended selection mode picks t: a
+// regenerable cache or build output that frees at least the "large only"
+// threshold, so the pick skips risky targets and ones not worth the rebuild.
+func (m *SystemJunkViewEnhanced) recommended(t *scanner.ScanTarget) bool {
+	return t.Regenerable() && t.Reclaimable() >= m.minDisplaySize
+}
+
+// defaultMinDisplaySize is the "large only" threshold when the config does
+// not set minDisplaySizeMB
+const defaultMinDisplaySize = 100 * 1000 * 1000
+
+// minAgeOverride replaces the minAgeDays config setting for this run when
+// set, see SetMinAge
+var minAgeOverride = -1
+
+// SetMinAge makes System Junk count and clean only files not modified for
+// days days (0 for no filter) instead of the config file's setting. It must
+// be called before NewApp.
+func SetMinAge(days int) {
+	minAgeOverride = days
+}
+
+// autoSelectOverride replaces the autoSelect config setting for this run,
+// see SetAutoSelect
+var autoSelectOverride string
+
+// reclaimable is the reclaimable size of the last completed scan, for the
+// main menu's health score. ok is false while there is none, or while a
+// scan or cleanup is running.
+func (m *SystemJunkViewEnhanced) reclaimable() (size int64, ok bool) {
+	if m.targets == nil || m.scanning || m.cleaning {
+		return 0, false
+	}
+	for _, t := range m.targets {
+		size += t.Reclaimable()
+	}
+	return size, true
+}
+
+// SetAutoSelect makes System Junk pre-select targets by policy (one of the
+// scanner.AutoSelect* constants) instead of the config file's setting. It
+// must be called before NewApp.
+func SetAutoSelect(policy string) {
+	autoSelectOverride = policy
+}
+
 func NewSystemJunkViewEnhanced() *SystemJunkViewEnhanced {
 	s := spinner.New()
 	s.Spinner = spinner.Dot
 	s.Style = lipgloss.NewStyle().Foreground(PrimaryColor)
 
+	junkScanner := scanner.NewEnhancedJunkScanner()
+	if autoSelectOverride != "" {
+		junkScanner.SetAutoSelect(autoSelectOverride)
+	}
+	if minAgeOverride >= 0 {
+		junkScanner.SetMinAge(minAgeOverride)
+	}
+
+	// A threshold set in the config applies from the start; otherwise l
+	// toggles the default one
+	minDisplaySize := int64(defaultMinDisplaySize)
+	cfg := scanner.LoadConfig()
+	if cfg.MinDisplaySizeMB > 0 {
+		minDisplaySize = int64(cfg.MinDisplaySizeMB) * 1000 * 1000
+	}
+
 	return &SystemJunkViewEnhanced{
 		spinner:        s,
-		scanner:        scanner.NewEnhancedJunkScanner(),
+		scanner:        junkScanner,
+		minDisplaySize: minDisplaySize,
+		largeOnly:      cfg.MinDisplaySizeMB > 0,
 		resultCh:       make(chan scanResultEnhanced, 1),
 		detailResultCh: make(chan detailResultMsg, 1),
+		list:           &listArea{},
+		sortColumn:     junkSortSize,
+		sortDesc:       true,
 	}
 }
 
@@ -88,19 +267,30 @@ func (m *SystemJunkViewEnhanced) startScan() tea.Cmd {
 	m.scanning = true
 	m.targets = []scanner.ScanTarget{}
 	m.errors = []string{}
+	m.onlySelected = false
+	m.rescanning, m.notice = "", ""
+	m.scanDone, m.scanTotal, m.scanCurrent = 0, 0, ""
+	m.progressCh = make(chan string, 1)
+	progress := progressRelay(m.progressCh)
+	ctx := newScanContext(&m.cancelScan)
 
 	go func() {
-		targets, err := m.scanner.Scan(nil)
+		targets, err := m.scanner.ScanContext(ctx, progress)
+		close(progress)
 		m.resultCh <- scanResultEnhanced{
 			targets: targets,
 			errors:  m.scanner.GetErrors(),
 			err:     err,
+			disk:    sampleDisk(),
 		}
 	}()
 
-	return func() tea.Msg {
-		return <-m.resultCh
-	}
+	return tea.Batch(
+		func() tea.Msg {
+			return <-m.resultCh
+		},
+		waitForProgress(m.progressCh),
+	)
 }
 
 func (m *SystemJunkViewEnhanced) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -110,28 +300,40 @@ func (m *SystemJunkViewEnhanced) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.height = msg.Height
 		m.updateScrollOffset()
 
+	case tea.MouseMsg:
+		return m.handleMouse(msg)
+
 	case tea.KeyMsg:
 		if m.confirming {
-			switch msg.String() {
-			case "y", "Y":
-				m.confirming = false
-				return m, m.startClean()
-			case "n", "N", "esc":
+			if done, confirmed := checkedConfirmKey(msg, m.recentChecking); done {
 				m.confirming = false
+				if confirmed {
+					return m, m.startClean()
+				}
 			}
 			return m, nil
 		}
 
-		if m.scanning || m.cleaning {
+		if m.cleaning {
+			return m, busyKey(msg, &m.quitArmed)
+		}
+
+		if m.scanning {
 			switch msg.String() {
 			case "q", "ctrl+c":
+				stopScan(&m.cancelScan)
 				return m, tea.Quit
 			case "esc":
+				stopScan(&m.cancelScan)
 				return m, func() tea.Msg { return BackToMenuMsg{} }
 			}
 			return m, nil
 		}
 
+		if m.filtering {
+			return m.handleFilterKeys(msg)
+		}
+
 		if m.showDetail {
 			return m.handleDetailKeys(msg)
 		}
@@ -144,72 +346,180 @@ func (m *SystemJunkViewEnhanced) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			switch msg.String() {
 			case "esc", "w":
 				m.showErrors = false
+			case "f":
+				if !m.hasFDA {
+					return m, openFullDiskAccessSettings()
+				}
+			case "r":
+				// Access granted since launch takes effect for new file
+				// operations, so a fresh scan picks up the blocked targets
+				m.hasFDA = scanner.HasFullDiskAccess()
+				m.fdaRechecked = true
+				if m.hasFDA {
+					m.showErrors = false
+					return m, m.startScan()
+				}
 			}
 			return m, nil
 		}
 
+		visible := m.visibleTargets()
+
 		switch msg.String() {
 		case "q", "ctrl+c":
 			return m, tea.Quit
 		case "esc":
+			if m.filter != "" {
+				m.setFilter("")
+				return m, nil
+			}
 			return m, func() tea.Msg { return BackToMenuMsg{} }
+		case "/":
+			m.filtering = true
 		case "up", "k":
 			if m.cursor > 0 {
 				m.cursor--
 			}
 			m.updateScrollOffset()
 		case "down", "j":
-			if m.cursor < len(m.targets)-1 {
+			if m.cursor < len(visible)-1 {
 				m.cursor++
 			}
 			m.updateScrollOffset()
 		case " ", "enter":
-			if len(m.targets) > 0 && m.cursor < len(m.targets) {
-				m.targets[m.cursor].Selected = !m.targets[m.cursor].Selected
+			if m.cursor < len(visible) {
+				idx := visible[m.cursor]
+				if m.targets[idx].Locked {
+					m.notice = fmt.Sprintf("%s is locked and cannot be selected; L unlocks it", m.targets[idx].Name)
+					return m, nil
+				}
+				if m.targets[idx].SizeUnknown {
+					m.notice = fmt.Sprintf("%s could not be read; grant Full Disk Access (w, then f) and rescan", m.targets[idx].Name)
+					return m, nil
+				}
+				m.targets[idx].Selected = !m.targets[idx].Selected
+				m.selectMode = junkSelectCustom
+				if m.onlySelected {
+					m.clampCursor()
+				}
 			}
 		case "a":
-			allSelected := true
-			for _, t := range m.targets {
-				if !t.Selected {
-					allSelected = false
-					break
+			m.selectMode = m.selectMode.next()
+			for _, idx := range visible {
+				t := &m.targets[idx]
+				if t.Locked || t.SizeUnknown {
+					continue
+				}
+				switch m.selectMode {
+				case junkSelectAll:
+					t.Selected = true
+				case junkSelectNone:
+					t.Selected = false
+				case junkSelectRecommended:
+					t.Selected = m.recommended(t)
 				}
 			}
-			for i := range m.targets {
-				m.targets[i].Selected = !allSelected
+			if m.onlySelected {
+				m.clampCursor()
+			}
+		case "m":
+			// Smart select: the visible targets only, like a, since only
+			// those are cleaned
+			m.selectMode = junkSelectSmart
+			for _, idx := range visible {
+				t := &m.targets[idx]
+				t.Selected = t.Regenerable() && !t.Locked && !t.SizeUnknown
+			}
+			if m.onlySelected {
+				m.clampCursor()
+			}
+		case "v":
+			current := ""
+			if m.cursor < len(visible) {
+				current = m.targets[visible[m.cursor]].Path
+			}
+			m.onlySelected = !m.onlySelected
+			m.restoreCursor(current)
+		case "l":
+			current := ""
+			if m.cursor < len(visible) {
+				current = m.targets[visible[m.cursor]].Path
 			}
+			m.largeOnly = !m.largeOnly
+			m.restoreCursor(current)
 		case "p":
-			if len(m.targets) > 0 && m.cursor < len(m.targets) {
+			if m.cursor < len(visible) {
 				m.showPreview = true
 				m.previewIndex = m.cursor
 			}
 		case "e":
-			if len(m.targets) > 0 && m.cursor < len(m.targets) {
+			if m.cursor < len(visible) {
+				target := m.targets[visible[m.cursor]]
 				m.showDetail = true
-				m.detailTarget = m.targets[m.cursor]
+				m.detailTarget = target
 				m.detailEntries = nil
 				m.detailCursor = 0
 				m.detailScroll = 0
 				m.detailErr = nil
-				return m, m.startDetailScan(m.targets[m.cursor].Path)
+				m.detailSelected = make(map[int]bool)
+				m.detailConfirming = false
+				return m, m.startDetailScan(target.Path)
+			}
+		case "o":
+			if m.cursor < len(visible) {
+				return m, revealInFinder(m.targets[visible[m.cursor]].Path)
 			}
 		case "w":
 			if len(m.errors) > 0 {
 				m.showErrors = true
+				m.hasFDA = scanner.HasFullDiskAccess()
+				m.fdaRechecked = false
 			}
-		case "d", "c":
+		case "d", "c", "D":
 			hasSelected := false
-			for _, t := range m.targets {
-				if t.Selected {
+			for _, idx := range visible {
+				if m.targets[idx].Selected {
 					hasSelected = true
 					break
 				}
 			}
 			if hasSelected {
 				m.confirming = true
+				m.emptyAfterClean = msg.String() == "D"
+				return m, m.startRecentCheck(m.selectedPaths(visible))
 			}
+		case "s":
+			// Cycle column; size and risk read best largest-first, names A-Z
+			m.sortColumn = (m.sortColumn + 1) % 3
+			m.sortDesc = m.sortColumn != junkSortName
+			m.sortTargets()
+		case "S":
+			m.sortDesc = !m.sortDesc
+			m.sortTargets()
 		case "r":
 			return m, m.startScan()
+		case "R":
+			if m.cursor < len(visible) && m.rescanning == "" {
+				return m, m.startRescan(m.targets[visible[m.cursor]])
+			}
+		case "L":
+			if m.cursor < len(visible) {
+				m.toggleLock(visible[m.cursor])
+				if m.onlySelected {
+					m.clampCursor()
+				}
+			}
+		}
+
+	case targetRescanMsg:
+		m.rescanning = ""
+		m.applyRescan(msg)
+
+	case recentActivityMsg:
+		// A late answer for a dialog already closed is dropped
+		if m.confirming || m.detailConfirming {
+			m.recentChecking = false
+			m.recentPaths = msg.paths
 		}
 
 	case detailResultMsg:
@@ -218,16 +528,27 @@ func (m *SystemJunkViewEnhanced) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.detailErr = msg.err
 		} else {
 			m.detailEntries = msg.entries
+			m.detailSelected = make(map[int]bool)
 		}
 
 	case scanResultEnhanced:
+		if scanCanceled(msg.err) {
+			// A scan stopped by leaving the view; a newer one may be running
+			return m, nil
+		}
 		m.scanning = false
 		if msg.err != nil {
 			m.err = msg.err
 		}
 		m.targets = msg.targets
 		m.errors = msg.errors
-		if m.cursor >= len(m.targets) {
+		m.disk = msg.disk
+		m.selectMode = junkSelectCustom
+		if m.junkAfter < 0 {
+			m.junkAfter = m.junkTotal()
+		}
+		m.sortTargets()
+		if m.cursor >= len(m.visibleTargets()) {
 			m.cursor = 0
 		}
 		m.scrollOffset = 0
@@ -236,14 +557,40 @@ func (m *SystemJunkViewEnhanced) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.cleaning = false
 		if msg.err != nil {
 			m.err = msg.err
+			m.junkBefore = 0
 		} else {
 			m.cleanedSize = msg.size
-			m.cleanResult = fmt.Sprintf("Cleaned %s", humanize.Bytes(uint64(msg.size)))
+			m.cleanResult = fmt.Sprintf("Cleaned %s", FormatBytes(uint64(msg.size)))
+			m.diskBefore, m.diskAfter = msg.before, msg.after
+			details := msg.details
+			m.trashEmptied = msg.emptied
+			if msg.emptied {
+				m.cleanResult = fmt.Sprintf("Cleaned %s and emptied the Trash: %s actually freed",
+					FormatBytes(uint64(msg.size)), FormatBytes(diskFreed(msg.before, msg.after)))
+				details += "; Trash emptied"
+			}
+			if msg.emptyErr != nil {
+				m.err = fmt.Errorf("emptying the Trash failed: %w", msg.emptyErr)
+			}
 			// Record snapshot after cleanup
-			return m, tea.Batch(m.startScan(), RecordSnapshot(0, 0, msg.size, "system_junk", msg.details))
+			return m, tea.Batch(m.startScan(), RecordSnapshot(msg.after.total, msg.after.used, msg.size, "system_junk", details))
 		}
 		return m, m.startScan()
 
+	case finderResultMsg:
+		m.err = msg.err
+		return m, nil
+
+	case progressMsg:
+		if m.scanning {
+			// Workers report out of order and the relay may drop lines,
+			// so only ever move forward
+			if done, total, name, ok := scanner.ParseScanProgress(string(msg)); ok && done > m.scanDone {
+				m.scanDone, m.scanTotal, m.scanCurrent = done, total, name
+			}
+			return m, waitForProgress(m.progressCh)
+		}
+
 	case BackToMenuMsg:
 		return NewMainMenu(), nil
 	}
@@ -262,13 +609,189 @@ func (m *SystemJunkViewEnhanced) handlePreviewKeys(msg tea.KeyMsg) (tea.Model, t
 			m.previewIndex--
 		}
 	case "down", "j":
-		if m.previewIndex < len(m.targets)-1 {
+		if m.previewIndex < len(m.visibleTargets())-1 {
 			m.previewIndex++
 		}
 	}
 	return m, nil
 }
 
+func (m *SystemJunkViewEnhanced) handleFilterKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyCtrlC:
+		return m, tea.Quit
+	case tea.KeyEsc:
+		m.filtering = false
+		m.setFilter("")
+	case tea.KeyEnter:
+		m.filtering = false
+	case tea.KeyBackspace:
+		if r := []rune(m.filter); len(r) > 0 {
+			m.setFilter(string(r[:len(r)-1]))
+		}
+	case tea.KeyRunes, tea.KeySpace:
+		m.setFilter(m.filter + string(msg.Runes))
+	}
+	return m, nil
+}
+
+// setFilter changes the filter and moves the cursor back to the top of the
+// (new) visible list
+func (m *SystemJunkViewEnhanced) setFilter(filter string) {
+	m.filter = filter
+	m.cursor = 0
+	m.scrollOffset = 0
+}
+
+// sortTargets orders m.targets by the active sort column, keeping the cursor
+// on the item it was on
+func (m *SystemJunkViewEnhanced) sortTargets() {
+	current := ""
+	if visible := m.visibleTargets(); m.cursor < len(visible) {
+		current = m.targets[visible[m.cursor]].Path
+	}
+
+	sort.SliceStable(m.targets, func(i, j int) bool {
+		a, b := m.targets[i], m.targets[j]
+		if m.sortDesc {
+			a, b = b, a
+		}
+		switch m.sortColumn {
+		case junkSortName:
+			return strings.ToLower(a.Name) < strings.ToLower(b.Name)
+		case junkSortRisk:
+			if a.RiskLevel != b.RiskLevel {
+				return a.RiskLevel < b.RiskLevel
+			}
+			return a.Reclaimable() < b.Reclaimable()
+		default:
+			return a.Reclaimable() < b.Reclaimable()
+		}
+	})
+
+	m.restoreCursor(current)
+}
+
+// restoreCursor moves the cursor to the visible target with the given path,
+// or keeps it in range if that target is no longer visible
+func (m *SystemJunkViewEnhanced) restoreCursor(path string) {
+	for i, idx := range m.visibleTargets() {
+		if m.targets[idx].Path == path {
+			m.cursor = i
+			m.updateScrollOffset()
+			return
+		}
+	}
+	m.clampCursor()
+}
+
+// clampCursor keeps the cursor inside the visible list after it shrinks
+func (m *SystemJunkViewEnhanced) clampCursor() {
+	if n := len(m.visibleTargets()); m.cursor >= n {
+		m.cursor = max(n-1, 0)
+	}
+	m.updateScrollOffset()
+}
+
+// sortHeader returns a column title with an arrow if the list is sorted by it
+func (m SystemJunkViewEnhanced) sortHeader(title string, column junkSortColumn) string {
+	if m.sortColumn != column {
+		return title
+	}
+	if m.sortDesc {
+		return title + " ↓"
+	}
+	return title + " ↑"
+}
+
+// visibleTargets returns indices into m.targets that match the filter
+func (m SystemJunkViewEnhanced) visibleTargets() []int {
+	needle := strings.ToLower(m.filter)
+	visible := make([]int, 0, len(m.targets))
+	for i, t := range m.targets {
+		if m.onlySelected && !t.Selected {
+			continue
+		}
+		if m.largeOnly && t.Reclaimable() < m.minDisplaySize && !t.SizeUnknown {
+			continue
+		}
+		if needle == "" || strings.Contains(strings.ToLower(t.Name), needle) {
+			visible = append(visible, i)
+		}
+	}
+	return visible
+}
+
+// targetSize is the size column of a target: what cleaning it frees, or
+// "access denied" when du could not read it
+func targetSize(t scanner.ScanTarget) string {
+	if t.SizeUnknown {
+		return "access denied"
+	}
+	return FormatBytes(uint64(t.Reclaimable()))
+}
+
+// toggleLock locks or unlocks m.targets[idx] and saves the change. A locked
+// target is taken out of the selection and no bulk selection picks it.
+func (m *SystemJunkViewEnhanced) toggleLock(idx int) {
+	t := &m.targets[idx]
+	if err := scanner.SetTargetLocked(t.Path, !t.Locked); err != nil {
+		m.err = fmt.Errorf("saving the lock on %s: %w", t.Name, err)
+		return
+	}
+	t.Locked = !t.Locked
+	if t.Locked {
+		t.Selected = false
+		m.selectMode = junkSelectCustom
+		m.notice = fmt.Sprintf("%s locked: select all and smart select skip it", t.Name)
+	} else {
+		m.notice = fmt.Sprintf("%s unlocked", t.Name)
+	}
+}
+
+// startRescan measures target again on its own, leaving the other rows as
+// they are
+func (m *SystemJunkViewEnhanced) startRescan(target scanner.ScanTarget) tea.Cmd {
+	m.rescanning = target.Path
+	m.notice = ""
+	junkScanner := m.scanner
+	return func() tea.Msg {
+		t, ok, err := junkScanner.RescanTarget(context.Background(), target)
+		return targetRescanMsg{path: target.Path, target: t, ok: ok, err: err}
+	}
+}
+
+// applyRescan puts a single target's new measurement in its row, or drops
+// the row as a full scan would; the cursor stays on the item it was on
+func (m *SystemJunkViewEnhanced) applyRescan(msg targetRescanMsg) {
+	idx := -1
+	for i, t := range m.targets {
+		if t.Path == msg.path {
+			idx = i
+			break
+		}
+	}
+	if idx < 0 {
+		// A full scan replaced the list in the meantime
+		return
+	}
+
+	old := m.targets[idx]
+	switch {
+	case msg.err != nil:
+		m.err = msg.err
+		return
+	case msg.ok:
+		msg.target.Selected = old.Selected
+		m.targets[idx] = msg.target
+		m.notice = fmt.Sprintf("%s rescanned: %s → %s", old.Name, targetSize(old), targetSize(msg.target))
+	default:
+		m.targets = append(m.targets[:idx], m.targets[idx+1:]...)
+		m.notice = fmt.Sprintf("%s rescanned: %s or less left, no longer listed", old.Name, FormatBytes(uint64(m.scanner.MinSize())))
+	}
+	m.sortTargets()
+}
+
 func (m *SystemJunkViewEnhanced) startDetailScan(path string) tea.Cmd {
 	m.detailScanning = true
 
@@ -294,11 +817,48 @@ func (m *SystemJunkViewEnhanced) handleDetailKeys(msg tea.KeyMsg) (tea.Model, te
 		return m, nil
 	}
 
+	if m.detailConfirming {
+		if done, confirmed := checkedConfirmKey(msg, m.recentChecking); done {
+			m.detailConfirming = false
+			if confirmed {
+				return m, m.startDetailClean()
+			}
+		}
+		return m, nil
+	}
+
 	switch msg.String() {
 	case "esc", "e":
 		m.showDetail = false
 	case "q", "ctrl+c":
 		return m, tea.Quit
+	case " ", "enter":
+		if m.detailCursor < len(m.detailEntries) && !m.detailTarget.Locked {
+			m.detailSelected[m.detailCursor] = !m.detailSelected[m.detailCursor]
+		}
+	case "a":
+		if m.detailTarget.Locked {
+			return m, nil
+		}
+		all := len(m.detailEntries) > 0
+		for i := range m.detailEntries {
+			if !m.detailSelected[i] {
+				all = false
+				break
+			}
+		}
+		for i := range m.detailEntries {
+			m.detailSelected[i] = !all
+		}
+	case "d", "c":
+		if _, count := m.detailSelection(); count > 0 {
+			m.detailConfirming = true
+			return m, m.startRecentCheck(m.detailSelectedPaths())
+		}
+	case "o":
+		if m.detailCursor < len(m.detailEntries) {
+			return m, revealInFinder(m.detailEntries[m.detailCursor].Path)
+		}
 	case "up", "k":
 		if m.detailCursor > 0 {
 			m.detailCursor--
@@ -313,6 +873,90 @@ func (m *SystemJunkViewEnhanced) handleDetailKeys(msg tea.KeyMsg) (tea.Model, te
 	return m, nil
 }
 
+// detailSelectedPaths returns the paths of the entries selected in the
+// detail view
+func (m SystemJunkViewEnhanced) detailSelectedPaths() []string {
+	var paths []string
+	for i, e := range m.detailEntries {
+		if m.detailSelected[i] {
+			paths = append(paths, e.Path)
+		}
+	}
+	return paths
+}
+
+// selectedPaths returns the paths of the selected targets among visible
+func (m SystemJunkViewEnhanced) selectedPaths(visible []int) []string {
+	var paths []string
+	for _, idx := range visible {
+		if m.targets[idx].Selected {
+			paths = append(paths, m.targets[idx].Path)
+		}
+	}
+	return paths
+}
+
+// startRecentCheck clears the last check's result and starts a new one
+// for the paths about to be confirmed
+func (m *SystemJunkViewEnhanced) startRecentCheck(paths []string) tea.Cmd {
+	m.recentPaths = nil
+	m.recentChecking = true
+	return checkRecentActivity(paths)
+}
+
+// detailSelection returns the total size and number of selected entries in
+// the detail view
+func (m SystemJunkViewEnhanced) detailSelection() (size int64, count int) {
+	for i, e := range m.detailEntries {
+		if m.detailSelected[i] {
+			size += e.Size
+			count++
+		}
+	}
+	return size, count
+}
+
+// startDetailClean moves the entries selected in the detail view to the
+// Trash, leaving the rest of the target alone, then goes back to the list
+func (m *SystemJunkViewEnhanced) startDetailClean() tea.Cmd {
+	m.cleaning = true
+	m.quitArmed = false
+	m.showDetail = false
+	m.junkBefore, m.junkAfter = m.junkTotal(), -1
+
+	// Entries are cleaned as targets of their own so that the target's age
+	// filter still applies to what is inside them; without one, each entry
+	// goes to the Trash whole
+	var entries []scanner.ScanTarget
+	var names []string
+	for i, e := range m.detailEntries {
+		if m.detailSelected[i] {
+			entry := scanner.ScanTarget{Name: e.Name, Path: e.Path, Size: e.Size, Selected: true}
+			if !m.detailTarget.OlderThan.IsZero() {
+				entry.OlderThan = m.detailTarget.OlderThan
+				entry.Exclude = m.detailTarget.Exclude
+			}
+			entries = append(entries, entry)
+			names = append(names, e.Name)
+		}
+	}
+	target := m.detailTarget.Name
+
+	return func() tea.Msg {
+		c := cleaner.NewCleaner()
+		before := sampleDisk()
+		size, err := c.CleanScanTargets(entries, nil)
+
+		details := target + ": "
+		if len(names) <= 3 {
+			details += strings.Join(names, ", ")
+		} else {
+			details += fmt.Sprintf("%s, %s and %d more", names[0], names[1], len(names)-2)
+		}
+		return cleanResultMsg{size: size, err: err, details: details, before: before, after: sampleDisk()}
+	}
+}
+
 func (m *SystemJunkViewEnhanced) updateDetailScroll() {
 	maxDisplay := MaxListItems
 	if m.height > 20 {
@@ -334,8 +978,8 @@ func (m *SystemJunkViewEnhanced) updateScrollOffset() {
 	if m.height > 20 {
 		maxDisplay = m.height - 12
 	}
-	if len(m.targets) < maxDisplay {
-		maxDisplay = len(m.targets)
+	if n := len(m.visibleTargets()); n < maxDisplay {
+		maxDisplay = n
 	}
 	if m.cursor < m.scrollOffset {
 		m.scrollOffset = m.cursor
@@ -345,16 +989,49 @@ func (m *SystemJunkViewEnhanced) updateScrollOffset() {
 	}
 }
 
+// handleMouse scrolls with the wheel and moves the cursor to a clicked row;
+// clicking the row already under the cursor toggles it
+func (m *SystemJunkViewEnhanced) handleMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
+	if key, ok := wheelKey(msg); ok {
+		return m.Update(key)
+	}
+	if m.confirming || m.cleaning || m.scanning || m.filtering {
+		return m, nil
+	}
+	if row := clickedRow(msg, m.list); row >= 0 {
+		i := m.scrollOffset + row
+		if i == m.cursor {
+			return m.Update(spaceKey)
+		}
+		m.cursor = i
+	}
+	return m, nil
+}
+
+// junkTotal is what every scanned target could free, shown or not
+func (m *SystemJunkViewEnhanced) junkTotal() int64 {
+	var total int64
+	for _, t := range m.targets {
+		total += t.Reclaimable()
+	}
+	return total
+}
+
 func (m *SystemJunkViewEnhanced) startClean() tea.Cmd {
 	m.cleaning = true
+	m.quitArmed = false
+	m.junkBefore, m.junkAfter = m.junkTotal(), -1
+	emptyTrash := m.emptyAfterClean
+	m.emptyAfterClean = false
 
 	return func() tea.Msg {
 		c := cleaner.NewCleaner()
+		before := sampleDisk()
 
 		var selected []scanner.ScanTarget
 		var names []string
-		for _, t := range m.targets {
-			if t.Selected {
+		for _, idx := range m.visibleTargets() {
+			if t := m.targets[idx]; t.Selected {
 				selected = append(selected, t)
 				names = append(names, t.Name)
 			}
@@ -369,14 +1046,42 @@ func (m *SystemJunkViewEnhanced) startClean() tea.Cmd {
 				details = fmt.Sprintf("%s, %s and %d more", names[0], names[1], len(names)-2)
 			}
 		}
-		return cleanResultMsg{size: size, err: err, details: details}
+		// A failed clean leaves the Trash alone: what did get moved is
+		// still there to restore
+		var emptyErr error
+		if emptyTrash && err == nil {
+			emptyErr = c.EmptyTrash()
+		}
+		return cleanResultMsg{size: size, err: err, details: details, before: before, after: sampleDisk(),
+			emptied: emptyTrash && err == nil && emptyErr == nil, emptyErr: emptyErr}
+	}
+}
+
+// freeSpaceProjection shows what cleaning selected bytes does to free
+// space: nothing until the Trash is emptied, since that is where the items
+// go. The figure after emptying is an estimate; hard links and APFS clones
+// free less than their size.
+func (m SystemJunkViewEnhanced) freeSpaceProjection(selected uint64) string {
+	if m.disk.total == 0 || selected == 0 {
+		return ""
+	}
+	free := m.disk.total - m.disk.used
+	projected := free + selected
+	if projected > m.disk.total {
+		projected = m.disk.total
 	}
+	return "  " + StatsLine([]string{
+		fmt.Sprintf("Free now: %s", FormatBytes(free)),
+		fmt.Sprintf("After clean: %s %s", FormatBytes(free), DimStyle.Render("(in Trash)")),
+		fmt.Sprintf("After emptying Trash: ~%s", SuccessStyle.Render(FormatBytes(projected))),
+	})
 }
 
 func (m SystemJunkViewEnhanced) View() string {
 	if m.width == 0 {
 		return "Loading..."
 	}
+	m.list.rows = 0
 
 	if m.showDetail {
 		return m.detailView()
@@ -398,7 +1103,17 @@ func (m SystemJunkViewEnhanced) View() string {
 	if m.scanning {
 		b.WriteString(fmt.Sprintf("  %s Scanning system for junk files...\n", m.spinner.View()))
 		b.WriteString("\n")
-		b.WriteString("  This may take a moment...\n")
+		if m.scanTotal > 0 {
+			percent := float64(m.scanDone) / float64(m.scanTotal) * 100
+			b.WriteString("  ")
+			b.WriteString(ProgressBar(percent, 40, PrimaryColor, DimColor))
+			b.WriteString(fmt.Sprintf(" %d/%d\n", m.scanDone, m.scanTotal))
+			b.WriteString("  ")
+			b.WriteString(DimStyle.Render(truncate(m.scanCurrent, 50)))
+			b.WriteString("\n")
+		} else {
+			b.WriteString("  This may take a moment...\n")
+		}
 		return Center(m.width, m.height, b.String())
 	}
 
@@ -406,12 +1121,42 @@ func (m SystemJunkViewEnhanced) View() string {
 		b.WriteString(fmt.Sprintf("  %s Cleaning selected items...\n", m.spinner.View()))
 		b.WriteString("\n")
 		b.WriteString("  Moving files to Trash...\n")
+		b.WriteString(busyHint(m.quitArmed))
 		return Center(m.width, m.height, b.String())
 	}
 
 	if m.cleanResult != "" {
 		b.WriteString("  ")
 		b.WriteString(SuccessStyle.Render("[ok] "+m.cleanResult))
+		b.WriteString("\n")
+		if bars := DiskBeforeAfter(m.diskBefore, m.diskAfter, 30); bars != "" {
+			b.WriteString(bars)
+		}
+		if freed := diskFreed(m.diskBefore, m.diskAfter); m.trashEmptied && freed < uint64(m.cleanedSize) {
+			b.WriteString("  ")
+			b.WriteString(DimStyle.Render("Less than the items' total: hard links, APFS clones and sparse files free less than their size."))
+			b.WriteString("\n")
+		}
+		if m.junkBefore > 0 && m.junkAfter >= 0 {
+			b.WriteString("  ")
+			b.WriteString(StatsLine([]string{
+				fmt.Sprintf("Junk before: %s", FormatBytes(uint64(m.junkBefore))),
+				fmt.Sprintf("Reclaimed: %s", FormatBytes(uint64(m.cleanedSize))),
+				fmt.Sprintf("Remaining: %s", FormatBytes(uint64(m.junkAfter))),
+			}))
+			b.WriteString("\n")
+			if m.junkAfter == 0 {
+				b.WriteString("  ")
+				b.WriteString(SuccessStyle.Render("Nothing left to clean."))
+				b.WriteString("\n")
+			}
+		}
+		b.WriteString("\n")
+	}
+
+	if m.notice != "" {
+		b.WriteString("  ")
+		b.WriteString(DimStyle.Render(m.notice))
 		b.WriteString("\n\n")
 	}
 
@@ -422,17 +1167,79 @@ func (m SystemJunkViewEnhanced) View() string {
 	}
 
 	if len(m.errors) > 0 {
+		unreadable := 0
+		for _, t := range m.targets {
+			if t.SizeUnknown {
+				unreadable++
+			}
+		}
 		b.WriteString("  ")
-		b.WriteString(WarningStyle.Render(fmt.Sprintf("[!] %d warnings (press 'w' to view)", len(m.errors))))
+		if unreadable > 0 {
+			b.WriteString(WarningStyle.Render(fmt.Sprintf("[!] %d warnings, %d targets could not be measured (press 'w' to view)", len(m.errors), unreadable)))
+		} else {
+			b.WriteString(WarningStyle.Render(fmt.Sprintf("[!] %d warnings (press 'w' to view)", len(m.errors))))
+		}
 		b.WriteString("\n")
 	}
 
+	visible := m.visibleTargets()
+
+	if m.filtering || m.filter != "" {
+		b.WriteString("  ")
+		b.WriteString(AccentStyle.Render("/"))
+		b.WriteString(" ")
+		b.WriteString(m.filter)
+		if m.filtering {
+			b.WriteString("_")
+		}
+		b.WriteString("\n\n")
+	}
+
+	if days := m.scanner.MinAgeDays(); days > 0 {
+		b.WriteString("  ")
+		b.WriteString(AccentStyle.Render(fmt.Sprintf("Only files not modified in %d days", days)))
+		b.WriteString(DimStyle.Render(" (sizes show the stale part)"))
+		b.WriteString("\n\n")
+	}
+
+	if m.onlySelected {
+		b.WriteString("  ")
+		b.WriteString(AccentStyle.Render("Showing selected items only"))
+		b.WriteString(DimStyle.Render(" (v to show all)"))
+		b.WriteString("\n\n")
+	}
+
+	if m.largeOnly {
+		hidden := 0
+		for _, t := range m.targets {
+			if t.Reclaimable() < m.minDisplaySize && !t.SizeUnknown {
+				hidden++
+			}
+		}
+		b.WriteString("  ")
+		b.WriteString(AccentStyle.Render(fmt.Sprintf("Hiding %d targets under %s", hidden, FormatBytes(uint64(m.minDisplaySize)))))
+		b.WriteString(DimStyle.Render(" (l to show all)"))
+		b.WriteString("\n\n")
+	}
+
 	if len(m.targets) == 0 {
 		b.WriteString("  No junk files found.\n")
 		b.WriteString("\n  Your system is clean!\n")
+	} else if len(visible) == 0 && m.onlySelected {
+		b.WriteString("  Nothing selected.\n")
+	} else if len(visible) == 0 && m.filter == "" {
+		b.WriteString(fmt.Sprintf("  No targets over %s.\n", FormatBytes(uint64(m.minDisplaySize))))
+	} else if len(visible) == 0 {
+		b.WriteString(fmt.Sprintf("  No targets match %q.\n", m.filter))
 	} else {
 		b.WriteString("  ")
-		b.WriteString(TableHeader([]string{"", "Name", "Size", "Files", "Risk"}, []int{3, 28, 10, 7, 8}))
+		b.WriteString(TableHeader([]string{
+			"",
+			m.sortHeader("Name", junkSortName),
+			m.sortHeader("Size", junkSortSize),
+			"Files",
+			m.sortHeader("Risk", junkSortRisk),
+		}, []int{3, 28, 10, 7, 8}))
 		b.WriteString("\n")
 		b.WriteString("  ")
 		b.WriteString(Divider(60))
@@ -442,22 +1249,35 @@ func (m SystemJunkViewEnhanced) View() string {
 		if m.height > 20 {
 			maxDisplay = m.height - 12
 		}
-		if len(m.targets) < maxDisplay {
-			maxDisplay = len(m.targets)
+		if len(visible) < maxDisplay {
+			maxDisplay = len(visible)
 		}
 
-		for i := m.scrollOffset; i < m.scrollOffset+maxDisplay && i < len(m.targets); i++ {
-			target := m.targets[i]
+		m.list.markRows(&b, min(maxDisplay, len(visible)-m.scrollOffset))
+		for i := m.scrollOffset; i < m.scrollOffset+maxDisplay && i < len(visible); i++ {
+			target := m.targets[visible[i]]
 			cb := Checkbox(target.Selected)
+			if target.Locked {
+				cb = WarningStyle.Render("L")
+			} else if target.SizeUnknown {
+				cb = WarningStyle.Render("!")
+			}
 
 			name := padRight(truncate(target.Name, 28), 28)
-			sizeStr := padLeft(humanize.Bytes(uint64(target.Size)), 10)
+			sizeStr := padLeft(FormatBytes(uint64(target.Reclaimable())), 10)
+			if target.Path == m.rescanning {
+				sizeStr = padLeft("measuring", 10)
+			}
 
-			countStr := fmt.Sprintf("%d", target.FileCount)
+			countStr := humanize.Comma(int64(target.FileCount))
 			if target.FileCount < 0 {
 				countStr = "-"
 			}
 			countStr = padLeft(countStr, 7)
+			if target.SizeUnknown && target.Path != m.rescanning {
+				// Spans the size and files columns
+				sizeStr, countStr = padLeft("access denied", 17), ""
+			}
 
 			riskStr := GetRiskLabel(target.RiskLevel)
 
@@ -472,7 +1292,7 @@ func (m SystemJunkViewEnhanced) View() string {
 			b.WriteString("\n")
 		}
 
-		above, below := ScrollIndicator(m.scrollOffset, len(m.targets), maxDisplay)
+		above, below := ScrollIndicator(m.scrollOffset, len(visible), maxDisplay)
 		if above != "" {
 			b.WriteString("  ")
 			b.WriteString(above)
@@ -487,51 +1307,96 @@ func (m SystemJunkViewEnhanced) View() string {
 		selectedSize := int64(0)
 		selectedCount := 0
 		totalSize := int64(0)
-		for _, t := range m.targets {
-			totalSize += t.Size
+		for _, idx := range visible {
+			t := m.targets[idx]
+			totalSize += t.Reclaimable()
 			if t.Selected {
-				selectedSize += t.Size
+				selectedSize += t.Reclaimable()
 				selectedCount++
 			}
 		}
 
 		b.WriteString("\n")
 		stats := StatsBar([]string{
-			fmt.Sprintf("Total: %s (%d)", humanize.Bytes(uint64(totalSize)), len(m.targets)),
-			fmt.Sprintf("Selected: %s (%d)", humanize.Bytes(uint64(selectedSize)), selectedCount),
+			fmt.Sprintf("Total: %s (%d)", FormatBytes(uint64(totalSize)), len(visible)),
+			fmt.Sprintf("Selected: %s (%d)", FormatBytes(uint64(selectedSize)), selectedCount),
+			fmt.Sprintf("Selection: %s", m.selectMode),
 		})
 		b.WriteString(stats)
+		if m.selectMode == junkSelectSmart {
+			var smartSize int64
+			smartCount := 0
+			for _, idx := range visible {
+				if t := m.targets[idx]; t.Selected {
+					smartSize += t.Reclaimable()
+					smartCount++
+				}
+			}
+			b.WriteString("\n")
+			b.WriteString(DimStyle.Render(fmt.Sprintf("  Smart select: %d low-risk caches their apps rebuild on their own, %s reclaimable",
+				smartCount, FormatBytes(uint64(smartSize)))))
+		}
+		if projection := m.freeSpaceProjection(uint64(selectedSize)); projection != "" {
+			b.WriteString("\n")
+			b.WriteString(projection)
+		}
 	}
 
 	b.WriteString("\n\n")
 	if m.confirming {
 		selectedCount := 0
 		selectedSize := int64(0)
-		for _, t := range m.targets {
-			if t.Selected {
+		inUse := make(map[string]bool)
+		for _, p := range m.recentPaths {
+			inUse[p] = true
+		}
+		var paths, recent []string
+		for _, idx := range visible {
+			if t := m.targets[idx]; t.Selected {
 				selectedCount++
-				selectedSize += t.Size
+				selectedSize += t.Reclaimable()
+				paths = append(paths, t.Path)
+				if inUse[t.Path] {
+					recent = append(recent, t.Name)
+				}
 			}
 		}
-		b.WriteString("  " + WarningStyle.Render(fmt.Sprintf("Move %d items (%s) to Trash?", selectedCount, humanize.Bytes(uint64(selectedSize)))))
-		b.WriteString("\n\n")
+		b.WriteString(syncedDataWarning(paths))
+		b.WriteString(recentActivityWarning(recent, m.recentChecking))
+		if m.emptyAfterClean {
+			b.WriteString(WarningStyle.Render("! The Trash is emptied afterwards, including what was already in it. This cannot be undone."))
+			b.WriteString("\n\n")
+			b.WriteString(ConfirmDialog(fmt.Sprintf("Clean %d items (%s) and empty the Trash?", selectedCount, FormatBytes(uint64(selectedSize)))))
+		} else {
+			b.WriteString(ConfirmDialog(fmt.Sprintf("Move %d items (%s) to Trash?", selectedCount, FormatBytes(uint64(selectedSize)))))
+		}
+	} else if m.filtering {
 		b.WriteString(StyledHelpBar([]KeyHelp{
-			{Key: "y", Desc: "confirm"},
-			{Key: "n/esc", Desc: "cancel"},
+			{Key: "enter", Desc: "apply"},
+			{Key: "esc", Desc: "clear"},
 		}))
 	} else {
 		b.WriteString(StyledHelpBar([]KeyHelp{
 			{Key: "j/k", Desc: "navigate"},
 			{Key: "space", Desc: "toggle"},
-			{Key: "a", Desc: "all"},
+			{Key: "a", Desc: "select " + m.selectMode.next().String()},
+			{Key: "m", Desc: "smart select"},
+			{Key: "/", Desc: "filter"},
+			{Key: "s/S", Desc: "sort"},
+			{Key: "v", Desc: "selected"},
+			{Key: "l", Desc: "large only"},
 			{Key: "e", Desc: "detail"},
 			{Key: "p", Desc: "preview"},
+			{Key: "o", Desc: "reveal"},
 			{Key: "d", Desc: "clean"},
+			{Key: "D", Desc: "clean + empty Trash"},
 			{Key: "r", Desc: "refresh"},
+			{Key: "R", Desc: "rescan item"},
+			{Key: "L", Desc: "lock"},
 		}))
 	}
 
-	return Center(m.width, m.height, b.String())
+	return m.list.center(m.width, m.height, b.String())
 }
 
 func (m SystemJunkViewEnhanced) detailView() string {
@@ -541,9 +1406,14 @@ func (m SystemJunkViewEnhanced) detailView() string {
 	b.WriteString("\n\n")
 
 	// Target info header
-	b.WriteString(fmt.Sprintf("  Path: %s\n", SubtitleStyle.Render(m.detailTarget.Path)))
-	b.WriteString(fmt.Sprintf("  Size: %s", humanize.Bytes(uint64(m.detailTarget.Size))))
+	b.WriteString(fmt.Sprintf("  Path: %s\n", SubtitleStyle.Render(displayPath(m.detailTarget.Path))))
+	b.WriteString(fmt.Sprintf("  Size: %s", FormatBytes(uint64(m.detailTarget.Size))))
 	b.WriteString(fmt.Sprintf("    Risk: %s\n", GetRiskLabel(m.detailTarget.RiskLevel)))
+	if m.detailTarget.Locked {
+		b.WriteString("  ")
+		b.WriteString(WarningStyle.Render("Locked: nothing here can be selected (L in the list unlocks it)"))
+		b.WriteString("\n")
+	}
 	b.WriteString("\n")
 
 	if m.detailScanning {
@@ -576,10 +1446,10 @@ func (m SystemJunkViewEnhanced) detailView() string {
 
 	// Table header
 	b.WriteString("  ")
-	b.WriteString(TableHeader([]string{"", "Name", "Size"}, []int{3, 40, 12}))
+	b.WriteString(TableHeader([]string{"", "", "Name", "Size"}, []int{3, 1, 40, 12}))
 	b.WriteString("\n")
 	b.WriteString("  ")
-	b.WriteString(Divider(58))
+	b.WriteString(Divider(62))
 	b.WriteString("\n")
 
 	maxDisplay := MaxListItems
@@ -602,9 +1472,9 @@ func (m SystemJunkViewEnhanced) detailView() string {
 		}
 
 		name := padRight(truncate(entry.Name, 40), 40)
-		sizeStr := padLeft(humanize.Bytes(uint64(entry.Size)), 12)
+		sizeStr := padLeft(FormatBytes(uint64(entry.Size)), 12)
 
-		line := fmt.Sprintf("  %s %s %s", icon, name, sizeStr)
+		line := fmt.Sprintf("  %s %s %s %s", Checkbox(m.detailSelected[i]), icon, name, sizeStr)
 
 		if i == m.detailCursor {
 			line = SelectedScanItemStyle.Render(line)
@@ -638,17 +1508,38 @@ func (m SystemJunkViewEnhanced) detailView() string {
 			fileCount++
 		}
 	}
+	selectedSize, selectedCount := m.detailSelection()
 	b.WriteString(StatsBar([]string{
 		fmt.Sprintf("Entries: %d", len(m.detailEntries)),
 		fmt.Sprintf("Dirs: %d", dirCount),
 		fmt.Sprintf("Files: %d", fileCount),
+		fmt.Sprintf("Selected: %s (%d)", FormatBytes(uint64(selectedSize)), selectedCount),
 	}))
 
 	b.WriteString("\n\n")
-	b.WriteString(StyledHelpBar([]KeyHelp{
-		{Key: "j/k", Desc: "navigate"},
-		{Key: "esc", Desc: "back"},
-	}))
+	if m.detailConfirming {
+		paths := m.detailSelectedPaths()
+		var recent []string
+		for _, p := range m.recentPaths {
+			recent = append(recent, filepath.Base(p))
+		}
+		b.WriteString(syncedDataWarning(paths))
+		b.WriteString(recentActivityWarning(recent, m.recentChecking))
+		if cutoff := m.detailTarget.OlderThan; !cutoff.IsZero() {
+			b.WriteString(DimStyle.Render(fmt.Sprintf("Only files last modified before %s are moved; sizes are whole entries", cutoff.Format("2006-01-02"))))
+			b.WriteString("\n\n")
+		}
+		b.WriteString(ConfirmDialog(fmt.Sprintf("Move %d entries (%s) to Trash?", selectedCount, FormatBytes(uint64(selectedSize)))))
+	} else {
+		b.WriteString(StyledHelpBar([]KeyHelp{
+			{Key: "j/k", Desc: "navigate"},
+			{Key: "space", Desc: "toggle"},
+			{Key: "a", Desc: "all/none"},
+			{Key: "o", Desc: "reveal"},
+			{Key: "d", Desc: "clean selected"},
+			{Key: "esc", Desc: "back"},
+		}))
+	}
 
 	return Center(m.width, m.height, b.String())
 }
@@ -659,18 +1550,24 @@ func (m SystemJunkViewEnhanced) previewView() string {
 	b.WriteString(PageHeader("", "Preview", m.width))
 	b.WriteString("\n\n")
 
-	if m.previewIndex < len(m.targets) {
-		target := m.targets[m.previewIndex]
+	if visible := m.visibleTargets(); m.previewIndex < len(visible) {
+		target := m.targets[visible[m.previewIndex]]
 
 		b.WriteString(fmt.Sprintf("  > %s\n", target.Name))
-		b.WriteString(fmt.Sprintf("     Path: %s\n", target.Path))
+		b.WriteString(fmt.Sprintf("     Path: %s\n", displayPath(target.Path)))
 
-		sizeStr := humanize.Bytes(uint64(target.Size))
+		sizeStr := FormatBytes(uint64(target.Size))
 		if target.Size > 1024*1024*1024 {
 			sizeStr = lipgloss.NewStyle().Foreground(WarningColor).Bold(true).Render(sizeStr)
 		}
 		b.WriteString(fmt.Sprintf("     Size: %s\n", sizeStr))
-		b.WriteString(fmt.Sprintf("     Files: %d\n", target.FileCount))
+		if !target.OlderThan.IsZero() {
+			b.WriteString(fmt.Sprintf("     Older than %s: %s in %s files\n",
+				target.OlderThan.Format("2006-01-02"), FormatBytes(uint64(target.StaleSize)), humanize.Comma(int64(target.StaleFiles))))
+		}
+		if target.FileCount >= 0 {
+			b.WriteString(fmt.Sprintf("     Files: %s\n", humanize.Comma(int64(target.FileCount))))
+		}
 		b.WriteString(fmt.Sprintf("     Risk: %s\n", GetRiskLabel(target.RiskLevel)))
 		b.WriteString("\n")
 
@@ -681,11 +1578,8 @@ func (m SystemJunkViewEnhanced) previewView() string {
 					b.WriteString(fmt.Sprintf("     ... and %d more\n", len(target.Files)-10))
 					break
 				}
-				shortPath := file.Path
-				if len(shortPath) > 50 {
-					shortPath = "..." + shortPath[len(shortPath)-47:]
-				}
-				b.WriteString(fmt.Sprintf("     %s (%s)\n", shortPath, humanize.Bytes(uint64(file.Size))))
+				shortPath := truncatePathLeft(displayPath(file.Path), max(50, m.width-20))
+				b.WriteString(fmt.Sprintf("     %s (%s)\n", shortPath, FormatBytes(uint64(file.Size))))
 			}
 		}
 
@@ -722,15 +1616,25 @@ func (m SystemJunkViewEnhanced) errorsView() string {
 	b.WriteString(SubtitleStyle.Render("These are usually permission errors when accessing certain directories."))
 	
 	// Check if we have Full Disk Access permission issues
-	hasFDA := scanner.HasFullDiskAccess()
-	if !hasFDA {
+	if !m.hasFDA {
 		b.WriteString("\n\n")
 		b.WriteString("  " + WarningStyle.Render("⚠ Full Disk Access Required") + "\n")
 		b.WriteString("  To access Trash, Safari Cache, and other protected folders:\n")
-		b.WriteString("  1. Open System Settings → Privacy & Security → Full Disk Access\n")
+		b.WriteString("  1. Press f to open System Settings → Privacy & Security → Full Disk Access\n")
 		b.WriteString("  2. Click '+' and add this terminal application\n")
-		b.WriteString("  3. Restart lume\n")
+		b.WriteString("  3. Press r to re-check access and rescan\n")
+		if m.fdaRechecked {
+			b.WriteString("\n  " + ErrorStyle.Render("Still no Full Disk Access. Some terminals only pick it up after they are restarted.") + "\n")
+		}
+	}
+
+	b.WriteString("\n\n")
+	help := []KeyHelp{{Key: "r", Desc: "re-check & rescan"}}
+	if !m.hasFDA {
+		help = append(help, KeyHelp{Key: "f", Desc: "open settings"})
 	}
+	help = append(help, KeyHelp{Key: "esc/w", Desc: "back"})
+	b.WriteString(StyledHelpBar(help))
 
 	return Center(m.width, m.height, b.String())
 }
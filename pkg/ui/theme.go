@@ -5,9 +5,13 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
 	"sort"
+	"strconv"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+
 	"github.com/Tyooughtul/lume/pkg/scanner"
 )
 
@@ -52,6 +56,74 @@ func (t *Theme) SelectedBgColor() lipgloss.Color { return lipgloss.Color(t.Selec
 func (t *Theme) SelectedFgColor() lipgloss.Color { return lipgloss.Color(t.SelectedFg) }
 func (t *Theme) BorderColor() lipgloss.Color    { return lipgloss.Color(t.Border) }
 
+// themeColor describes one color field of Theme for validation and editing
+type themeColor struct {
+	Key   string // JSON key
+	Label string
+	Get   func(t *Theme) *string
+}
+
+// themeColors lists the color fields of Theme in display order
+var themeColors = []themeColor{
+	{"primary", "Primary", func(t *Theme) *string { return &t.Primary }},
+	{"secondary", "Secondary", func(t *Theme) *string { return &t.Secondary }},
+	{"accent", "Accent", func(t *Theme) *string { return &t.Accent }},
+	{"danger", "Danger", func(t *Theme) *string { return &t.Danger }},
+	{"warning", "Warning", func(t *Theme) *string { return &t.Warning }},
+	{"success", "Success", func(t *Theme) *string { return &t.Success }},
+	{"background", "Background", func(t *Theme) *string { return &t.Background }},
+	{"foreground", "Foreground", func(t *Theme) *string { return &t.Foreground }},
+	{"gray", "Gray", func(t *Theme) *string { return &t.Gray }},
+	{"light_gray", "Light gray", func(t *Theme) *string { return &t.LightGray }},
+	{"dim", "Dim", func(t *Theme) *string { return &t.Dim }},
+	{"selected_bg", "Selected bg", func(t *Theme) *string { return &t.SelectedBg }},
+	{"selected_fg", "Selected fg", func(t *Theme) *string { return &t.SelectedFg }},
+	{"border", "Border", func(t *Theme) *string { return &t.Border }},
+}
+
+var (
+	hexColorRe  = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+	themeNameRe = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+)
+
+// validColor reports whether s is a #RGB or #RRGGBB color or an ANSI
+// color index (0-255), the forms lipgloss.Color understands
+func validColor(s string) bool {
+	if hexColorRe.MatchString(s) {
+		return true
+	}
+	n, err := strconv.Atoi(s)
+	return err == nil && n >= 0 && n <= 255
+}
+
+// Validate checks every color field. Empty fields are allowed and leave the
+// terminal's default color in place.
+func (t *Theme) Validate() error {
+	for _, c := range themeColors {
+		if v := *c.Get(t); v != "" && !validColor(v) {
+			return fmt.Errorf("%s: %q is not a #RGB, #RRGGBB or 0-255 color", c.Key, v)
+		}
+	}
+	return nil
+}
+
+// sanitize replaces every invalid color with the one from fallback and
+// returns a warning per replaced field. lipgloss silently renders an invalid
+// color as "no color", which can leave text invisible.
+func (t *Theme) sanitize(fallback *Theme) []string {
+	var warnings []string
+	for _, c := range themeColors {
+		v := c.Get(t)
+		if *v == "" || validColor(*v) {
+			continue
+		}
+		def := *c.Get(fallback)
+		warnings = append(warnings, fmt.Sprintf("theme %s: %s %q is not a color, using %s", t.Name, c.Key, *v, def))
+		*v = def
+	}
+	return warnings
+}
+
 // PresetThemes contains all built-in themes
 var PresetThemes = map[string]Theme{
 	"modern": {
@@ -190,6 +262,32 @@ var PresetThemes = map[string]Theme{
 		SelectedFg:  "#f8f8f2",
 		Border:      "#75715e",
 	},
+	"light": {
+		Name:        "light",
+		Description: "Light (for light terminals)",
+		Primary:     "#005f87", // deep blue
+		Secondary:   "#00875f", // green
+		Accent:      "#8700af", // purple
+		Danger:      "#d70000", // red
+		Warning:     "#af5f00", // dark orange
+		Success:     "#00875f", // green
+		Foreground:  "#1c1c1c",
+		Gray:        "#6c6c6c",
+		LightGray:   "#585858", // darker than Gray: secondary text on a light background
+		Dim:         "#a8a8a8",
+		SelectedBg:  "#d0e4f5",
+		SelectedFg:  "#000000",
+		Border:      "#8a8a8a",
+	},
+}
+
+// defaultThemeName picks the theme used when none has been saved: the
+// built-in themes other than "light" assume a dark terminal background
+func defaultThemeName() string {
+	if !lipgloss.HasDarkBackground() {
+		return "light"
+	}
+	return "modern"
 }
 
 // ThemeManager manages theme configuration
@@ -197,6 +295,7 @@ type ThemeManager struct {
 	CurrentTheme Theme
 	AllThemes    map[string]Theme
 	ConfigPath   string
+	Warnings     []string // invalid colors replaced while loading or switching themes
 }
 
 // NewThemeManager creates a theme manager
@@ -215,7 +314,7 @@ func NewThemeManager() *ThemeManager {
 	tm.loadUserThemes()
 
 	// Set default theme or use saved theme
-	tm.CurrentTheme = tm.AllThemes["modern"]
+	tm.CurrentTheme = tm.AllThemes[defaultThemeName()]
 	tm.loadCurrentTheme()
 
 	return tm
@@ -234,6 +333,8 @@ func (tm *ThemeManager) GetThemeNames() []string {
 // SetTheme switches to the specified theme
 func (tm *ThemeManager) SetTheme(name string) error {
 	if theme, ok := tm.AllThemes[name]; ok {
+		def := PresetThemes["modern"]
+		tm.Warnings = append(tm.Warnings, theme.sanitize(&def)...)
 		tm.CurrentTheme = theme
 		tm.saveCurrentTheme()
 		// Update global color variables
@@ -266,9 +367,35 @@ func (tm *ThemeManager) NextTheme() string {
 	return nextName
 }
 
+// noColor is set by DisableColor; applyTheme then applies monoTheme
+// whatever theme is selected
+var noColor bool
+
+// monoTheme leaves every color to the terminal's defaults
+var monoTheme = Theme{Name: "mono", Description: "No color"}
+
+// cursorMark is the left "border" that marks the selected row when there is
+// no styling to highlight it with
+var cursorMark = lipgloss.Border{Left: ">"}
+
+// DisableColor takes all color out of the TUI, for NO_COLOR and -no-color.
+// lipgloss renders plain text from then on, so no escape code gets through
+// whatever a style asks for; the selected row is marked with a ">" in front
+// instead of a highlight.
+func DisableColor() {
+	noColor = true
+	lipgloss.SetColorProfile(termenv.Ascii)
+	if GlobalThemeManager != nil {
+		GlobalThemeManager.applyTheme()
+	}
+}
+
 // Apply current theme to global variables
 func (tm *ThemeManager) applyTheme() {
 	t := &tm.CurrentTheme
+	if noColor {
+		t = &monoTheme
+	}
 	PrimaryColor = t.PrimaryColor()
 	SecondaryColor = t.SecondaryColor()
 	AccentColor = t.AccentColor()
@@ -291,7 +418,8 @@ func (tm *ThemeManager) applyTheme() {
 	ErrorStyle = ErrorStyle.Foreground(DangerColor)
 	SuccessStyle = SuccessStyle.Foreground(SuccessColor)
 	InfoBoxStyle = InfoBoxStyle.BorderForeground(GrayColor)
-	SelectedScanItemStyle = SelectedScanItemStyle.Background(BgSelected).Foreground(WhiteColor)
+	SelectedScanItemStyle = SelectedScanItemStyle.Background(BgSelected).Foreground(WhiteColor).
+		BorderStyle(cursorMark).BorderLeft(noColor)
 
 	// Update risk styles
 	RiskLowStyle = RiskLowStyle.Foreground(SuccessColor)
@@ -349,15 +477,57 @@ func (tm *ThemeManager) loadCurrentTheme() {
 	}
 }
 
-// loadUserThemes loads custom themes from user config directory
-func (tm *ThemeManager) loadUserThemes() {
+// userThemesDir returns ~/.config/lume/themes, or "" without a home directory
+func userThemesDir() string {
 	home := scanner.GetRealHomeDir()
 	if home == "" {
-		return
+		return ""
 	}
+	return filepath.Join(home, ".config", "lume", "themes")
+}
 
+// SaveUserTheme writes theme to ~/.config/lume/themes/<name>.json and
+// switches to it. Built-in theme names cannot be overwritten.
+func (tm *ThemeManager) SaveUserTheme(theme Theme) (string, error) {
+	if !themeNameRe.MatchString(theme.Name) {
+		return "", fmt.Errorf("theme name may only contain letters, digits, - and _")
+	}
+	if _, ok := PresetThemes[theme.Name]; ok {
+		return "", fmt.Errorf("%q is a built-in theme", theme.Name)
+	}
+	if err := theme.Validate(); err != nil {
+		return "", err
+	}
+
+	dir := userThemesDir()
+	if dir == "" {
+		return "", fmt.Errorf("cannot determine home directory")
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+
+	data, err := json.MarshalIndent(theme, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, theme.Name+".json")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return "", err
+	}
+
+	tm.AllThemes[theme.Name] = theme
+	return path, tm.SetTheme(theme.Name)
+}
+
+// loadUserThemes loads custom themes from user config directory
+func (tm *ThemeManager) loadUserThemes() {
 	// Load custom themes from ~/.config/lume/themes/
-	themesDir := filepath.Join(home, ".config", "lume", "themes")
+	themesDir := userThemesDir()
+	if themesDir == "" {
+		return
+	}
+
 	files, err := os.ReadDir(themesDir)
 	if err != nil {
 		return
@@ -376,6 +546,7 @@ func (tm *ThemeManager) loadUserThemes() {
 
 		var theme Theme
 		if err := json.Unmarshal(data, &theme); err != nil {
+			tm.Warnings = append(tm.Warnings, fmt.Sprintf("theme %s: %v", file.Name(), err))
 			continue
 		}
 
@@ -384,6 +555,8 @@ func (tm *ThemeManager) loadUserThemes() {
 		if theme.Name == "" {
 			theme.Name = name
 		}
+		def := PresetThemes["modern"]
+		tm.Warnings = append(tm.Warnings, theme.sanitize(&def)...)
 		tm.AllThemes[name] = theme
 	}
 }
@@ -0,0 +1,300 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// ThemeEditorView edits a copy of an existing theme field by field with a
+// live preview, and saves the result as ~/.config/lume/themes/<name>.json.
+// Nothing changes on screen outside the preview until the theme is saved.
+type ThemeEditorView struct {
+	draft     Theme
+	base      string // theme the draft started from
+	cursor    int    // 0 is the base theme row, 1.. the color fields
+	editing   bool
+	naming    bool
+	input     string
+	err       string
+	savedPath string
+	width     int
+	height    int
+}
+
+func NewThemeEditorView() *ThemeEditorView {
+	return &ThemeEditorView{}
+}
+
+func (m *ThemeEditorView) Init() tea.Cmd {
+	m.cursor = 0
+	m.editing = false
+	m.naming = false
+	m.err = ""
+	m.savedPath = ""
+	if GlobalThemeManager != nil {
+		m.loadBase(GlobalThemeManager.CurrentTheme.Name)
+	}
+	return nil
+}
+
+// loadBase resets the draft to a copy of the named theme
+func (m *ThemeEditorView) loadBase(name string) {
+	if GlobalThemeManager == nil {
+		return
+	}
+	if t, ok := GlobalThemeManager.AllThemes[name]; ok {
+		m.draft = t
+		m.base = name
+	}
+}
+
+// cycleBase moves the draft to the next or previous theme
+func (m *ThemeEditorView) cycleBase(delta int) {
+	if GlobalThemeManager == nil {
+		return
+	}
+	names := GlobalThemeManager.GetThemeNames()
+	if len(names) == 0 {
+		return
+	}
+	idx := 0
+	for i, name := range names {
+		if name == m.base {
+			idx = i
+			break
+		}
+	}
+	idx = (idx + delta + len(names)) % len(names)
+	m.loadBase(names[idx])
+	m.err = ""
+}
+
+func (m *ThemeEditorView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+
+	case tea.KeyMsg:
+		if m.editing || m.naming {
+			switch msg.String() {
+			case "esc":
+				m.editing = false
+				m.naming = false
+				m.err = ""
+			case "enter":
+				m.submitInput()
+			case "backspace":
+				if len(m.input) > 0 {
+					m.input = m.input[:len(m.input)-1]
+				}
+			default:
+				if msg.Type == tea.KeyRunes {
+					m.input += string(msg.Runes)
+				}
+			}
+			return m, nil
+		}
+
+		switch msg.String() {
+		case "q", "esc":
+			return m, func() tea.Msg { return BackToMenuMsg{} }
+		case "up", "k":
+			if m.cursor > 0 {
+				m.cursor--
+			}
+		case "down", "j":
+			if m.cursor < len(themeColors) {
+				m.cursor++
+			}
+		case "left", "h":
+			if m.cursor == 0 {
+				m.cycleBase(-1)
+			}
+		case "right", "l":
+			if m.cursor == 0 {
+				m.cycleBase(1)
+			}
+		case "enter", " ":
+			if m.cursor == 0 {
+				m.cycleBase(1)
+			} else {
+				m.editing = true
+				m.input = *themeColors[m.cursor-1].Get(&m.draft)
+				m.err = ""
+			}
+		case "s":
+			if err := m.draft.Validate(); err != nil {
+				m.err = err.Error()
+				break
+			}
+			m.naming = true
+			m.input = m.draft.Name
+			if _, builtin := PresetThemes[m.input]; builtin {
+				m.input += "-custom"
+			}
+			m.err = ""
+		}
+	}
+
+	return m, nil
+}
+
+// submitInput applies the color or theme name being typed
+func (m *ThemeEditorView) submitInput() {
+	value := strings.TrimSpace(m.input)
+
+	if m.editing {
+		if value != "" && !validColor(value) && !strings.HasPrefix(value, "#") {
+			value = "#" + value
+		}
+		if value != "" && !validColor(value) {
+			m.err = fmt.Sprintf("%q is not a #RGB, #RRGGBB or 0-255 color", value)
+			return
+		}
+		*themeColors[m.cursor-1].Get(&m.draft) = strings.ToLower(value)
+		m.editing = false
+		m.err = ""
+		return
+	}
+
+	if GlobalThemeManager == nil {
+		m.err = "themes are not available"
+		return
+	}
+	theme := m.draft
+	theme.Name = value
+	if theme.Description == "" || theme.Name != m.draft.Name {
+		theme.Description = value + " (custom)"
+	}
+	path, err := GlobalThemeManager.SaveUserTheme(theme)
+	if err != nil {
+		m.err = err.Error()
+		return
+	}
+	m.draft = theme
+	m.base = theme.Name
+	m.naming = false
+	m.err = ""
+	m.savedPath = path
+}
+
+func (m *ThemeEditorView) View() string {
+	if m.width == 0 {
+		return "Loading..."
+	}
+
+	var b strings.Builder
+	b.WriteString(PageHeader("", "Theme Editor", m.width))
+	b.WriteString("\n\n")
+
+	if m.savedPath != "" {
+		b.WriteString("  ")
+		b.WriteString(SuccessStyle.Render("[ok] Saved " + displayPath(m.savedPath)))
+		b.WriteString("\n\n")
+	}
+
+	baseLine := fmt.Sprintf("  %s  < %s >", padRight("Base theme", 14), m.base)
+	if m.cursor == 0 {
+		baseLine = SelectedScanItemStyle.Render(padRight(baseLine, 40))
+	}
+	b.WriteString(baseLine)
+	b.WriteString("\n\n")
+
+	for i, c := range themeColors {
+		value := *c.Get(&m.draft)
+		swatch := "  "
+		if value != "" {
+			swatch = lipgloss.NewStyle().Background(lipgloss.Color(value)).Render("  ")
+		}
+		shown := value
+		if shown == "" {
+			shown = DimStyle.Render("default")
+		}
+		if m.editing && m.cursor == i+1 {
+			shown = AccentStyle.Render(m.input + "_")
+		}
+
+		line := fmt.Sprintf("  %s  %s  %s", padRight(c.Label, 14), swatch, shown)
+		if m.cursor == i+1 && !m.editing {
+			line = SelectedScanItemStyle.Render(padRightAnsi(line, 40))
+		}
+		b.WriteString(line)
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString(m.renderPreview())
+	b.WriteString("\n")
+
+	if m.err != "" {
+		b.WriteString("\n  ")
+		b.WriteString(ErrorStyle.Render(m.err))
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	switch {
+	case m.naming:
+		b.WriteString("  Save as ~/.config/lume/themes/")
+		b.WriteString(AccentStyle.Render(m.input + "_"))
+		b.WriteString(".json\n\n")
+		b.WriteString(StyledHelpBar([]KeyHelp{{Key: "enter", Desc: "save"}, {Key: "esc", Desc: "cancel"}}))
+	case m.editing:
+		b.WriteString(StyledHelpBar([]KeyHelp{{Key: "enter", Desc: "apply"}, {Key: "esc", Desc: "cancel"}}))
+	default:
+		b.WriteString(StyledHelpBar([]KeyHelp{
+			{Key: "j/k", Desc: "navigate"},
+			{Key: "h/l", Desc: "base theme"},
+			{Key: "enter", Desc: "edit"},
+			{Key: "s", Desc: "save"},
+			{Key: "esc", Desc: "back"},
+		}))
+	}
+
+	return Center(m.width, m.height, b.String())
+}
+
+// renderPreview draws a miniature screen using only the draft's colors
+func (m *ThemeEditorView) renderPreview() string {
+	t := &m.draft
+	fg := lipgloss.NewStyle().Foreground(t.ForegroundColor())
+
+	var p strings.Builder
+	p.WriteString(lipgloss.NewStyle().Foreground(t.PrimaryColor()).Bold(true).Render("Preview"))
+	p.WriteString("  ")
+	p.WriteString(lipgloss.NewStyle().Foreground(t.LightGrayColor()).Render("subtitle text"))
+	p.WriteString("\n")
+	p.WriteString(lipgloss.NewStyle().Foreground(t.DimColor()).Render(strings.Repeat("-", 44)))
+	p.WriteString("\n")
+	p.WriteString(lipgloss.NewStyle().Background(t.SelectedBgColor()).Foreground(t.SelectedFgColor()).Bold(true).
+		Render(padRight(" > [x] Selected item           1.2 GB", 44)))
+	p.WriteString("\n")
+	p.WriteString(fg.Render("   [ ] Normal item             340 MB"))
+	p.WriteString("\n   ")
+	p.WriteString(lipgloss.NewStyle().Foreground(t.SuccessColor()).Render("Low"))
+	p.WriteString("  ")
+	p.WriteString(lipgloss.NewStyle().Foreground(t.WarningColor()).Render("Medium"))
+	p.WriteString("  ")
+	p.WriteString(lipgloss.NewStyle().Foreground(t.DangerColor()).Render("High"))
+	p.WriteString("  ")
+	p.WriteString(lipgloss.NewStyle().Foreground(t.AccentColor()).Bold(true).Render("accent"))
+	p.WriteString("  ")
+	p.WriteString(lipgloss.NewStyle().Foreground(t.SecondaryColor()).Render("secondary"))
+	p.WriteString("\n   ")
+	p.WriteString(ProgressBar(62, 30, t.DangerColor(), t.SecondaryColor()))
+	p.WriteString("\n")
+	p.WriteString(lipgloss.NewStyle().Foreground(t.GrayColor()).Render("   j/k navigate  enter select  q quit"))
+
+	box := lipgloss.NewStyle().
+		Border(lipgloss.NormalBorder()).
+		BorderForeground(t.BorderColor()).
+		Padding(0, 1)
+	if t.Background != "" {
+		box = box.Background(lipgloss.Color(t.Background))
+	}
+	return lipgloss.NewStyle().MarginLeft(2).Render(box.Render(p.String()))
+}
@@ -0,0 +1,223 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/Tyooughtul/lume/pkg/cleaner"
+)
+
+// TrashView empties the Trash. Every other view only moves files there, so
+// free space does not change until this runs. It is the one permanent delete
+// besides Time Machine snapshots, hence the confirmation.
+type TrashView struct {
+	size       int64
+	items      int
+	sizeErr    error // the Trash could not be read, usually no Full Disk Access
+	measuring  bool
+	emptying   bool
+	quitArmed  bool
+	confirming bool
+	done       bool
+	spinner    spinner.Model
+	width      int
+	height     int
+	diskBefore diskReading
+	diskAfter  diskReading
+	err        error
+}
+
+// trashSizeMsg reports what the Trash holds
+type trashSizeMsg struct {
+	size  int64
+	items int
+	err   error
+}
+
+// trashEmptiedMsg reports the result of emptying the Trash, with df readings
+// taken around it
+type trashEmptiedMsg struct {
+	before diskReading
+	after  diskReading
+	err    error
+}
+
+func NewTrashView() *TrashView {
+	s := spinner.New()
+	s.Spinner = spinner.Dot
+	s.Style = lipgloss.NewStyle().Foreground(PrimaryColor)
+
+	return &TrashView{spinner: s}
+}
+
+func (m *TrashView) Init() tea.Cmd {
+	m.confirming = false
+	m.done = false
+	m.err = nil
+	return tea.Batch(m.spinner.Tick, m.measure())
+}
+
+func (m *TrashView) measure() tea.Cmd {
+	m.measuring = true
+	return func() tea.Msg {
+		size, items, err := cleaner.NewCleaner().TrashSize()
+		return trashSizeMsg{size: size, items: items, err: err}
+	}
+}
+
+func (m *TrashView) startEmpty() tea.Cmd {
+	m.emptying = true
+	m.quitArmed = false
+	return func() tea.Msg {
+		before := sampleDisk()
+		err := cleaner.NewCleaner().EmptyTrash()
+		return trashEmptiedMsg{before: before, after: sampleDisk(), err: err}
+	}
+}
+
+// freed is how much free space emptying the Trash gave back, from df
+func (m *TrashView) freed() uint64 {
+	return diskFreed(m.diskBefore, m.diskAfter)
+}
+
+func (m *TrashView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+
+	case tea.KeyMsg:
+		if m.confirming {
+			if done, confirmed := confirmKey(msg); done {
+				m.confirming = false
+				if confirmed {
+					return m, m.startEmpty()
+				}
+			}
+			return m, nil
+		}
+
+		if m.emptying {
+			return m, busyKey(msg, &m.quitArmed)
+		}
+
+		switch msg.String() {
+		case "q", "ctrl+c":
+			return m, tea.Quit
+		case "esc":
+			return m, func() tea.Msg { return BackToMenuMsg{} }
+		case "d", "c", "enter":
+			if !m.measuring && (m.items > 0 || m.sizeErr != nil) {
+				m.confirming = true
+			}
+		case "f":
+			if m.sizeErr != nil {
+				return m, openFullDiskAccessSettings()
+			}
+		case "r":
+			if !m.measuring {
+				m.done = false
+				return m, m.measure()
+			}
+		}
+
+	case finderResultMsg:
+		m.err = msg.err
+		return m, nil
+
+	case trashSizeMsg:
+		m.measuring = false
+		m.size, m.items, m.sizeErr = msg.size, msg.items, msg.err
+		return m, nil
+
+	case trashEmptiedMsg:
+		m.emptying = false
+		m.err = msg.err
+		if msg.err == nil {
+			m.done = true
+			m.diskBefore, m.diskAfter = msg.before, msg.after
+			details := fmt.Sprintf("Emptied Trash, %s freed", FormatBytes(m.freed()))
+			return m, tea.Batch(m.measure(), RecordSnapshot(msg.after.total, msg.after.used, 0, "empty_trash", details))
+		}
+		return m, m.measure()
+	}
+
+	var cmd tea.Cmd
+	m.spinner, cmd = m.spinner.Update(msg)
+	return m, cmd
+}
+
+func (m *TrashView) View() string {
+	if m.width == 0 {
+		return "Loading..."
+	}
+
+	var b strings.Builder
+	b.WriteString(PageHeader("", "Empty Trash", m.width))
+	b.WriteString("\n\n")
+
+	if m.emptying {
+		b.WriteString(fmt.Sprintf("  %s Emptying the Trash...\n", m.spinner.View()))
+		b.WriteString(busyHint(m.quitArmed))
+		return Center(m.width, m.height, b.String())
+	}
+
+	if m.err != nil {
+		b.WriteString("  ")
+		b.WriteString(ErrorStyle.Render(fmt.Sprintf("Error: %v", m.err)))
+		b.WriteString("\n\n")
+	}
+
+	if m.done {
+		b.WriteString("  ")
+		b.WriteString(SuccessStyle.Render(fmt.Sprintf("[ok] Trash emptied, %s freed", FormatBytes(m.freed()))))
+		b.WriteString("\n")
+		b.WriteString(DiskBeforeAfter(m.diskBefore, m.diskAfter, 30))
+		b.WriteString("\n")
+	}
+
+	switch {
+	case m.measuring:
+		b.WriteString(fmt.Sprintf("  %s Measuring the Trash...\n", m.spinner.View()))
+	case m.sizeErr != nil:
+		b.WriteString("  ")
+		b.WriteString(WarningStyle.Render("The Trash could not be read (lume may need Full Disk Access)."))
+		b.WriteString("\n  ")
+		b.WriteString(DimStyle.Render("It can still be emptied through Finder; press f to grant access in System Settings."))
+		b.WriteString("\n")
+	case m.items == 0:
+		b.WriteString("  The Trash is empty.\n")
+	default:
+		b.WriteString(fmt.Sprintf("  The Trash holds %s in %d items.\n", AccentStyle.Render(FormatBytes(uint64(m.size))), m.items))
+		b.WriteString("  ")
+		b.WriteString(DimStyle.Render("Files lume cleans go here; the space comes back once it is emptied."))
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	if m.confirming {
+		question := "Permanently delete everything in the Trash?"
+		if m.sizeErr == nil {
+			question = fmt.Sprintf("Permanently delete %d items (%s) in the Trash?", m.items, FormatBytes(uint64(m.size)))
+		}
+		b.WriteString(WarningStyle.Render("! This cannot be undone. Trash on other volumes is emptied too."))
+		b.WriteString("\n\n")
+		b.WriteString(ConfirmDialog(question))
+	} else {
+		help := []KeyHelp{
+			{Key: "d", Desc: "empty Trash"},
+			{Key: "r", Desc: "refresh"},
+		}
+		if m.sizeErr != nil {
+			help = append(help, KeyHelp{Key: "f", Desc: "Full Disk Access"})
+		}
+		help = append(help, KeyHelp{Key: "esc", Desc: "back"})
+		b.WriteString(StyledHelpBar(help))
+	}
+
+	return Center(m.width, m.height, b.String())
+}
@@ -0,0 +1,29 @@
+package ui
+
+import "github.com/dustin/go-humanize"
+
+// binaryUnits shows sizes in powers of 1024 (GiB, as df -h does) rather
+// than SI units (GB, as Finder does), see SetBinaryUnits
+var binaryUnits bool
+
+// SetBinaryUnits chooses binary (GiB) or SI (GB) units for every size lume
+// shows, the TUI and the command-line reports alike
+func SetBinaryUnits(binary bool) {
+	binaryUnits = binary
+}
+
+// FormatBytes formats a size in bytes in the chosen units
+func FormatBytes(n uint64) string {
+	if binaryUnits {
+		return humanize.IBytes(n)
+	}
+	return humanize.Bytes(n)
+}
+
+// unitsName describes the chosen units for the main menu
+func unitsName() string {
+	if binaryUnits {
+		return "GiB"
+	}
+	return "GB"
+}
@@ -1,7 +1,9 @@
 package ui
 
 import (
+	"context"
 	"fmt"
+	"os"
 	"path/filepath"
 	"strings"
 	"time"
@@ -9,7 +11,6 @@ import (
 	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
-	"github.com/dustin/go-humanize"
 
 	"github.com/Tyooughtul/lume/pkg/cleaner"
 	"github.com/Tyooughtul/lume/pkg/scanner"
@@ -22,6 +23,7 @@ type ZombieHunterView struct {
 	scrollOffset int
 	scanning     bool
 	cleaning     bool
+	quitArmed    bool
 	confirming   bool
 	width        int
 	height       int
@@ -29,11 +31,17 @@ type ZombieHunterView struct {
 	rootPath     string
 	minSize      int64
 	resultCh     chan zombieResult
+	cancelScan   context.CancelFunc
 	cleanCh      chan cleanResultMsg
 	err          error
 	selectedTab  int // 0=Heatmap, 1=Zombie Files, 2=Hot Files
+	byModTime    bool // Age files by modification time even if atime works
 	selected     map[int]bool
 	cleanedSize  int64
+	diskBefore   diskReading
+	diskAfter    diskReading
+	exportPath   string
+	exportErr    error
 }
 
 type zombieResult struct {
@@ -79,11 +87,19 @@ func (m *ZombieHunterView) Init() tea.Cmd {
 func (m *ZombieHunterView) startScan() tea.Cmd {
 	m.scanning = true
 	m.result = nil
+	m.exportPath, m.exportErr = "", nil
+	ctx := newScanContext(&m.cancelScan)
 
 	go func() {
 		s := scanner.NewZombieHunterScanner(m.rootPath)
 		s.SetMinSize(m.minSize)
-		result, err := s.Scan(nil)
+		var result *scanner.ZombieHunterResult
+		var err error
+		if m.byModTime {
+			result, err = s.ScanByModTimeContext(ctx, nil)
+		} else {
+			result, err = s.ScanContext(ctx, nil)
+		}
 		m.resultCh <- zombieResult{result: result, err: err}
 	}()
 
@@ -94,24 +110,25 @@ func (m *ZombieHunterView) startScan() tea.Cmd {
 
 func (m *ZombieHunterView) startClean() tea.Cmd {
 	m.cleaning = true
+	m.quitArmed = false
 
 	go func() {
 		c := cleaner.NewCleaner()
+		before := sampleDisk()
 		var files []scanner.FileInfo
-		if stat, ok := m.result.Stats[scanner.RangeZombie]; ok {
-			for i, f := range stat.Files {
-				if m.selected[i] {
-					files = append(files, scanner.FileInfo{
-						Path: f.Path,
-						Name: f.Name,
-						Size: f.Size,
-					})
-				}
+		for i, f := range m.zombieFiles() {
+			if m.selected[i] {
+				files = append(files, scanner.FileInfo{
+					Path:     f.Path,
+					Name:     f.Name,
+					Size:     f.Size,
+					Modified: f.ModTime,
+				})
 			}
 		}
 		size, err := c.CleanFiles(files, nil)
 		details := fmt.Sprintf("%d zombie files", len(files))
-		m.cleanCh <- cleanResultMsg{size: size, err: err, details: details}
+		m.cleanCh <- cleanResultMsg{size: size, err: err, details: details, before: before, after: sampleDisk()}
 	}()
 
 	return func() tea.Msg {
@@ -119,6 +136,17 @@ func (m *ZombieHunterView) startClean() tea.Cmd {
 	}
 }
 
+// zombieFiles returns the files listed on the Zombie Files tab
+func (m *ZombieHunterView) zombieFiles() []scanner.ZombieFileInfo {
+	if m.result == nil {
+		return nil
+	}
+	if stat, ok := m.result.Stats[scanner.RangeZombie]; ok {
+		return stat.Files
+	}
+	return nil
+}
+
 func (m *ZombieHunterView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
@@ -128,21 +156,26 @@ func (m *ZombieHunterView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case tea.KeyMsg:
 		if m.confirming {
-			switch msg.String() {
-			case "y", "Y":
-				m.confirming = false
-				return m, m.startClean()
-			case "n", "N", "esc":
+			if done, confirmed := confirmKey(msg); done {
 				m.confirming = false
+				if confirmed {
+					return m, m.startClean()
+				}
 			}
 			return m, nil
 		}
 
-		if m.scanning || m.cleaning {
+		if m.cleaning {
+			return m, busyKey(msg, &m.quitArmed)
+		}
+
+		if m.scanning {
 			switch msg.String() {
 			case "q", "ctrl+c":
+				stopScan(&m.cancelScan)
 				return m, tea.Quit
 			case "esc":
+				stopScan(&m.cancelScan)
 				return m, func() tea.Msg { return BackToMenuMsg{} }
 			}
 			return m, nil
@@ -173,11 +206,11 @@ func (m *ZombieHunterView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			m.updateScrollOffset()
 		case " ":
-			if m.selectedTab == 1 { // Zombie Files tab
+			if m.selectedTab == 1 && m.cursor < len(m.zombieFiles()) { // Zombie Files tab
 				m.selected[m.cursor] = !m.selected[m.cursor]
 			}
 		case "a":
-			if m.selectedTab == 1 {
+			if m.selectedTab == 1 && m.result != nil {
 				if stat, ok := m.result.Stats[scanner.RangeZombie]; ok {
 					allSelected := true
 					for i := range stat.Files {
@@ -207,6 +240,14 @@ func (m *ZombieHunterView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					m.confirming = true
 				}
 			}
+		case "m":
+			m.byModTime = !m.byModTime
+			m.selected = make(map[int]bool)
+			return m, m.startScan()
+		case "x":
+			if m.result != nil {
+				m.exportPath, m.exportErr = m.exportCSV()
+			}
 		case "r":
 			m.selected = make(map[int]bool)
 			return m, m.startScan()
@@ -229,6 +270,10 @@ func (m *ZombieHunterView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 	case zombieResult:
+		if scanCanceled(msg.err) {
+			// A scan stopped by leaving the view; a newer one may be running
+			return m, nil
+		}
 		m.scanning = false
 		m.result = msg.result
 		m.err = msg.err
@@ -241,8 +286,9 @@ func (m *ZombieHunterView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.err = msg.err
 		if msg.size > 0 {
 			m.cleanedSize = msg.size
+			m.diskBefore, m.diskAfter = msg.before, msg.after
 			m.selected = make(map[int]bool)
-			return m, tea.Batch(m.startScan(), RecordSnapshot(0, 0, msg.size, "zombie_hunter", msg.details))
+			return m, tea.Batch(m.startScan(), RecordSnapshot(msg.after.total, msg.after.used, msg.size, "zombie_hunter", msg.details))
 		}
 		return m, m.startScan()
 
@@ -323,8 +369,8 @@ func (m *ZombieHunterView) View() string {
 
 		titleLine := lipgloss.NewStyle().Foreground(AccentColor).Bold(true).Render("Zombie Hunter")
 		spinnerLine := fmt.Sprintf("%s  Scanning file access times...", m.spinner.View())
-		pathLine := DimStyle.Render(fmt.Sprintf("Path: %s", m.rootPath))
-		sizeLine := DimStyle.Render(fmt.Sprintf("Min size: %s", humanize.Bytes(uint64(m.minSize))))
+		pathLine := DimStyle.Render(fmt.Sprintf("Path: %s", displayPath(m.rootPath)))
+		sizeLine := DimStyle.Render(fmt.Sprintf("Min size: %s", FormatBytes(uint64(m.minSize))))
 
 		boxContent := fmt.Sprintf("%s\n\n%s\n\n%s\n%s", titleLine, spinnerLine, pathLine, sizeLine)
 		b.WriteString(scanBox.Render(boxContent))
@@ -346,6 +392,7 @@ func (m *ZombieHunterView) View() string {
 		boxContent := fmt.Sprintf("%s\n\n%s", titleLine, spinnerLine)
 		b.WriteString(cleanBox.Render(boxContent))
 		b.WriteString("\n")
+		b.WriteString(busyHint(m.quitArmed))
 		return Center(m.width, m.height, b.String())
 	}
 
@@ -359,6 +406,24 @@ func (m *ZombieHunterView) View() string {
 		return Center(m.width, m.height, b.String())
 	}
 
+	if m.cleanedSize > 0 {
+		b.WriteString("  ")
+		b.WriteString(SuccessStyle.Render(fmt.Sprintf("[ok] Cleaned %s", FormatBytes(uint64(m.cleanedSize)))))
+		b.WriteString("\n")
+		b.WriteString(DiskBeforeAfter(m.diskBefore, m.diskAfter, 30))
+		b.WriteString("\n")
+	}
+
+	if m.exportErr != nil {
+		b.WriteString("  ")
+		b.WriteString(ErrorStyle.Render(fmt.Sprintf("Export failed: %v", m.exportErr)))
+		b.WriteString("\n\n")
+	} else if m.exportPath != "" {
+		b.WriteString("  ")
+		b.WriteString(SuccessStyle.Render("[ok] Exported to " + displayPath(m.exportPath)))
+		b.WriteString("\n\n")
+	}
+
 	// Tab bar
 	b.WriteString(m.renderTabs())
 	b.WriteString("\n\n")
@@ -378,25 +443,18 @@ func (m *ZombieHunterView) View() string {
 	if m.confirming {
 		selectedSize := int64(0)
 		selectedCount := 0
+		var paths []string
 		if stat, ok := m.result.Stats[scanner.RangeZombie]; ok {
 			for i, f := range stat.Files {
 				if m.selected[i] {
 					selectedSize += f.Size
 					selectedCount++
+					paths = append(paths, f.Path)
 				}
 			}
 		}
-		confirmBox := lipgloss.NewStyle().
-			Border(lipgloss.RoundedBorder()).
-			BorderForeground(WarningColor).
-			Padding(0, 2)
-		confirmContent := WarningStyle.Render(fmt.Sprintf("Move %d zombie files (%s) to Trash?", selectedCount, humanize.Bytes(uint64(selectedSize))))
-		b.WriteString(confirmBox.Render(confirmContent))
-		b.WriteString("\n\n")
-		b.WriteString(StyledHelpBar([]KeyHelp{
-			{Key: "y", Desc: "confirm"},
-			{Key: "n/esc", Desc: "cancel"},
-		}))
+		b.WriteString(syncedDataWarning(paths))
+		b.WriteString(ConfirmDialog(fmt.Sprintf("Move %d zombie files (%s) to Trash?", selectedCount, FormatBytes(uint64(selectedSize)))))
 	} else if m.selectedTab == 1 {
 		b.WriteString(StyledHelpBar([]KeyHelp{
 			{Key: "tab/h/l", Desc: "switch view"},
@@ -404,12 +462,15 @@ func (m *ZombieHunterView) View() string {
 			{Key: "space", Desc: "toggle"},
 			{Key: "a", Desc: "all"},
 			{Key: "d", Desc: "clean"},
+			{Key: "x", Desc: "export"},
 			{Key: "r", Desc: "refresh"},
 		}))
 	} else {
 		b.WriteString(StyledHelpBar([]KeyHelp{
 			{Key: "tab/h/l", Desc: "switch view"},
 			{Key: "j/k", Desc: "navigate"},
+			{Key: "m", Desc: "atime/mtime"},
+			{Key: "x", Desc: "export"},
 			{Key: "r", Desc: "refresh"},
 			{Key: "esc", Desc: "back"},
 		}))
@@ -418,6 +479,22 @@ func (m *ZombieHunterView) View() string {
 	return Center(m.width, m.height, b.String())
 }
 
+// exportCSV writes the scan result to ~/lume-zombies-<timestamp>.csv
+func (m *ZombieHunterView) exportCSV() (string, error) {
+	name := fmt.Sprintf("lume-zombies-%s.csv", time.Now().Format("20060102-150405"))
+	path := filepath.Join(scanner.GetRealHomeDir(), name)
+
+	f, err := os.Create(path)
+	if err != nil {
+		return "", err
+	}
+	if err := m.result.WriteCSV(f); err != nil {
+		f.Close()
+		return "", err
+	}
+	return path, f.Close()
+}
+
 func (m *ZombieHunterView) renderTabs() string {
 	tabs := []struct {
 		icon  string
@@ -467,11 +544,11 @@ func (m *ZombieHunterView) renderHeatmap() string {
 
 	totalCard := cardStyle.Copy().BorderForeground(PrimaryColor).Render(
 		lipgloss.NewStyle().Foreground(GrayColor).Render("Total Scanned") + "\n" +
-			lipgloss.NewStyle().Foreground(PrimaryColor).Bold(true).Render(humanize.Bytes(uint64(totalSize))))
+			lipgloss.NewStyle().Foreground(PrimaryColor).Bold(true).Render(FormatBytes(uint64(totalSize))))
 
 	zombieCard := cardStyle.Copy().BorderForeground(lipgloss.Color(scanner.RangeZombie.Color())).Render(
 		lipgloss.NewStyle().Foreground(GrayColor).Render("Zombie Files") + "\n" +
-			lipgloss.NewStyle().Foreground(lipgloss.Color(scanner.RangeZombie.Color())).Bold(true).Render(humanize.Bytes(uint64(zombieSize))))
+			lipgloss.NewStyle().Foreground(lipgloss.Color(scanner.RangeZombie.Color())).Bold(true).Render(FormatBytes(uint64(zombieSize))))
 
 	pctLabel := fmt.Sprintf("%.1f%%", zombiePercent)
 	pctColor := SecondaryColor
@@ -490,7 +567,14 @@ func (m *ZombieHunterView) renderHeatmap() string {
 	// Heatmap blocks
 	blocks := m.getHeatmapBlocks()
 	if len(blocks) > 0 {
-		b.WriteString("  " + TitleStyle.Render("File Activity Heatmap") + "\n\n")
+		basis := "by access time"
+		if m.result.ByModTime {
+			basis = "by modification time"
+			if !m.byModTime {
+				basis += " - access times are not tracked on this volume"
+			}
+		}
+		b.WriteString("  " + TitleStyle.Render("File Activity Heatmap") + " " + DimStyle.Render("("+basis+")") + "\n\n")
 
 		// Stacked bar (overview)
 		b.WriteString(m.renderStackedBar(blocks))
@@ -602,7 +686,7 @@ func (m *ZombieHunterView) renderHeatmapBlock(block heatmapBlock) string {
 	filledBar := lipgloss.NewStyle().Foreground(block.Color).Render(strings.Repeat("█", filled))
 	emptyBar := DimStyle.Render(strings.Repeat("░", barWidth-filled))
 
-	sizeStr := lipgloss.NewStyle().Foreground(block.Color).Bold(true).Render(humanize.Bytes(uint64(block.Size)))
+	sizeStr := lipgloss.NewStyle().Foreground(block.Color).Bold(true).Render(FormatBytes(uint64(block.Size)))
 	countStr := lipgloss.NewStyle().Foreground(GrayColor).Render(fmt.Sprintf("%d files", block.Count))
 	label := lipgloss.NewStyle().Foreground(block.Color).Render(fmt.Sprintf("%-18s", block.Label))
 	pctStr := lipgloss.NewStyle().Foreground(LightGrayColor).Render(fmt.Sprintf("%5.1f%%", block.Percent))
@@ -683,12 +767,12 @@ func (m *ZombieHunterView) renderZombieList() string {
 			lipgloss.NewStyle().Foreground(GrayColor).Render("Total: ") +
 				lipgloss.NewStyle().Foreground(WhiteColor).Bold(true).Render(fmt.Sprintf("%d files", len(stat.Files))),
 			lipgloss.NewStyle().Foreground(GrayColor).Render("Size: ") +
-				lipgloss.NewStyle().Foreground(WarningColor).Bold(true).Render(humanize.Bytes(uint64(stat.TotalSize))),
+				lipgloss.NewStyle().Foreground(WarningColor).Bold(true).Render(FormatBytes(uint64(stat.TotalSize))),
 		}
 		if selectedCount > 0 {
 			statParts = append(statParts,
 				lipgloss.NewStyle().Foreground(GrayColor).Render("Selected: ") +
-					lipgloss.NewStyle().Foreground(SecondaryColor).Bold(true).Render(fmt.Sprintf("%s (%d)", humanize.Bytes(uint64(selectedSize)), selectedCount)))
+					lipgloss.NewStyle().Foreground(SecondaryColor).Bold(true).Render(fmt.Sprintf("%s (%d)", FormatBytes(uint64(selectedSize)), selectedCount)))
 		}
 		b.WriteString("  " + strings.Join(statParts, DimStyle.Render("  |  ")))
 	}
@@ -759,7 +843,7 @@ func (m *ZombieHunterView) renderHotFiles() string {
 
 func (m *ZombieHunterView) formatFileLine(file scanner.ZombieFileInfo, selected bool) string {
 	name := truncate(filepath.Base(file.Path), 40)
-	size := padLeft(humanize.Bytes(uint64(file.Size)), 12)
+	size := padLeft(FormatBytes(uint64(file.Size)), 12)
 	accessStr, accessStyle := m.formatAccessTimeStyled(file)
 
 	line := fmt.Sprintf("  %s %s %s", name, size, accessStyle.Render(accessStr))
@@ -772,7 +856,7 @@ func (m *ZombieHunterView) formatFileLine(file scanner.ZombieFileInfo, selected
 
 func (m *ZombieHunterView) formatFileLineWithCb(file scanner.ZombieFileInfo, cb string, selected bool) string {
 	name := truncate(filepath.Base(file.Path), 36)
-	size := padLeft(humanize.Bytes(uint64(file.Size)), 12)
+	size := padLeft(FormatBytes(uint64(file.Size)), 12)
 	accessStr, accessStyle := m.formatAccessTimeStyled(file)
 
 	line := fmt.Sprintf("  %s %s %s %s", cb, name, size, accessStyle.Render(accessStr))
@@ -784,7 +868,7 @@ func (m *ZombieHunterView) formatFileLineWithCb(file scanner.ZombieFileInfo, cb
 }
 
 func (m *ZombieHunterView) formatAccessTimeStyled(file scanner.ZombieFileInfo) (string, lipgloss.Style) {
-	days := int(time.Since(file.AccessTime).Hours() / 24)
+	days := int(time.Since(m.result.AgeOf(file)).Hours() / 24)
 	if days < 0 {
 		days = 0
 	}